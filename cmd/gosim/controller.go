@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/controller"
+)
+
+var directions = []common.Direction{common.Left, common.Right, common.Up, common.Down}
+
+// newController returns the named controller for the game of the given seed,
+// the bots of the controller package are there besides random and scripted
+func newController(name string, script []common.Direction, seed int64) (controller.Controller, error) {
+	switch name {
+	case "random":
+		return &randomController{random: rand.New(rand.NewSource(seed))}, nil
+	case "scripted":
+		return &scriptedController{script: script}, nil
+	}
+
+	return controller.New(name)
+}
+
+// parseScript reads directions written as L, R, U and D
+func parseScript(script string) (directions []common.Direction, err error) {
+	letters := map[rune]common.Direction{
+		'L': common.Left,
+		'R': common.Right,
+		'U': common.Up,
+		'D': common.Down,
+	}
+	for _, letter := range script {
+		direction, ok := letters[letter]
+		if !ok {
+			return nil, fmt.Errorf("unknown direction %q in the script", letter)
+		}
+		directions = append(directions, direction)
+	}
+	if len(directions) == 0 {
+		return nil, fmt.Errorf("empty script")
+	}
+
+	return directions, nil
+}
+
+// randomController turns in a random direction one round out of four
+type randomController struct {
+	random *rand.Rand
+}
+
+func (aController *randomController) Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool) {
+	if aController.random.Intn(4) != 0 {
+		return direction, false
+	}
+
+	return directions[aController.random.Intn(len(directions))], true
+}
+
+// scriptedController cycles through a list of directions, one per round
+type scriptedController struct {
+	script []common.Direction
+	index  int
+}
+
+func (aController *scriptedController) Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool) {
+	direction = aController.script[aController.index%len(aController.script)]
+	aController.index++
+
+	return direction, true
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseScript(t *testing.T) {
+	directions, err := parseScript("LRUD")
+	require.NoError(t, err)
+	require.Equal(t, []common.Direction{common.Left, common.Right, common.Up, common.Down}, directions)
+	_, err = parseScript("")
+	require.Error(t, err)
+	_, err = parseScript("RX")
+	require.Error(t, err)
+}
+
+func Test_simulate(t *testing.T) {
+	script, err := parseScript("RRRRD")
+	require.NoError(t, err)
+	for _, name := range []string{"random", "scripted", "greedy", "path", "tail", "hamiltonian"} {
+		t.Run(name, func(t *testing.T) {
+			aConfig := config{
+				size:       common.Size{Width: 10, Height: 10},
+				options:    []gamestate.Option{gamestate.WithEdgePolicy(common.UniformEdges(common.WallEdge))},
+				controller: name,
+				script:     script,
+				maxRounds:  500,
+			}
+			aResult, err := simulate(aConfig, 3)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), aResult.Seed)
+			require.True(t, aResult.Rounds > 0 && aResult.Rounds <= aConfig.maxRounds)
+			require.NotEmpty(t, aResult.Death)
+
+			// A seed always plays the same game
+			again, err := simulate(aConfig, 3)
+			require.NoError(t, err)
+			require.Equal(t, aResult, again)
+		})
+	}
+	_, err = simulate(config{controller: "human"}, 1)
+	require.Error(t, err)
+}
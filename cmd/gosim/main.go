@@ -0,0 +1,137 @@
+// Command gosim plays many games headless with a controller steering the
+// snake and reports the distributions of the score, the snake length, the
+// rounds survived and the causes of death as CSV or JSON.
+// Game i is seeded with seed+i so a run can be reproduced.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/controller"
+)
+
+// config is what every game of a run shares
+type config struct {
+	size       common.Size
+	options    []gamestate.Option
+	controller string
+	script     []common.Direction
+	maxRounds  int
+}
+
+func main() {
+	games := flag.Int("games", 1000, "number of games")
+	seed := flag.Int64("seed", 1, "seed of the first game, the next games use the following seeds")
+	width := flag.Int("width", 20, "board width")
+	height := flag.Int("height", 20, "board height")
+	horizontal := flag.String("horizontal", "wrap", "left and right edges: wrap, wall or bounce")
+	vertical := flag.String("vertical", "wrap", "top and bottom edges: wrap, wall or bounce")
+	candies := flag.Int("candies", 1, "number of candies on the board")
+	controllerName := flag.String("controller", "random", "what steers the snake: random, scripted, greedy, path, tail or hamiltonian")
+	script := flag.String("script", "RRRRD", "directions cycled by the scripted controller, from L, R, U and D")
+	maxRounds := flag.Int("max-rounds", 10000, "rounds after which a game is stopped")
+	format := flag.String("format", "csv", "output format: csv or json")
+	report := flag.String("report", "summary", "what is written: summary or games")
+	flag.Parse()
+
+	edgePolicy, err := common.ParseEdgePolicy(*horizontal, *vertical)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	directions, err := parseScript(*script)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if _, err = newController(*controllerName, directions, 0); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	aConfig := config{
+		size: common.Size{Width: *width, Height: *height},
+		options: []gamestate.Option{
+			gamestate.WithEdgePolicy(edgePolicy),
+			gamestate.WithCandyRule(common.CandyRule{Count: *candies}),
+		},
+		controller: *controllerName,
+		script:     directions,
+		maxRounds:  *maxRounds,
+	}
+
+	results := make([]result, 0, *games)
+	failed := false
+	for i := 0; i < *games; i++ {
+		aResult, err := simulate(aConfig, *seed+int64(i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "seed %d: %v\n", aResult.Seed, err)
+			failed = true
+		}
+		results = append(results, aResult)
+	}
+	if err = write(os.Stdout, results, *format, *report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// simulate plays the game of the given seed until the snake dies
+// or the round limit is reached
+func simulate(aConfig config, seed int64) (aResult result, err error) {
+	aResult.Seed = seed
+	game := gamestate.New(append(aConfig.options, gamestate.WithSeed(seed))...)
+	aController, err := newController(aConfig.controller, aConfig.script, seed)
+	if err != nil {
+		return aResult, err
+	}
+	if err = game.InitBoard(aConfig.size); err != nil {
+		return aResult, err
+	}
+	if _, err = game.CreateObjects(); err != nil {
+		return aResult, err
+	}
+	game.Start()
+	for game.GameInProgress() && game.Round() < aConfig.maxRounds {
+		controller.Steer(game, 0, aController)
+		if _, err = game.Play(); err != nil {
+			aResult.Death = "error"
+			break
+		}
+	}
+
+	aResult.Score = game.Score()
+	aResult.Rounds = game.Round()
+	aResult.Length, _ = game.SnakeSize()
+	if aResult.Death == "" {
+		aResult.Death = game.GameOverReason().String()
+		if game.GameInProgress() {
+			aResult.Death = maxRoundsDeath
+		}
+	}
+
+	return aResult, err
+}
+
+// write outputs the results of every game or their summary
+func write(w io.Writer, results []result, format, report string) error {
+	switch {
+	case format == "csv" && report == "games":
+		return writeResultsCSV(w, results)
+	case format == "csv" && report == "summary":
+		return writeSummaryCSV(w, summarize(results))
+	case format == "json" && report == "games":
+		return writeJSON(w, results)
+	case format == "json" && report == "summary":
+		return writeJSON(w, summarize(results))
+	}
+
+	return fmt.Errorf("unknown format %q or report %q", format, report)
+}
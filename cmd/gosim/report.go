@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// maxRoundsDeath is the death of a game stopped by the round limit
+const maxRoundsDeath = "max rounds"
+
+// result is the outcome of a game
+type result struct {
+	Seed   int64  `json:"seed"`
+	Score  int    `json:"score"`
+	Length int    `json:"length"`
+	Rounds int    `json:"rounds"`
+	Death  string `json:"death"`
+}
+
+// distribution sums up the values of a metric over the games
+type distribution struct {
+	Min  int     `json:"min"`
+	P50  int     `json:"p50"`
+	P90  int     `json:"p90"`
+	P99  int     `json:"p99"`
+	Max  int     `json:"max"`
+	Mean float64 `json:"mean"`
+}
+
+// summary sums up a run, Deaths counts the games by cause of death
+type summary struct {
+	Games  int            `json:"games"`
+	Score  distribution   `json:"score"`
+	Length distribution   `json:"length"`
+	Rounds distribution   `json:"rounds"`
+	Deaths map[string]int `json:"deaths"`
+}
+
+// summarize returns the distributions of the results
+func summarize(results []result) summary {
+	aSummary := summary{
+		Games:  len(results),
+		Deaths: make(map[string]int),
+	}
+	scores := make([]int, len(results))
+	lengths := make([]int, len(results))
+	rounds := make([]int, len(results))
+	for i, aResult := range results {
+		scores[i] = aResult.Score
+		lengths[i] = aResult.Length
+		rounds[i] = aResult.Rounds
+		aSummary.Deaths[aResult.Death]++
+	}
+	aSummary.Score = distributionOf(scores)
+	aSummary.Length = distributionOf(lengths)
+	aSummary.Rounds = distributionOf(rounds)
+
+	return aSummary
+}
+
+// distributionOf sorts values and returns their distribution,
+// the percentiles are the nearest rank ones
+func distributionOf(values []int) (aDistribution distribution) {
+	if len(values) == 0 {
+		return aDistribution
+	}
+	sort.Ints(values)
+	sum := 0
+	for _, value := range values {
+		sum += value
+	}
+	percentile := func(p int) int {
+		rank := (p*len(values) + 99) / 100
+		return values[rank-1]
+	}
+
+	return distribution{
+		Min:  values[0],
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P99:  percentile(99),
+		Max:  values[len(values)-1],
+		Mean: float64(sum) / float64(len(values)),
+	}
+}
+
+// writeResultsCSV writes a row per game
+func writeResultsCSV(w io.Writer, results []result) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"seed", "score", "length", "rounds", "death"})
+	for _, aResult := range results {
+		_ = writer.Write([]string{
+			strconv.FormatInt(aResult.Seed, 10),
+			strconv.Itoa(aResult.Score),
+			strconv.Itoa(aResult.Length),
+			strconv.Itoa(aResult.Rounds),
+			aResult.Death,
+		})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// writeSummaryCSV writes a row per metric then a row per cause of death
+func writeSummaryCSV(w io.Writer, aSummary summary) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"metric", "min", "p50", "p90", "p99", "max", "mean"})
+	for _, metric := range []struct {
+		name         string
+		distribution distribution
+	}{
+		{"score", aSummary.Score},
+		{"length", aSummary.Length},
+		{"rounds", aSummary.Rounds},
+	} {
+		aDistribution := metric.distribution
+		_ = writer.Write([]string{
+			metric.name,
+			strconv.Itoa(aDistribution.Min),
+			strconv.Itoa(aDistribution.P50),
+			strconv.Itoa(aDistribution.P90),
+			strconv.Itoa(aDistribution.P99),
+			strconv.Itoa(aDistribution.Max),
+			strconv.FormatFloat(aDistribution.Mean, 'f', 2, 64),
+		})
+	}
+
+	deaths := make([]string, 0, len(aSummary.Deaths))
+	for death := range aSummary.Deaths {
+		deaths = append(deaths, death)
+	}
+	sort.Strings(deaths)
+	_ = writer.Write([]string{"death", "games"})
+	for _, death := range deaths {
+		_ = writer.Write([]string{death, strconv.Itoa(aSummary.Deaths[death])})
+	}
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// writeJSON writes value as indented JSON
+func writeJSON(w io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
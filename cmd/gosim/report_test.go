@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_distributionOf(t *testing.T) {
+	tests := []struct {
+		name             string
+		values           []int
+		wantDistribution distribution
+	}{
+		{
+			name:             "TestEmpty",
+			values:           nil,
+			wantDistribution: distribution{},
+		},
+		{
+			name:   "TestOneValue",
+			values: []int{7},
+			wantDistribution: distribution{
+				Min:  7,
+				P50:  7,
+				P90:  7,
+				P99:  7,
+				Max:  7,
+				Mean: 7,
+			},
+		},
+		{
+			name:   "TestTenValues",
+			values: []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+			wantDistribution: distribution{
+				Min:  1,
+				P50:  5,
+				P90:  9,
+				P99:  10,
+				Max:  10,
+				Mean: 5.5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantDistribution, distributionOf(tt.values))
+		})
+	}
+}
+
+func Test_summarize(t *testing.T) {
+	results := []result{
+		{Seed: 1, Score: 2, Length: 3, Rounds: 40, Death: "self collision"},
+		{Seed: 2, Score: 4, Length: 5, Rounds: 60, Death: maxRoundsDeath},
+		{Seed: 3, Score: 0, Length: 1, Rounds: 10, Death: "self collision"},
+	}
+	aSummary := summarize(results)
+	require.Equal(t, 3, aSummary.Games)
+	require.Equal(t, map[string]int{"self collision": 2, maxRoundsDeath: 1}, aSummary.Deaths)
+	require.Equal(t, 2.0, aSummary.Score.Mean)
+	require.Equal(t, 60, aSummary.Rounds.Max)
+
+	var buffer bytes.Buffer
+	require.NoError(t, writeSummaryCSV(&buffer, aSummary))
+	require.Equal(t, `metric,min,p50,p90,p99,max,mean
+score,0,2,4,4,4,2.00
+length,1,3,5,5,5,3.00
+rounds,10,40,60,60,60,36.67
+death,games
+max rounds,1
+self collision,2
+`, buffer.String())
+
+	buffer.Reset()
+	require.NoError(t, writeResultsCSV(&buffer, results[:1]))
+	require.Equal(t, "seed,score,length,rounds,death\n1,2,3,40,self collision\n", buffer.String())
+}
@@ -0,0 +1,56 @@
+package main
+
+// key is a decoded keyboard command
+type key int
+
+// Commands understood by the client
+const (
+	keyNone key = iota
+	keyLeft
+	keyRight
+	keyUp
+	keyDown
+	keyRestart
+	keyPause
+	keyQuit
+)
+
+// parseKeys decodes the bytes read from the terminal.
+// Arrow keys are sent as the escape sequences ESC [ A..D
+func parseKeys(buffer []byte) (keys []key) {
+	for i := 0; i < len(buffer); i++ {
+		if buffer[i] == 0x1b && i+2 < len(buffer) && buffer[i+1] == '[' {
+			switch buffer[i+2] {
+			case 'A':
+				keys = append(keys, keyUp)
+			case 'B':
+				keys = append(keys, keyDown)
+			case 'C':
+				keys = append(keys, keyRight)
+			case 'D':
+				keys = append(keys, keyLeft)
+			}
+			i += 2
+			continue
+		}
+
+		switch buffer[i] {
+		case 'w', 'W':
+			keys = append(keys, keyUp)
+		case 's', 'S':
+			keys = append(keys, keyDown)
+		case 'd', 'D':
+			keys = append(keys, keyRight)
+		case 'a', 'A':
+			keys = append(keys, keyLeft)
+		case 'r', 'R':
+			keys = append(keys, keyRestart)
+		case 'p', 'P':
+			keys = append(keys, keyPause)
+		case 'q', 'Q', 0x03: // 0x03 is Ctrl-C
+			keys = append(keys, keyQuit)
+		}
+	}
+
+	return keys
+}
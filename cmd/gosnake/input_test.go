@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseKeys(t *testing.T) {
+	type args struct {
+		buffer []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantKeys []key
+	}{
+		{
+			name: "TestEmpty",
+			args: args{
+				buffer: []byte{},
+			},
+			wantKeys: nil,
+		},
+		{
+			name: "TestArrows",
+			args: args{
+				buffer: []byte("\x1b[A\x1b[B\x1b[C\x1b[D"),
+			},
+			wantKeys: []key{keyUp, keyDown, keyRight, keyLeft},
+		},
+		{
+			name: "TestWASD",
+			args: args{
+				buffer: []byte("wasdWASD"),
+			},
+			wantKeys: []key{keyUp, keyLeft, keyDown, keyRight, keyUp, keyLeft, keyDown, keyRight},
+		},
+		{
+			name: "TestCommands",
+			args: args{
+				buffer: []byte{'r', 'p', 'q', 0x03},
+			},
+			wantKeys: []key{keyRestart, keyPause, keyQuit, keyQuit},
+		},
+		{
+			name: "TestUnknownIgnored",
+			args: args{
+				buffer: []byte("x\x1b[Zd"),
+			},
+			wantKeys: []key{keyRight},
+		},
+		{
+			name: "TestTruncatedEscape", // A lonely ESC is not an arrow
+			args: args{
+				buffer: []byte("\x1b["),
+			},
+			wantKeys: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotKeys := parseKeys(tt.args.buffer)
+			require.Equal(t, tt.wantKeys, gotKeys)
+		})
+	}
+}
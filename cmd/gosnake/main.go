@@ -0,0 +1,325 @@
+// Command gosnake is a terminal front end for the snake game logic.
+// It draws the board with ANSI escape sequences and reads the arrow keys
+// or WASD to steer the snake. P pauses, R restarts after a game over, Q quits.
+// With -bot a controller plays instead, as a demo. -difficulty picks a
+// board and a speed curve making the snake faster as it scores.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/controller"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/difficulty"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/level"
+)
+
+// Screen layout: the status line comes first then the framed board
+const (
+	statusRow   = 0
+	boardRow    = 2
+	boardColumn = 1
+)
+
+// client holds the game and its display
+type client struct {
+	game     gamestate.GameStater
+	terminal *terminal
+	size     common.Size
+	bot      controller.Controller
+}
+
+func main() {
+	width := flag.Int("width", 40, "board width")
+	height := flag.Int("height", 20, "board height")
+	tick := flag.Duration("tick", 0, "delay between two rounds, 0 follows the speed curve of the difficulty")
+	seed := flag.Int64("seed", 0, "seed of the candy positions, 0 for a random game")
+	horizontal := flag.String("horizontal", "wrap", "left and right edges: wrap, wall or bounce")
+	vertical := flag.String("vertical", "wrap", "top and bottom edges: wrap, wall or bounce")
+	candies := flag.Int("candies", 1, "number of candies on the board")
+	bonus := flag.Bool("bonus", false, "spawn golden, shrink, speed and poison candies too")
+	levelName := flag.String("difficulty", "", "difficulty: easy, normal or hard, it replaces the size, edge and candies flags")
+	levelFile := flag.String("level", "", "level file, it replaces the size, edge and candies flags")
+	botName := flag.String("bot", "", "bot playing the game: greedy, path, tail or hamiltonian")
+	flag.Parse()
+
+	edgePolicy, err := common.ParseEdgePolicy(*horizontal, *vertical)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	options := []gamestate.Option{
+		gamestate.WithEdgePolicy(edgePolicy),
+		gamestate.WithCandyRule(common.CandyRule{Count: *candies}),
+	}
+	if *bonus {
+		options = append(options, gamestate.WithCandyKinds(bonusKinds()))
+	}
+	if *seed != 0 {
+		options = append(options, gamestate.WithSeed(*seed))
+	}
+	size := common.Size{Width: *width, Height: *height}
+	if *levelName != "" {
+		aLevel, err := difficulty.Lookup(*levelName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		options = append(options, gamestate.WithDifficulty(aLevel))
+		size = aLevel.Size
+	}
+	if *levelFile != "" {
+		aLevel, err := readLevel(*levelFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		options = append(options, aLevel.Options()...)
+		size = aLevel.Size
+	}
+	if *tick > 0 {
+		options = append(options, gamestate.WithSpeedCurve(difficulty.Constant(*tick)))
+	}
+	var bot controller.Controller
+	if *botName != "" {
+		if bot, err = controller.New(*botName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if err := run(gamestate.New(options...), size, bot); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// readLevel reads a level file
+func readLevel(name string) (aLevel level.Level, err error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return aLevel, err
+	}
+	defer file.Close()
+
+	return level.Read(file)
+}
+
+// bonusKinds returns the default candy kinds,
+// a candy out of four is not a regular one
+func bonusKinds() candy.Registry {
+	candyKinds := candy.DefaultRegistry()
+	for kind, properties := range candyKinds {
+		properties.Weight = 1
+		if kind == common.RegularCandy {
+			properties.Weight = 12
+		}
+		candyKinds[kind] = properties
+	}
+
+	return candyKinds
+}
+
+func run(game gamestate.GameStater, size common.Size, bot controller.Controller) (err error) {
+	aTerminal, err := newTerminal(os.Stdout)
+	if err != nil {
+		return err
+	}
+	defer aTerminal.close()
+
+	aClient := &client{
+		game:     game,
+		terminal: aTerminal,
+		size:     size,
+		bot:      bot,
+	}
+	if err = aClient.newGame(); err != nil {
+		return err
+	}
+
+	keys := make(chan key)
+	go readKeys(keys)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	interval := game.TickInterval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-signals:
+			return nil
+		case aKey := <-keys:
+			quit, err := aClient.handleKey(aKey)
+			if quit || err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if !aClient.game.GameInProgress() || aClient.game.Paused() {
+				continue
+			}
+			if err = aClient.play(); err != nil {
+				return err
+			}
+			// The delay follows the score and the speed candies
+			if speed := aClient.game.TickInterval(); speed != interval {
+				interval = speed
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
+
+// readKeys forwards the keys typed on stdin
+func readKeys(keys chan<- key) {
+	buffer := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(buffer)
+		if err != nil {
+			keys <- keyQuit
+			return
+		}
+		for _, aKey := range parseKeys(buffer[:n]) {
+			keys <- aKey
+		}
+	}
+}
+
+// handleKey applies a command, quit is true when the player leaves
+func (aClient *client) handleKey(aKey key) (quit bool, err error) {
+	switch aKey {
+	case keyLeft:
+		aClient.game.MoveLeft()
+	case keyRight:
+		aClient.game.MoveRight()
+	case keyUp:
+		aClient.game.MoveUp()
+	case keyDown:
+		aClient.game.MoveDown()
+	case keyRestart:
+		if !aClient.game.GameInProgress() {
+			return false, aClient.newGame()
+		}
+	case keyPause:
+		if aClient.game.Paused() {
+			aClient.game.Resume()
+		} else {
+			aClient.game.Pause()
+		}
+		aClient.drawStatus()
+	case keyQuit:
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// newGame resets the board and draws it entirely
+func (aClient *client) newGame() (err error) {
+	if err = aClient.game.InitBoard(aClient.size); err != nil {
+		return err
+	}
+	listSprite, err := aClient.game.CreateObjects()
+	if err != nil {
+		return err
+	}
+	aClient.game.Start()
+
+	aClient.terminal.write(clearScreen)
+	aClient.drawFrame()
+	aClient.drawSprites(listSprite)
+	aClient.drawStatus()
+	return nil
+}
+
+// play runs a round and draws what has changed
+func (aClient *client) play() (err error) {
+	if aClient.bot != nil {
+		controller.Steer(aClient.game, 0, aClient.bot)
+	}
+	listSprite, err := aClient.game.Play()
+	if err != nil {
+		return err
+	}
+	aClient.drawSprites(listSprite)
+	aClient.drawStatus()
+	return nil
+}
+
+func (aClient *client) drawFrame() {
+	width := aClient.size.Width
+	height := aClient.size.Height
+	horizontal := "+"
+	for i := 0; i < width; i++ {
+		horizontal += "-"
+	}
+	horizontal += "+"
+
+	aClient.terminal.print(boardColumn-1, boardRow-1, horizontal)
+	for row := 0; row < height; row++ {
+		aClient.terminal.print(boardColumn-1, boardRow+row, "|")
+		aClient.terminal.print(boardColumn+width, boardRow+row, "|")
+	}
+	aClient.terminal.print(boardColumn-1, boardRow+height, horizontal)
+}
+
+// drawSprites draws the sprites in order: a tail freed and
+// a head moved at the same position leave the head on screen
+func (aClient *client) drawSprites(listSprite []common.Sprite) {
+	for _, sprite := range listSprite {
+		aClient.terminal.print(
+			boardColumn+sprite.Position.X,
+			boardRow+sprite.Position.Y,
+			aClient.colorOf(sprite.Value)+string(sprite.Value)+resetStyle,
+		)
+	}
+}
+
+func (aClient *client) colorOf(value rune) string {
+	switch value {
+	case aClient.game.SnakePart():
+		return "\x1b[32m" // green
+	case aClient.game.CandyBody():
+		return "\x1b[31m" // red
+	case aClient.game.ObstacleBody():
+		return "\x1b[90m" // gray
+	}
+	if !aClient.game.IsCandy(value) {
+		return ""
+	}
+	kind, _ := aClient.game.CandyKinds().KindOf(value)
+	switch kind {
+	case common.GoldenCandy:
+		return "\x1b[33m" // yellow
+	case common.ShrinkCandy:
+		return "\x1b[36m" // cyan
+	case common.SpeedCandy:
+		return "\x1b[34m" // blue
+	case common.PoisonCandy:
+		return "\x1b[35m" // magenta
+	}
+
+	return "\x1b[31m" // red
+}
+
+func (aClient *client) drawStatus() {
+	status := fmt.Sprintf("Score: %-5d High score: %-5d Round: %-7d",
+		aClient.game.Score(), aClient.game.HighScore(), aClient.game.Round())
+	switch {
+	case aClient.game.Outcome() == common.Won:
+		status += "You won - R: restart, Q: quit"
+	case !aClient.game.GameInProgress():
+		status += fmt.Sprintf("Game over (%s) - R: restart, Q: quit", aClient.game.GameOverReason())
+	case aClient.game.Paused():
+		status += "Paused - P: resume, Q: quit"
+	}
+	aClient.terminal.print(0, statusRow, status+"\x1b[K")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ANSI escape sequences used to draw the game
+const (
+	clearScreen = "\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	resetStyle  = "\x1b[0m"
+)
+
+// terminal wraps the output stream and the tty settings
+type terminal struct {
+	out        io.Writer
+	savedState string
+}
+
+// newTerminal switches the tty to non canonical mode without echo.
+// The previous settings are kept to be restored by close
+func newTerminal(out io.Writer) (aTerminal *terminal, err error) {
+	aTerminal = &terminal{
+		out: out,
+	}
+
+	aTerminal.savedState, err = stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	aTerminal.write(hideCursor + clearScreen)
+
+	return aTerminal, nil
+}
+
+// close restores the tty settings and the cursor
+func (aTerminal *terminal) close() {
+	aTerminal.write(resetStyle + showCursor)
+	aTerminal.moveTo(0, 0)
+	aTerminal.write(clearScreen)
+	_, _ = stty(aTerminal.savedState)
+}
+
+func (aTerminal *terminal) write(text string) {
+	_, _ = io.WriteString(aTerminal.out, text)
+}
+
+// moveTo positions the cursor, column and row start at 0
+func (aTerminal *terminal) moveTo(column, row int) {
+	aTerminal.write(fmt.Sprintf("\x1b[%d;%dH", row+1, column+1))
+}
+
+// print writes the text at the given position
+func (aTerminal *terminal) print(column, row int, text string) {
+	aTerminal.moveTo(column, row)
+	aTerminal.write(text)
+}
+
+// stty runs the stty command on the current tty
+func stty(args ...string) (output string, err error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,69 @@
+// Command gosnakeserver hosts multiplayer snake games over TCP.
+// The players join a lobby, a game starts once -players have joined
+// or when one of them asks for it. Spectators can watch the games without
+// playing. See package server for the protocol.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/difficulty"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/server"
+)
+
+func main() {
+	address := flag.String("addr", "localhost:4242", "address to listen on")
+	players := flag.Int("players", server.DefaultPlayers, "number of players starting a game")
+	width := flag.Int("width", server.DefaultSize.Width, "board width")
+	height := flag.Int("height", server.DefaultSize.Height, "board height")
+	levelName := flag.String("difficulty", "", "difficulty: easy, normal or hard, it replaces the size flags")
+	seed := flag.Int64("seed", 0, "seed of the candy positions, 0 for random games")
+	flag.Parse()
+
+	size := common.Size{Width: *width, Height: *height}
+	var options []gamestate.Option
+	if *levelName != "" {
+		aLevel, err := difficulty.Lookup(*levelName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		options = append(options, gamestate.WithDifficulty(aLevel))
+		size = aLevel.Size
+	}
+	if *seed != 0 {
+		options = append(options, gamestate.WithSeed(*seed))
+	}
+	if *players < 1 || *players >= size.Height {
+		fmt.Fprintf(os.Stderr, "the number of players must be between 1 and %d\n", size.Height-1)
+		os.Exit(2)
+	}
+
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "listening on %s\n", listener.Addr())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	aServer := server.New(
+		server.WithPlayers(*players),
+		server.WithSize(size),
+		server.WithGameOptions(options...),
+	)
+	if err := aServer.Serve(ctx, listener); err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
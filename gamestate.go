@@ -2,9 +2,17 @@ package gamestate
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/difficulty"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/event"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/gameboard"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/random"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 )
 
 // GameStater is the gameState interface
@@ -13,11 +21,33 @@ type GameStater interface {
 	FreeSpace() rune
 	SnakePart() rune
 	CandyBody() rune
+	IsCandy(ch rune) bool
+	CandyPositions() []common.Position
+	StartCandies() []common.Sprite
+	Cell(position common.Position) (value rune, err error)
+	NextPosition(position common.Position, direction common.Direction) (
+		next common.Position, nextDirection common.Direction, ok bool)
+	ObstacleBody() rune
+	IsObstacle(ch rune) bool
+	PlaceObstacle(position common.Position) (sprite common.Sprite, err error)
+	RemoveObstacle(position common.Position) (sprite common.Sprite, err error)
+	Obstacles() []common.Position
 	CreateObjects() (listSprite []common.Sprite, err error)
 	Start()
 	Play() (listSprite []common.Sprite, err error)
+	Pause()
+	Resume()
+	Paused() bool
+	Step() (listSprite []common.Sprite, err error)
+	SetDebug(debug bool)
+	LastDiff() (aDiff common.BoardDiff, ok bool)
 	GameInProgress() bool
 	SetGameInProgress(bool)
+	GameOverReason() common.GameOverReason
+	Outcome() common.Outcome
+	Result() (aResult common.GameResult, ok bool)
+	WinCondition() common.WinCondition
+	CandiesEaten() int
 	Dirty() bool
 	HighScore() int
 	Score() int
@@ -27,10 +57,26 @@ type GameStater interface {
 	MoveDown()
 	MoveUp()
 	BoardSize() common.Size
+	EdgePolicy() common.EdgePolicy
+	CandyRule() common.CandyRule
+	CandyKinds() candy.Registry
+	InputDepth() int
+	SpeedRounds() int
+	TickInterval() time.Duration
 	SnakePosition() (position common.Position, err error)
 	SnakeTail() (position common.Position, err error)
 	SnakeDirection() (direction common.Direction, err error)
 	SnakeSize() (size int, err error)
+	SnakeIDs() []common.SnakeID
+	SnakeState(id common.SnakeID) (aSnapshot snapshot.Snake, err error)
+	HeadOnRule() common.HeadOnRule
+	Steer(id common.SnakeID, direction common.Direction)
+	SnakeScore(id common.SnakeID) int
+	SnakeGameOverReason(id common.SnakeID) common.GameOverReason
+	Snapshot() (aSnapshot snapshot.Game, err error)
+	Restore(aSnapshot snapshot.Game) (err error)
+	Deterministic() bool
+	Subscribe(handler event.Handler) (unsubscribe func())
 }
 
 type gameState struct {
@@ -39,42 +85,264 @@ type gameState struct {
 	score          int
 	highScore      int
 	dirty          bool
+	gameOverReason common.GameOverReason
+	outcome        common.Outcome
+	result         common.GameResult
+	winCondition   common.WinCondition
+	candiesEaten   int
+	paused         bool
+	debug          bool
+	lastDiff       *common.BoardDiff
+	inputs         []common.Direction
+	inputDepth     int
+	candyRule      common.CandyRule
+	pendingCandies []int
+	candyKinds     candy.Registry
+	effectRule     EffectRule
+	speedRounds    int
+	speedCurve     difficulty.Curve
+	levelSize      common.Size
+	events         event.Dispatcher
+	boardOptions   []gameboard.Option
+	snakeStart     *snakeStart
+	players        []*player
+	deaths         map[common.SnakeID]common.GameOverReason
+	obstacles      []common.Position
+	candies        []common.Sprite
 	gameboard.GameBoarder
 }
 
-// ErrInvalidBoardReference is a custom error thrown when the board object is nil
-var ErrInvalidBoardReference = errors.New("the board object is nil")
+// snakeStart is where and how the snake starts
+type snakeStart struct {
+	position  common.Position
+	direction common.Direction
+}
 
-var (
-	goLeft common.Direction = common.Direction{
-		DX: -1,
-		DY: 0,
+// player is a snake added by WithSnake, its ID is its index in players plus one
+type player struct {
+	start  snakeStart
+	inputs []common.Direction
+	score  int
+}
+
+// Effect is what eating a candy does to the game,
+// the snake grows by one part whatever the effect
+type Effect struct {
+	Points   int  // added to the score
+	Shrink   int  // tail segments removed
+	Speed    int  // rounds the snake goes faster
+	GameOver bool // the snake is poisoned
+}
+
+// EffectRule returns the effect of eating a candy of the given kind
+type EffectRule func(kind common.CandyKind, properties candy.Properties) Effect
+
+// DefaultEffectRule applies the properties of the candy kind
+func DefaultEffectRule(kind common.CandyKind, properties candy.Properties) Effect {
+	return Effect{
+		Points:   properties.Points,
+		Shrink:   properties.Shrink,
+		Speed:    properties.Speed,
+		GameOver: properties.Poison,
+	}
+}
+
+// Option configures a gameState created by New
+type Option func(aGameState *gameState)
+
+// WithRandom sets the random source shared by every board of the game
+func WithRandom(randomizer random.Randomer) Option {
+	return func(aGameState *gameState) {
+		aGameState.boardOptions = append(aGameState.boardOptions, gameboard.WithRandom(randomizer))
+	}
+}
+
+// WithSnakeStart places the snake at position heading to direction
+// instead of the center of the board heading right
+func WithSnakeStart(position common.Position, direction common.Direction) Option {
+	return func(aGameState *gameState) {
+		aGameState.snakeStart = &snakeStart{
+			position:  position,
+			direction: direction,
+		}
+	}
+}
+
+// WithSnake adds a snake starting at position heading to direction.
+// The snakes added get the IDs 1, 2... in order, the snake 0 being the
+// one placed by WithSnakeStart. With several snakes the game goes on
+// until they are all dead
+func WithSnake(position common.Position, direction common.Direction) Option {
+	return func(aGameState *gameState) {
+		aGameState.players = append(aGameState.players, &player{
+			start: snakeStart{
+				position:  position,
+				direction: direction,
+			},
+		})
+	}
+}
+
+// WithHeadOnRule sets what happens when snake heads move to the same cell
+func WithHeadOnRule(rule common.HeadOnRule) Option {
+	return func(aGameState *gameState) {
+		aGameState.boardOptions = append(aGameState.boardOptions, gameboard.WithHeadOnRule(rule))
+	}
+}
+
+// WithEdgePolicy sets what happens when the snake crosses an edge of the board
+func WithEdgePolicy(edgePolicy common.EdgePolicy) Option {
+	return func(aGameState *gameState) {
+		aGameState.boardOptions = append(aGameState.boardOptions, gameboard.WithEdgePolicy(edgePolicy))
+	}
+}
+
+// WithInputDepth sets how many direction changes can be queued between
+// two rounds, the extra ones are dropped
+func WithInputDepth(depth int) Option {
+	return func(aGameState *gameState) {
+		aGameState.inputDepth = depth
+	}
+}
+
+// WithCandyRule sets how many candies are on the board and how they respawn.
+// A count lower than one is read as one
+func WithCandyRule(rule common.CandyRule) Option {
+	return func(aGameState *gameState) {
+		if rule.Count < 1 {
+			rule.Count = 1
+		}
+		aGameState.candyRule = rule
+	}
+}
+
+// WithWinCondition sets how the game is won, by default it can only be lost
+func WithWinCondition(win common.WinCondition) Option {
+	return func(aGameState *gameState) {
+		aGameState.winCondition = win
+	}
+}
+
+// WithSpeedCurve sets the delay between two rounds returned by TickInterval,
+// nil restores the default constant interval
+func WithSpeedCurve(curve difficulty.Curve) Option {
+	return func(aGameState *gameState) {
+		if curve == nil {
+			curve = difficulty.Constant(difficulty.DefaultInterval)
+		}
+		aGameState.speedCurve = curve
+	}
+}
+
+// WithDifficulty applies the edges, the candy count and the speed curve
+// of aLevel. Its size is used by InitBoard when given a zero size
+func WithDifficulty(aLevel difficulty.Level) Option {
+	return func(aGameState *gameState) {
+		aGameState.levelSize = aLevel.Size
+		aGameState.boardOptions = append(aGameState.boardOptions, gameboard.WithEdgePolicy(aLevel.Edges))
+		if aLevel.Candies > 0 {
+			aGameState.candyRule.Count = aLevel.Candies
+		}
+		if aLevel.Curve != nil {
+			aGameState.speedCurve = aLevel.Curve
+		}
+	}
+}
+
+// WithDebug makes every round record the changes of the board, see LastDiff
+func WithDebug() Option {
+	return func(aGameState *gameState) {
+		aGameState.debug = true
+	}
+}
+
+// WithCandyKinds sets the kinds of candy of the game, their
+// effects and how often they are spawned
+func WithCandyKinds(candyKinds candy.Registry) Option {
+	return func(aGameState *gameState) {
+		aGameState.candyKinds = candyKinds
+		aGameState.boardOptions = append(aGameState.boardOptions, gameboard.WithCandyKinds(candyKinds))
+	}
+}
+
+// WithEffectRule replaces DefaultEffectRule to apply the candy effects
+func WithEffectRule(rule EffectRule) Option {
+	return func(aGameState *gameState) {
+		aGameState.effectRule = rule
 	}
-	goRight common.Direction = common.Direction{
-		DX: 1,
-		DY: 0,
+}
+
+// WithObstacles places obstacles on every board of the game
+// before the snake and the candies
+func WithObstacles(positions ...common.Position) Option {
+	return func(aGameState *gameState) {
+		aGameState.obstacles = append(aGameState.obstacles, positions...)
 	}
-	goUp common.Direction = common.Direction{
-		DX: 0,
-		DY: -1,
+}
+
+// WithCandies places candies on every board of the game, each sprite
+// value is the body of a candy kind. The candy rule count includes them
+func WithCandies(candies ...common.Sprite) Option {
+	return func(aGameState *gameState) {
+		aGameState.candies = append(aGameState.candies, candies...)
 	}
-	goDown common.Direction = common.Direction{
-		DX: 0,
-		DY: 1,
+}
+
+// WithSeed makes the candy sequence reproducible for the given seed.
+// Each board gets its own source: every game created with the option,
+// and every InitBoard of a game, draws the same candies
+func WithSeed(seed int64) Option {
+	return func(aGameState *gameState) {
+		aGameState.boardOptions = append(aGameState.boardOptions, gameboard.WithSeed(seed))
 	}
+}
+
+// DefaultInputDepth is the number of direction changes queued between two rounds
+const DefaultInputDepth = 3
+
+// Defines custom errors
+var (
+	ErrInvalidBoardReference = errors.New("the board object is nil")
+	ErrUnknownCandy          = errors.New("unknown candy body")
+	ErrPaused                = errors.New("the game is paused")
+	ErrNotCloneable          = errors.New("the game can't be cloned")
+)
+
+var (
+	goLeft  = common.Left
+	goRight = common.Right
+	goUp    = common.Up
+	goDown  = common.Down
 )
 
 // New returns an instance of gameState
-func New() GameStater {
+func New(options ...Option) GameStater {
 	var aGameState gameState
-	aGameState.GameBoarder = gameboard.New()
+	aGameState.inputDepth = DefaultInputDepth
+	aGameState.candyRule = common.CandyRule{Count: 1}
+	aGameState.candyKinds = candy.DefaultRegistry()
+	aGameState.effectRule = DefaultEffectRule
+	aGameState.speedCurve = difficulty.Constant(difficulty.DefaultInterval)
+	for _, option := range options {
+		option(&aGameState)
+	}
+	aGameState.GameBoarder = gameboard.New(aGameState.boardOptions...)
 	return &aGameState
 }
 
+// InitBoard creates a new board of size, a zero size takes the one
+// of the level set by WithDifficulty
 func (aGameState *gameState) InitBoard(size common.Size) (err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	aGameState.GameBoarder = gameboard.New()
+	if size == (common.Size{}) {
+		size = aGameState.levelSize
+	}
+	aGameState.GameBoarder = gameboard.New(aGameState.boardOptions...)
+	aGameState.inputs = nil
+	aGameState.pendingCandies = nil
+	aGameState.speedRounds = 0
+	aGameState.resetPlayers()
 
 	if err = aGameState.InitGameBoard(size); err != nil {
 		return err
@@ -95,22 +363,68 @@ func (aGameState *gameState) CandyBody() rune {
 	return gameboard.CandyBody
 }
 
+func (aGameState *gameState) ObstacleBody() rune {
+	return gameboard.ObstacleBody
+}
+
 func (aGameState *gameState) CreateObjects() (listSprite []common.Sprite, err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	if aGameState.GameBoarder == nil {
 		return listSprite, ErrInvalidBoardReference
 	}
+	for _, position := range aGameState.obstacles {
+		obstacle, err := aGameState.PlaceObstacle(position)
+		if err != nil {
+			return nil, err
+		}
+		listSprite = append(listSprite, obstacle)
+	}
 	position := common.Position{
 		X: aGameState.BoardSize().Width / 2,
 		Y: aGameState.BoardSize().Height / 2,
 	}
-	snake, err := aGameState.CreateSnake(position, goRight)
+	direction := goRight
+	if aGameState.snakeStart != nil {
+		position = aGameState.snakeStart.position
+		direction = aGameState.snakeStart.direction
+	}
+	snake, err := aGameState.CreateSnake(position, direction)
 	if err != nil {
 		return nil, err
 	}
-	candy, err := aGameState.CreateCandy()
-	return []common.Sprite{snake, candy}, err
+	listSprite = append(listSprite, snake)
+	for i, aPlayer := range aGameState.players {
+		snake, err := aGameState.AddSnake(common.SnakeID(i+1), aPlayer.start.position, aPlayer.start.direction)
+		if err != nil {
+			return nil, err
+		}
+		listSprite = append(listSprite, snake)
+	}
+	aGameState.pendingCandies = nil
+	for _, sprite := range aGameState.candies {
+		kind, ok := aGameState.CandyKinds().KindOf(sprite.Value)
+		if !ok {
+			return listSprite, fmt.Errorf("%w: %q", ErrUnknownCandy, sprite.Value)
+		}
+		candy, err := aGameState.PlaceCandy(sprite.Position, kind)
+		if err != nil {
+			return listSprite, err
+		}
+		aGameState.emitCandy(event.CandySpawned, 0, candy.Position, kind)
+		listSprite = append(listSprite, candy)
+	}
+	for i := len(aGameState.candies); i < aGameState.candyRule.Count; i++ {
+		candy, err := aGameState.spawnCandy()
+		if errors.Is(err, gameboard.ErrBoardFull) {
+			break
+		}
+		if err != nil {
+			return listSprite, err
+		}
+		listSprite = append(listSprite, candy)
+	}
+	return listSprite, nil
 }
 
 func (aGameState *gameState) Start() {
@@ -118,45 +432,309 @@ func (aGameState *gameState) Start() {
 	aGameState.score = 0
 	aGameState.round = 0
 	aGameState.dirty = true
+	aGameState.gameOverReason = common.NotOver
+	aGameState.outcome = common.Undecided
+	aGameState.result = common.GameResult{}
+	aGameState.candiesEaten = 0
+	aGameState.paused = false
+	aGameState.lastDiff = nil
+	aGameState.inputs = nil
+	aGameState.speedRounds = 0
+	aGameState.resetPlayers()
+}
+
+// resetPlayers clears the scores, inputs and deaths of the added snakes
+func (aGameState *gameState) resetPlayers() {
+	for _, aPlayer := range aGameState.players {
+		aPlayer.inputs = nil
+		aPlayer.score = 0
+	}
+	aGameState.deaths = nil
 }
 
+// Play plays a round, it fails with ErrPaused while the game is paused
 func (aGameState *gameState) Play() (listSprite []common.Sprite, err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	if aGameState.GameBoarder == nil {
 		return listSprite, ErrInvalidBoardReference
 	}
+	if aGameState.paused {
+		return listSprite, ErrPaused
+	}
+
+	return aGameState.playRound()
+}
+
+// Step pauses the game and plays a single round, the next rounds
+// are played by calling Step again or by Play once resumed
+func (aGameState *gameState) Step() (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aGameState.GameBoarder == nil {
+		return listSprite, ErrInvalidBoardReference
+	}
+	aGameState.paused = true
+
+	return aGameState.playRound()
+}
+
+// Pause stops Play until Resume, the inputs are still queued meanwhile.
+// Unlike a game over the game goes on where it was
+func (aGameState *gameState) Pause() {
+	aGameState.paused = true
+}
+
+func (aGameState *gameState) Resume() {
+	aGameState.paused = false
+}
+
+func (aGameState *gameState) Paused() bool {
+	return aGameState.paused
+}
+
+// SetDebug turns the recording of the board changes on or off
+func (aGameState *gameState) SetDebug(debug bool) {
+	aGameState.debug = debug
+	if !debug {
+		aGameState.lastDiff = nil
+	}
+}
+
+// LastDiff returns the changes of the board during the last round played
+// in debug mode, ok is false when no round has been recorded
+func (aGameState *gameState) LastDiff() (aDiff common.BoardDiff, ok bool) {
+	if aGameState.lastDiff == nil {
+		return aDiff, false
+	}
+
+	return *aGameState.lastDiff, true
+}
+
+// playRound plays a round, recording the changes of the board in debug mode
+func (aGameState *gameState) playRound() (listSprite []common.Sprite, err error) {
+	if !aGameState.debug {
+		return aGameState.play()
+	}
+	before := aGameState.cells()
+	listSprite, err = aGameState.play()
+	aDiff := common.NewBoardDiff(aGameState.round, before, aGameState.cells())
+	aGameState.lastDiff = &aDiff
+
+	return listSprite, err
+}
+
+// cells returns the rows of the board, nil without a board
+func (aGameState *gameState) cells() (rows []string) {
+	if aGameState.GameBoarder == nil {
+		return nil
+	}
 
+	return Cells(aGameState)
+}
+
+// Cells returns the rows of the board of game, like the Cells of its
+// snapshot. Unlike Snapshot it leaves the game as it is: the candies
+// drawn next are the same whether it is called or not
+func Cells(game GameStater) (rows []string) {
+	size := game.BoardSize()
+	rows = make([]string, size.Height)
+	row := make([]rune, size.Width)
+	for y := range rows {
+		for x := range row {
+			value, err := game.Cell(common.Position{X: x, Y: y})
+			if err != nil {
+				value = '?'
+			}
+			row[x] = value
+		}
+		rows[y] = string(row)
+	}
+
+	return rows
+}
+
+// play plays a round
+func (aGameState *gameState) play() (listSprite []common.Sprite, err error) {
 	//Plays a round
 	aGameState.round++
+	if aGameState.speedRounds > 0 {
+		aGameState.speedRounds--
+	}
+
+	if len(aGameState.players) > 0 {
+		return aGameState.playSnakes()
+	}
+
+	//Takes the next queued direction
+	aGameState.applyInput(0)
 
 	//Move the snake
 	oldValue, spriteList, err := aGameState.MoveSnake()
 	if err != nil {
-		aGameState.gameInProgress = false
+		aGameState.gameOver(common.InternalError, aGameState.headPosition())
 		return spriteList, err
 	}
 	//Game over?
 	if aGameState.IsSnakePart(oldValue) {
-		aGameState.gameInProgress = false
+		aGameState.gameOver(common.SelfCollision, aGameState.headPosition())
+		return spriteList, nil
+	}
+	if aGameState.IsWall(oldValue) {
+		aGameState.gameOver(common.WallCollision, aGameState.cellAhead())
+		return spriteList, nil
+	}
+	if aGameState.IsObstacle(oldValue) {
+		aGameState.gameOver(common.ObstacleCollision, aGameState.headPosition())
 		return spriteList, nil
 	}
 
 	//Ate a candy?
 	if aGameState.IsCandy(oldValue) {
-		//Remove the candy since it's been eaten
-		aGameState.RemoveCandy()
-		//updates the score
-		aGameState.score++
-		//updates the highscore
-		if aGameState.score > aGameState.highScore {
-			aGameState.highScore = aGameState.score
+		shrinkList, err := aGameState.eatCandy(0, aGameState.headPosition())
+		if err != nil {
+			return nil, err
+		}
+		spriteList = append(spriteList, shrinkList...)
+		if !aGameState.gameInProgress {
+			return spriteList, nil
+		}
+	}
+
+	return aGameState.endRound(spriteList)
+}
+
+// playSnakes plays a round of a game with several snakes.
+// A dead snake leaves the board, the game is over once they are all dead
+func (aGameState *gameState) playSnakes() (spriteList []common.Sprite, err error) {
+	for _, id := range aGameState.SnakeIDs() {
+		aGameState.applyInput(id)
+	}
+
+	moves, spriteList, err := aGameState.MoveSnakes()
+	if err != nil {
+		aGameState.gameOver(common.InternalError, common.Position{})
+		return spriteList, err
+	}
+	// The result of the game is the one of the last snake to die
+	var aResult common.GameResult
+	for _, move := range moves {
+		if move.Collision != common.NotOver {
+			aGameState.snakeDied(move.ID, move.Collision, move.Position)
+			aResult = aGameState.snakeResult(move, move.Collision)
+			continue
+		}
+		if aGameState.IsCandy(move.OldValue) {
+			candyList, err := aGameState.eatCandy(move.ID, move.Position)
+			if err != nil {
+				return nil, err
+			}
+			spriteList = append(spriteList, candyList...)
+			if died, ok := aGameState.deaths[move.ID]; ok {
+				move.Length++ // The snake grew before it was poisoned
+				aResult = aGameState.snakeResult(move, died)
+			}
+		}
+	}
+	if len(aGameState.SnakeIDs()) == 0 {
+		aGameState.finish(aResult)
+		return spriteList, nil
+	}
+
+	return aGameState.endRound(spriteList)
+}
+
+// endRound refreshes the candies of a round the snakes survived
+// then checks the win condition
+func (aGameState *gameState) endRound(spriteList []common.Sprite) ([]common.Sprite, error) {
+	candyList, err := aGameState.refreshCandies()
+	spriteList = append(spriteList, candyList...)
+	if err != nil {
+		return spriteList, err
+	}
+	aGameState.checkWin()
+	return spriteList, nil
+}
+
+// checkWin ends the game once the win condition is met. The condition is
+// checked for the whole game: the best score of the snakes counts and
+// the candies eaten by every snake add up.
+// Eating the candies within the round limit is lost when the limit passes
+func (aGameState *gameState) checkWin() {
+	win := aGameState.winCondition
+	won := false
+	switch win.Goal {
+	case common.FillBoard:
+		won = aGameState.FreeCount() == 0 && aGameState.CandyCount() == 0
+	case common.ReachScore:
+		won = aGameState.bestScore() >= win.Target
+	case common.SurviveRounds:
+		won = aGameState.round >= win.Target
+	case common.EatCandies:
+		won = aGameState.candiesEaten >= win.Target
+		if !won && win.Rounds > 0 && aGameState.round >= win.Rounds {
+			aGameState.gameOver(common.RoundLimit, aGameState.headPosition())
+			return
+		}
+	}
+	if won {
+		id, score := aGameState.bestSnake()
+		aResult := common.GameResult{
+			Outcome: common.Won,
+			Score:   score,
+			Snake:   id,
+		}
+		if state, err := aGameState.SnakeState(id); err == nil && len(state.Body) > 0 {
+			aResult.Length = len(state.Body)
+			aResult.Killer = state.Body[len(state.Body)-1]
+		}
+		aGameState.finish(aResult)
+	}
+}
+
+// bestScore returns the highest score of the snakes
+func (aGameState *gameState) bestScore() int {
+	_, best := aGameState.bestSnake()
+	return best
+}
+
+// bestSnake returns the live snake with the highest score, the first one
+// on a tie. Only the snake 0 is alive in a game without added snakes
+func (aGameState *gameState) bestSnake() (id common.SnakeID, best int) {
+	best = -1
+	for _, snakeID := range aGameState.SnakeIDs() {
+		if score := aGameState.SnakeScore(snakeID); score > best {
+			id, best = snakeID, score
 		}
 	}
+	if best < 0 {
+		return 0, aGameState.score
+	}
+
+	return id, best
+}
+
+// refreshCandies removes the expired candies and spawns
+// the ones due by the respawn rule
+func (aGameState *gameState) refreshCandies() (spriteList []common.Sprite, err error) {
+	//Expired candies?
+	expiredList, err := aGameState.ExpireCandies()
+	if err != nil {
+		return nil, err
+	}
+	for _, sprite := range expiredList {
+		aGameState.removedCandy()
+		aGameState.emit(event.CandyExpired, sprite.Position)
+	}
+	spriteList = append(spriteList, expiredList...)
 
-	//No more candies?
-	if !aGameState.CandyAlive() {
-		sprite, err := aGameState.CreateCandy()
+	//Missing candies? They wait for a free cell when the board is full
+	for missing := aGameState.missingCandies(); missing > 0; missing-- {
+		sprite, err := aGameState.spawnCandy()
+		if errors.Is(err, gameboard.ErrBoardFull) {
+			aGameState.postponeCandies(missing)
+			break
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -166,6 +744,235 @@ func (aGameState *gameState) Play() (listSprite []common.Sprite, err error) {
 	return spriteList, nil
 }
 
+// eatCandy removes the candy under the head of the snake id and applies
+// its effect. It returns the sprites of the cells freed by a shrink
+// or by the death of a poisoned snake among several
+func (aGameState *gameState) eatCandy(id common.SnakeID, head common.Position) (listSprite []common.Sprite, err error) {
+	//Remove the candy since it's been eaten
+	kind, err := aGameState.RemoveCandyAt(head)
+	if err != nil {
+		return nil, err
+	}
+	aGameState.emitCandy(event.CandyEaten, id, head, kind)
+	aGameState.removedCandy()
+	aGameState.candiesEaten++
+
+	effect := aGameState.effect(kind)
+	if effect.GameOver {
+		if len(aGameState.players) == 0 {
+			aGameState.gameOver(common.Poisoned, head)
+			return nil, nil
+		}
+		aGameState.snakeDied(id, common.Poisoned, head)
+		return aGameState.RemoveSnake(id)
+	}
+	if score := aGameState.scoreOf(id); effect.Points != 0 && score != nil {
+		//updates the score
+		*score += effect.Points
+		aGameState.events.Emit(aGameState.newSnakeEvent(event.ScoreChanged, id, common.Position{}))
+		//updates the highscore
+		if *score > aGameState.highScore {
+			aGameState.highScore = *score
+			aGameState.emit(event.HighScoreChanged, common.Position{})
+		}
+	}
+	if effect.Speed > aGameState.speedRounds {
+		aGameState.speedRounds = effect.Speed
+	}
+	if effect.Shrink > 0 {
+		return aGameState.ShrinkSnake(id, effect.Shrink)
+	}
+
+	return nil, nil
+}
+
+// snakeDied records why the snake id died and notifies the subscribers
+func (aGameState *gameState) snakeDied(id common.SnakeID, reason common.GameOverReason, position common.Position) {
+	if aGameState.deaths == nil {
+		aGameState.deaths = make(map[common.SnakeID]common.GameOverReason)
+	}
+	aGameState.deaths[id] = reason
+	anEvent := aGameState.newSnakeEvent(event.SnakeDied, id, position)
+	anEvent.Reason = reason
+	aGameState.events.Emit(anEvent)
+}
+
+// scoreOf returns the score of the snake id, nil for an unknown snake
+func (aGameState *gameState) scoreOf(id common.SnakeID) *int {
+	if id == 0 {
+		return &aGameState.score
+	}
+	if aPlayer := aGameState.player(id); aPlayer != nil {
+		return &aPlayer.score
+	}
+
+	return nil
+}
+
+// player returns the snake added with the ID id, nil for the snake 0
+// or an unknown ID
+func (aGameState *gameState) player(id common.SnakeID) *player {
+	if id < 1 || int(id) > len(aGameState.players) {
+		return nil
+	}
+
+	return aGameState.players[id-1]
+}
+
+// effect returns the effect of eating a candy of the given kind
+func (aGameState *gameState) effect(kind common.CandyKind) Effect {
+	rule := aGameState.effectRule
+	if rule == nil {
+		rule = DefaultEffectRule
+	}
+
+	return rule(kind, aGameState.CandyKinds()[kind])
+}
+
+// removedCandy schedules the respawn of a candy eaten or expired this round
+func (aGameState *gameState) removedCandy() {
+	if aGameState.candyRule.Respawn == common.RespawnDelayed {
+		aGameState.pendingCandies = append(aGameState.pendingCandies, aGameState.round+aGameState.candyRule.Delay)
+	}
+}
+
+// postponeCandies delays the respawn of count candies to the next round
+func (aGameState *gameState) postponeCandies(count int) {
+	if aGameState.candyRule.Respawn != common.RespawnDelayed {
+		return
+	}
+	for ; count > 0; count-- {
+		aGameState.pendingCandies = append([]int{aGameState.round + 1}, aGameState.pendingCandies...)
+	}
+}
+
+// missingCandies returns how many candies the respawn rule
+// puts back on the board this round
+func (aGameState *gameState) missingCandies() int {
+	switch aGameState.candyRule.Respawn {
+	case common.RespawnBatch:
+		if aGameState.CandyCount() > 0 {
+			return 0
+		}
+	case common.RespawnDelayed:
+		due := 0
+		for due < len(aGameState.pendingCandies) && aGameState.pendingCandies[due] <= aGameState.round {
+			due++
+		}
+		aGameState.pendingCandies = aGameState.pendingCandies[due:]
+		return due
+	}
+
+	return aGameState.candyRule.Count - aGameState.CandyCount()
+}
+
+// spawnCandy adds a candy to the board and notifies the subscribers
+func (aGameState *gameState) spawnCandy() (sprite common.Sprite, err error) {
+	if sprite, err = aGameState.CreateCandy(); err != nil {
+		return sprite, err
+	}
+	kind, _ := aGameState.CandyKinds().KindOf(sprite.Value)
+	aGameState.emitCandy(event.CandySpawned, 0, sprite.Position, kind)
+	return sprite, nil
+}
+
+// gameOver ends the game of the snake 0 for reason, killer is the cell
+// that ended it
+func (aGameState *gameState) gameOver(reason common.GameOverReason, killer common.Position) {
+	length, _ := aGameState.SnakeSize()
+	aGameState.finish(common.GameResult{
+		Outcome: common.Lost,
+		Reason:  reason,
+		Length:  length,
+		Score:   aGameState.score,
+		Killer:  killer,
+	})
+}
+
+// snakeResult returns the result of the game ended by the death of a snake
+func (aGameState *gameState) snakeResult(move common.SnakeMove, reason common.GameOverReason) common.GameResult {
+	return common.GameResult{
+		Outcome: common.Lost,
+		Reason:  reason,
+		Length:  move.Length,
+		Score:   aGameState.SnakeScore(move.ID),
+		Killer:  move.Position,
+		Snake:   move.ID,
+	}
+}
+
+// finish ends the game with aResult and notifies the subscribers
+func (aGameState *gameState) finish(aResult common.GameResult) {
+	aResult.Round = aGameState.round
+	aGameState.gameInProgress = false
+	aGameState.gameOverReason = aResult.Reason
+	aGameState.outcome = aResult.Outcome
+	aGameState.result = aResult
+	if aResult.Outcome == common.Won {
+		aGameState.emit(event.GameWon, aGameState.headPosition())
+		return
+	}
+	aGameState.emit(event.GameOver, aGameState.headPosition())
+}
+
+// cellAhead returns the cell in front of the snake 0 head, it may be off the board
+func (aGameState *gameState) cellAhead() common.Position {
+	head := aGameState.headPosition()
+	direction, _ := aGameState.SnakeDirection()
+	return common.Position{
+		X: head.X + direction.DX,
+		Y: head.Y + direction.DY,
+	}
+}
+
+// headPosition returns the position of the snake head, or 0,0 without a snake
+func (aGameState *gameState) headPosition() common.Position {
+	position, _ := aGameState.SnakePosition()
+	return position
+}
+
+// emit sends an event of the current round to the subscribers
+func (aGameState *gameState) emit(kind event.Kind, position common.Position) {
+	aGameState.events.Emit(aGameState.newEvent(kind, position))
+}
+
+// emitCandy sends an event about a candy of the given kind and the snake id
+func (aGameState *gameState) emitCandy(kind event.Kind, id common.SnakeID, position common.Position,
+	candyKind common.CandyKind) {
+	anEvent := aGameState.newSnakeEvent(kind, id, position)
+	anEvent.Candy = candyKind
+	aGameState.events.Emit(anEvent)
+}
+
+// newEvent returns an event of the current round
+func (aGameState *gameState) newEvent(kind event.Kind, position common.Position) event.Event {
+	return aGameState.newSnakeEvent(kind, 0, position)
+}
+
+// newSnakeEvent returns an event of the current round about the snake id
+func (aGameState *gameState) newSnakeEvent(kind event.Kind, id common.SnakeID, position common.Position) event.Event {
+	var score int
+	if snakeScore := aGameState.scoreOf(id); snakeScore != nil {
+		score = *snakeScore
+	}
+	if kind == event.HighScoreChanged {
+		score = aGameState.highScore
+	}
+
+	return event.Event{
+		Kind:     kind,
+		Round:    aGameState.round,
+		Position: position,
+		Score:    score,
+		Reason:   aGameState.gameOverReason,
+		Snake:    id,
+	}
+}
+
+func (aGameState *gameState) Subscribe(handler event.Handler) (unsubscribe func()) {
+	return aGameState.events.Subscribe(handler)
+}
+
 func (aGameState *gameState) GameInProgress() bool {
 	return aGameState.gameInProgress
 }
@@ -174,6 +981,77 @@ func (aGameState *gameState) SetGameInProgress(val bool) {
 	aGameState.gameInProgress = val
 }
 
+func (aGameState *gameState) GameOverReason() common.GameOverReason {
+	return aGameState.gameOverReason
+}
+
+// Outcome tells whether the game has been won or lost
+func (aGameState *gameState) Outcome() common.Outcome {
+	return aGameState.outcome
+}
+
+// Result returns how the game ended, ok is false until it is over
+func (aGameState *gameState) Result() (aResult common.GameResult, ok bool) {
+	return aGameState.result, aGameState.outcome != common.Undecided
+}
+
+func (aGameState *gameState) WinCondition() common.WinCondition {
+	return aGameState.winCondition
+}
+
+// StartCandies returns the candies placed by WithCandies when the objects are created
+func (aGameState *gameState) StartCandies() []common.Sprite {
+	return append([]common.Sprite(nil), aGameState.candies...)
+}
+
+// CandiesEaten returns the number of candies eaten since the start, by every snake
+func (aGameState *gameState) CandiesEaten() int {
+	return aGameState.candiesEaten
+}
+
+func (aGameState *gameState) CandyRule() common.CandyRule {
+	return aGameState.candyRule
+}
+
+// CandyKinds returns the kinds of candy of the game
+func (aGameState *gameState) CandyKinds() candy.Registry {
+	if aGameState.candyKinds == nil {
+		aGameState.candyKinds = candy.DefaultRegistry()
+	}
+
+	return aGameState.candyKinds
+}
+
+// InputDepth returns how many direction changes can be queued between two rounds
+func (aGameState *gameState) InputDepth() int {
+	return aGameState.inputDepth
+}
+
+// SpeedRounds returns the rounds left during which the snake goes faster.
+// The game doesn't keep time, the front end shortens its ticks meanwhile
+func (aGameState *gameState) SpeedRounds() int {
+	return aGameState.speedRounds
+}
+
+// TickInterval returns the delay before the next round given by the
+// speed curve for the score, the length and the round, a speed candy halves it.
+// It is never shorter than difficulty.MinInterval
+func (aGameState *gameState) TickInterval() time.Duration {
+	aProgress := difficulty.Progress{
+		Score: aGameState.score,
+		Round: aGameState.round,
+	}
+	if aGameState.GameBoarder != nil {
+		aProgress.Length, _ = aGameState.SnakeSize()
+	}
+	interval := aGameState.speedCurve(aProgress)
+	if aGameState.speedRounds > 0 {
+		interval /= 2
+	}
+
+	return difficulty.AtLeastMin(interval)
+}
+
 func (aGameState *gameState) Dirty() bool {
 	return aGameState.dirty
 }
@@ -190,15 +1068,312 @@ func (aGameState *gameState) Round() int {
 	return aGameState.round
 }
 
+// SnakeScore returns the score of the snake id, Score is the score of the snake 0
+func (aGameState *gameState) SnakeScore(id common.SnakeID) int {
+	if score := aGameState.scoreOf(id); score != nil {
+		return *score
+	}
+
+	return 0
+}
+
+// SnakeGameOverReason tells why the snake id died, NotOver while it is alive
+func (aGameState *gameState) SnakeGameOverReason(id common.SnakeID) common.GameOverReason {
+	if reason, ok := aGameState.deaths[id]; ok {
+		return reason
+	}
+	if id == 0 && len(aGameState.players) == 0 {
+		return aGameState.gameOverReason
+	}
+
+	return common.NotOver
+}
+
 func (aGameState *gameState) MoveLeft() {
-	aGameState.SetSnakeDirection(goLeft)
+	aGameState.queueInput(0, goLeft)
 }
 func (aGameState *gameState) MoveRight() {
-	aGameState.SetSnakeDirection(goRight)
+	aGameState.queueInput(0, goRight)
 }
 func (aGameState *gameState) MoveDown() {
-	aGameState.SetSnakeDirection(goDown)
+	aGameState.queueInput(0, goDown)
 }
 func (aGameState *gameState) MoveUp() {
-	aGameState.SetSnakeDirection(goUp)
+	aGameState.queueInput(0, goUp)
+}
+
+// Steer queues a direction for the snake id, like MoveLeft and
+// the others do for the snake 0
+func (aGameState *gameState) Steer(id common.SnakeID, direction common.Direction) {
+	aGameState.queueInput(id, direction)
+}
+
+// queueInput adds a direction to be taken by the snake id in a next round.
+// It is compared to the last queued direction, or the snake direction:
+// repeating it or reversing into the neck of the snake is ignored
+func (aGameState *gameState) queueInput(id common.SnakeID, direction common.Direction) {
+	if aGameState.GameBoarder == nil {
+		return
+	}
+	inputs := aGameState.inputsOf(id)
+	if inputs == nil {
+		return
+	}
+
+	var last common.Direction
+	if len(*inputs) > 0 {
+		last = (*inputs)[len(*inputs)-1]
+	} else {
+		var err error
+		if last, err = aGameState.snakeDirection(id); err != nil {
+			return
+		}
+	}
+
+	if direction == last || aGameState.reversesIntoNeck(id, last, direction) {
+		return
+	}
+	if len(*inputs) >= aGameState.inputDepth {
+		return
+	}
+	*inputs = append(*inputs, direction)
+}
+
+// applyInput sets the direction of the snake id to the oldest queued one
+func (aGameState *gameState) applyInput(id common.SnakeID) {
+	inputs := aGameState.inputsOf(id)
+	if inputs == nil || len(*inputs) == 0 {
+		return
+	}
+	direction := (*inputs)[0]
+	*inputs = (*inputs)[1:]
+
+	// The snake may have bounced since the direction was queued
+	current, err := aGameState.snakeDirection(id)
+	if err != nil || aGameState.reversesIntoNeck(id, current, direction) {
+		return
+	}
+	if id == 0 {
+		aGameState.SetSnakeDirection(direction)
+		return
+	}
+	_ = aGameState.TurnSnake(id, direction) // The snake exists, its direction was read
+}
+
+// inputsOf returns the queued directions of the snake id, nil for an unknown snake
+func (aGameState *gameState) inputsOf(id common.SnakeID) *[]common.Direction {
+	if id == 0 {
+		return &aGameState.inputs
+	}
+	if aPlayer := aGameState.player(id); aPlayer != nil {
+		return &aPlayer.inputs
+	}
+
+	return nil
+}
+
+// snakeDirection returns the direction of the snake id
+func (aGameState *gameState) snakeDirection(id common.SnakeID) (direction common.Direction, err error) {
+	if id == 0 {
+		return aGameState.SnakeDirection()
+	}
+	state, err := aGameState.SnakeState(id)
+	return state.Direction, err
+}
+
+// reversesIntoNeck tells if going from current to next makes
+// the snake id run into itself, which needs more than one part
+func (aGameState *gameState) reversesIntoNeck(id common.SnakeID, current, next common.Direction) bool {
+	if next != current.Reverse() {
+		return false
+	}
+	if id != 0 {
+		state, err := aGameState.SnakeState(id)
+		return err == nil && len(state.Body) > 1
+	}
+	size, err := aGameState.SnakeSize()
+	return err == nil && size > 1
+}
+
+func (aGameState *gameState) Snapshot() (aSnapshot snapshot.Game, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aGameState.GameBoarder == nil {
+		return aSnapshot, ErrInvalidBoardReference
+	}
+	board, err := aGameState.GameBoarder.Snapshot()
+	if err != nil {
+		return aSnapshot, err
+	}
+
+	return snapshot.Game{
+		Version:        snapshot.Version,
+		GameInProgress: aGameState.gameInProgress,
+		Round:          aGameState.round,
+		Score:          aGameState.score,
+		HighScore:      aGameState.highScore,
+		Dirty:          aGameState.dirty,
+		Inputs:         append([]common.Direction(nil), aGameState.inputs...),
+		PendingCandies: append([]int(nil), aGameState.pendingCandies...),
+		SpeedRounds:    aGameState.speedRounds,
+		Players:        aGameState.playerSnapshots(),
+		Deaths:         aGameState.deathSnapshots(),
+		Result:         aGameState.resultSnapshot(),
+		CandiesEaten:   aGameState.candiesEaten,
+		Paused:         aGameState.paused,
+		Board:          board,
+	}, nil
+}
+
+// Clone returns a copy of a game created by New. Given the same inputs
+// it plays the same as the game from then on as long as it is Deterministic,
+// playing one doesn't change the other. The subscribers are not copied
+func Clone(game GameStater) (aClone GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aGameState, ok := game.(*gameState)
+	if !ok {
+		return nil, ErrNotCloneable
+	}
+
+	return aGameState.clone()
+}
+
+func (aGameState *gameState) clone() (*gameState, error) {
+	aClone := *aGameState
+	if aGameState.GameBoarder != nil {
+		aGameBoarder, err := gameboard.Clone(aGameState.GameBoarder)
+		if err != nil {
+			return nil, err
+		}
+		aClone.GameBoarder = aGameBoarder
+	}
+	aClone.events = event.Dispatcher{}
+	aClone.inputs = append([]common.Direction(nil), aGameState.inputs...)
+	aClone.pendingCandies = append([]int(nil), aGameState.pendingCandies...)
+	aClone.players = nil
+	for _, aPlayer := range aGameState.players {
+		playerClone := *aPlayer
+		playerClone.inputs = append([]common.Direction(nil), aPlayer.inputs...)
+		aClone.players = append(aClone.players, &playerClone)
+	}
+	if aGameState.deaths != nil {
+		aClone.deaths = make(map[common.SnakeID]common.GameOverReason, len(aGameState.deaths))
+		for id, reason := range aGameState.deaths {
+			aClone.deaths[id] = reason
+		}
+	}
+	if aGameState.lastDiff != nil {
+		aDiff := *aGameState.lastDiff
+		aClone.lastDiff = &aDiff
+	}
+
+	return &aClone, nil
+}
+
+// Deterministic tells whether the game always plays the same given the
+// same inputs, that is its random source is seeded by WithSeed
+func (aGameState *gameState) Deterministic() bool {
+	return aGameState.GameBoarder != nil && aGameState.GameBoarder.Deterministic()
+}
+
+// resultSnapshot returns the result of the game, nil until it is over
+func (aGameState *gameState) resultSnapshot() *common.GameResult {
+	if aResult, ok := aGameState.Result(); ok {
+		return &aResult
+	}
+
+	return nil
+}
+
+// playerSnapshots returns the saved state of the added snakes
+func (aGameState *gameState) playerSnapshots() (players []snapshot.Player) {
+	for i, aPlayer := range aGameState.players {
+		players = append(players, snapshot.Player{
+			ID: common.SnakeID(i + 1),
+			Start: snapshot.Snake{
+				Body:      []common.Position{aPlayer.start.position},
+				Direction: aPlayer.start.direction,
+			},
+			Score:  aPlayer.score,
+			Inputs: append([]common.Direction(nil), aPlayer.inputs...),
+		})
+	}
+
+	return players
+}
+
+// deathSnapshots returns the deaths sorted by snake ID
+func (aGameState *gameState) deathSnapshots() (deaths []snapshot.Death) {
+	for id, reason := range aGameState.deaths {
+		deaths = append(deaths, snapshot.Death{
+			ID:     id,
+			Reason: reason,
+		})
+	}
+	sort.Slice(deaths, func(i, j int) bool { return deaths[i].ID < deaths[j].ID })
+
+	return deaths
+}
+
+func (aGameState *gameState) Restore(aSnapshot snapshot.Game) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aSnapshot.Version != snapshot.Version {
+		return fmt.Errorf("%w: version %d", snapshot.ErrUnsupported, aSnapshot.Version)
+	}
+
+	aGameBoard := gameboard.New(aGameState.boardOptions...)
+	if err = aGameBoard.Restore(aSnapshot.Board); err != nil {
+		return err
+	}
+	// Only a game with several snakes goes on once the snake 0 is removed
+	if aSnapshot.GameInProgress && len(aSnapshot.Players) == 0 && len(aSnapshot.Board.Snake.Body) == 0 {
+		return fmt.Errorf("%w: the snake has no body", gameboard.ErrInvalidSnapshot)
+	}
+	var players []*player
+	for i, playerSnapshot := range aSnapshot.Players {
+		if playerSnapshot.ID != common.SnakeID(i+1) || len(playerSnapshot.Start.Body) != 1 {
+			return snapshot.ErrUnsupported
+		}
+		players = append(players, &player{
+			start: snakeStart{
+				position:  playerSnapshot.Start.Body[0],
+				direction: playerSnapshot.Start.Direction,
+			},
+			inputs: append([]common.Direction(nil), playerSnapshot.Inputs...),
+			score:  playerSnapshot.Score,
+		})
+	}
+	var deaths map[common.SnakeID]common.GameOverReason
+	for _, aDeath := range aSnapshot.Deaths {
+		if deaths == nil {
+			deaths = make(map[common.SnakeID]common.GameOverReason)
+		}
+		deaths[aDeath.ID] = aDeath.Reason
+	}
+
+	aGameState.GameBoarder = aGameBoard
+	aGameState.gameInProgress = aSnapshot.GameInProgress
+	aGameState.round = aSnapshot.Round
+	aGameState.score = aSnapshot.Score
+	aGameState.highScore = aSnapshot.HighScore
+	aGameState.dirty = aSnapshot.Dirty
+	aGameState.inputs = append([]common.Direction(nil), aSnapshot.Inputs...)
+	aGameState.pendingCandies = append([]int(nil), aSnapshot.PendingCandies...)
+	aGameState.speedRounds = aSnapshot.SpeedRounds
+	aGameState.players = players
+	aGameState.deaths = deaths
+	aGameState.gameOverReason = common.NotOver
+	aGameState.outcome = common.Undecided
+	aGameState.result = common.GameResult{}
+	if aSnapshot.Result != nil {
+		aGameState.outcome = aSnapshot.Result.Outcome
+		aGameState.result = *aSnapshot.Result
+		aGameState.gameOverReason = aSnapshot.Result.Reason
+	}
+	aGameState.candiesEaten = aSnapshot.CandiesEaten
+	aGameState.paused = aSnapshot.Paused
+	aGameState.lastDiff = nil
+	return nil
 }
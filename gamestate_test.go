@@ -1,11 +1,18 @@
 package gamestate
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Amari-Mecheri/GoSnakeLogic/mocks"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/difficulty"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/event"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/gameboard"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
 
 	"github.com/stretchr/testify/require"
@@ -60,6 +67,7 @@ func TestGameState_InitBoard(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			if tt.wantMock {
@@ -140,6 +148,7 @@ func TestGameState_CreateObjects(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoarder,
 			}
 			if tt.wantMock {
@@ -242,16 +251,20 @@ func TestGameState_Play(t *testing.T) {
 		mockBoardSize      common.Size
 		mockOldValue       rune
 		mockIsSnakePart    bool
+		mockIsWall         bool
+		mockIsObstacle     bool
 		mockIsCandyBody    bool
-		mockIsCandyAlive   bool
+		mockCandyCount     int
 		mockSnakePosition  common.Position
 		mockCandyPosition  common.Position
 		mockListSprite     []common.Sprite
 		mockErr            error
 		wantGameInProgress bool
+		wantGameOverReason common.GameOverReason
 		wantListSprite     []common.Sprite
 		wantScore          int
 		wantHighScore      int
+		wantEvents         []event.Event
 		wantErrType        error
 		wantErr            bool
 	}{
@@ -268,6 +281,8 @@ func TestGameState_Play(t *testing.T) {
 			wantMock:           true,
 			mockErr:            gameboard.ErrInvalidPosition,
 			wantGameInProgress: false,
+			wantGameOverReason: common.InternalError,
+			wantEvents:         []event.Event{{Kind: event.GameOver, Round: 1, Reason: common.InternalError}},
 			wantErrType:        gameboard.ErrInvalidPosition,
 			wantErr:            true,
 		},
@@ -283,7 +298,7 @@ func TestGameState_Play(t *testing.T) {
 			mockCandyPosition: testdata.Position1_1,
 			mockIsSnakePart:   false,
 			mockIsCandyBody:   false,
-			mockIsCandyAlive:  true,
+			mockCandyCount:    1,
 			mockListSprite: []common.Sprite{
 				{
 					Value:    gameboard.SnakePart,
@@ -319,7 +334,7 @@ func TestGameState_Play(t *testing.T) {
 			mockCandyPosition: testdata.Position1_1,
 			mockIsSnakePart:   true, // The snake ate itself
 			mockIsCandyBody:   false,
-			mockIsCandyAlive:  true,
+			mockCandyCount:    1,
 			mockListSprite: []common.Sprite{
 				{
 					Value:    gameboard.SnakePart,
@@ -331,6 +346,15 @@ func TestGameState_Play(t *testing.T) {
 				},
 			},
 			wantGameInProgress: false, // Then the game is over
+			wantGameOverReason: common.SelfCollision,
+			wantEvents: []event.Event{
+				{
+					Kind:     event.GameOver,
+					Round:    1,
+					Position: testdata.Position0_0,
+					Reason:   common.SelfCollision,
+				},
+			},
 			wantListSprite: []common.Sprite{
 				{
 					Value:    gameboard.SnakePart,
@@ -343,6 +367,44 @@ func TestGameState_Play(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "TestHitWall",
+			fields: fields{
+				gameInProgress: true,
+			},
+			wantMock:           true,
+			mockOldValue:       gameboard.WallBody,
+			mockIsWall:         true, // The snake hit a wall edge
+			wantGameInProgress: false,
+			wantGameOverReason: common.WallCollision,
+			wantEvents: []event.Event{
+				{
+					Kind:   event.GameOver,
+					Round:  1,
+					Reason: common.WallCollision,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "TestHitObstacle",
+			fields: fields{
+				gameInProgress: true,
+			},
+			wantMock:           true,
+			mockOldValue:       gameboard.ObstacleBody,
+			mockIsObstacle:     true, // The snake hit an obstacle
+			wantGameInProgress: false,
+			wantGameOverReason: common.ObstacleCollision,
+			wantEvents: []event.Event{
+				{
+					Kind:   event.GameOver,
+					Round:  1,
+					Reason: common.ObstacleCollision,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "TestEatTheCandyScore1HighSCore10",
 			fields: fields{
@@ -350,16 +412,35 @@ func TestGameState_Play(t *testing.T) {
 				score:          1,
 				highScore:      10,
 			},
-			wantMock:          true,
-			wantScore:         2,
-			wantHighScore:     10,
+			wantMock:      true,
+			wantScore:     2,
+			wantHighScore: 10,
+			wantEvents: []event.Event{
+				{
+					Kind:     event.CandyEaten,
+					Round:    1,
+					Position: testdata.Position0_0,
+					Score:    1,
+				},
+				{
+					Kind:  event.ScoreChanged,
+					Round: 1,
+					Score: 2,
+				},
+				{
+					Kind:     event.CandySpawned,
+					Round:    1,
+					Position: testdata.Position1_1,
+					Score:    2,
+				},
+			},
 			mockBoardSize:     testdata.Size0_0,
 			mockOldValue:      0,
 			mockSnakePosition: testdata.Position0_0,
 			mockCandyPosition: testdata.Position1_1,
 			mockIsSnakePart:   false,
-			mockIsCandyBody:   true,  // The snake ate the candy
-			mockIsCandyAlive:  false, // No more candies, a new one shall be generated
+			mockIsCandyBody:   true, // The snake ate the candy
+			mockCandyCount:    0,    // No more candies, a new one shall be generated
 			mockListSprite: []common.Sprite{
 				{
 					Value:    gameboard.SnakePart,
@@ -403,16 +484,40 @@ func TestGameState_Play(t *testing.T) {
 				score:          10,
 				highScore:      10,
 			},
-			wantMock:          true,
-			wantScore:         11,
-			wantHighScore:     11,
+			wantMock:      true,
+			wantScore:     11,
+			wantHighScore: 11,
+			wantEvents: []event.Event{
+				{
+					Kind:     event.CandyEaten,
+					Round:    1,
+					Position: testdata.Position0_0,
+					Score:    10,
+				},
+				{
+					Kind:  event.ScoreChanged,
+					Round: 1,
+					Score: 11,
+				},
+				{
+					Kind:  event.HighScoreChanged,
+					Round: 1,
+					Score: 11,
+				},
+				{
+					Kind:     event.CandySpawned,
+					Round:    1,
+					Position: testdata.Position1_1,
+					Score:    11,
+				},
+			},
 			mockBoardSize:     testdata.Size0_0,
 			mockOldValue:      0,
 			mockSnakePosition: testdata.Position0_0,
 			mockCandyPosition: testdata.Position1_1,
 			mockIsSnakePart:   false,
-			mockIsCandyBody:   true,  // The snake ate the candy
-			mockIsCandyAlive:  false, // No more candies, a new one shall be generated
+			mockIsCandyBody:   true, // The snake ate the candy
+			mockCandyCount:    0,    // No more candies, a new one shall be generated
 			mockListSprite: []common.Sprite{
 				{
 					Value:    gameboard.SnakePart,
@@ -458,19 +563,25 @@ func TestGameState_Play(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			if tt.wantMock {
 				aGameBoard := &mocks.GameBoarder{}
 				aGameBoard.On("BoardSize").Return(tt.mockBoardSize)
+				aGameBoard.On("SnakePosition").Return(tt.mockSnakePosition, nil)
+				aGameBoard.On("SnakeSize").Return(1, nil)
+				aGameBoard.On("SnakeDirection").Return(goRight, nil)
 				aGameBoard.On("MoveSnake").Return(
 					tt.mockOldValue,
 					tt.mockListSprite,
 					tt.mockErr,
 				)
 				aGameBoard.On("IsSnakePart", tt.mockOldValue).Return(tt.mockIsSnakePart)
+				aGameBoard.On("IsWall", tt.mockOldValue).Return(tt.mockIsWall)
+				aGameBoard.On("IsObstacle", tt.mockOldValue).Return(tt.mockIsObstacle)
 				aGameBoard.On("IsCandy", tt.mockOldValue).Return(tt.mockIsCandyBody)
-				aGameBoard.On("CandyAlive").Return(tt.mockIsCandyAlive)
+				aGameBoard.On("CandyCount").Return(tt.mockCandyCount)
 				aGameBoard.On("CreateCandy").Return(
 					common.Sprite{
 						Value:    gameboard.CandyBody,
@@ -478,9 +589,14 @@ func TestGameState_Play(t *testing.T) {
 					},
 					tt.mockErr,
 				)
-				aGameBoard.On("RemoveCandy").Return()
+				aGameBoard.On("RemoveCandyAt", tt.mockSnakePosition).Return(common.RegularCandy, nil)
+				aGameBoard.On("ExpireCandies").Return(nil, nil)
 				aGameState.GameBoarder = aGameBoard
 			}
+			var gotEvents []event.Event
+			aGameState.Subscribe(func(anEvent event.Event) {
+				gotEvents = append(gotEvents, anEvent)
+			})
 			gotListSprite, err := aGameState.Play()
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr, err)
@@ -492,6 +608,8 @@ func TestGameState_Play(t *testing.T) {
 			gotHighScore := aGameState.HighScore()
 			require.Equal(t, tt.wantScore, gotScore, "gotScore")
 			require.Equal(t, tt.wantHighScore, gotHighScore, "gotHighScore")
+			require.Equal(t, tt.wantGameOverReason, aGameState.GameOverReason(), "GameOverReason")
+			require.Equal(t, tt.wantEvents, gotEvents, "gotEvents")
 		})
 	}
 }
@@ -533,6 +651,7 @@ func TestGameState_GameInProgress(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			gotGameInProgress := aGameState.GameInProgress()
@@ -578,6 +697,7 @@ func TestGameState_Dirty(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			gotDirty := aGameState.Dirty()
@@ -616,6 +736,7 @@ func TestGameState_HighestScore(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			gotHighSCore := aGameState.HighScore()
@@ -654,6 +775,7 @@ func TestGameState_Score(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			gotScore := aGameState.Score()
@@ -692,6 +814,7 @@ func TestGameState_Round(t *testing.T) {
 				score:          tt.fields.score,
 				highScore:      tt.fields.highScore,
 				dirty:          tt.fields.dirty,
+				candyRule:      common.CandyRule{Count: 1},
 				GameBoarder:    tt.fields.GameBoard,
 			}
 			gotRound := aGameState.Round()
@@ -705,3 +828,1259 @@ func TestNew(t *testing.T) {
 	var got = New()
 	require.IsType(t, wantType, got)
 }
+
+func TestGameState_WithSeed(t *testing.T) {
+	// Two games sharing a seed produce the same sprites
+	tests := []struct {
+		name      string
+		seed1     int64
+		seed2     int64
+		wantEqual bool
+	}{
+		{
+			name:      "TestSameSeed",
+			seed1:     11,
+			seed2:     11,
+			wantEqual: true,
+		},
+		{
+			name:      "TestOtherSeed",
+			seed1:     11,
+			seed2:     12,
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := common.Size{
+				Width:  30,
+				Height: 30,
+			}
+			play := func(seed int64) (listSprite []common.Sprite) {
+				aGameState := New(WithSeed(seed))
+				require.NoError(t, aGameState.InitBoard(size))
+				sprites, err := aGameState.CreateObjects()
+				require.NoError(t, err)
+				listSprite = append(listSprite, sprites...)
+				// A second board draws the same candies again
+				require.NoError(t, aGameState.InitBoard(size))
+				sprites, err = aGameState.CreateObjects()
+				require.NoError(t, err)
+				require.ElementsMatch(t, listSprite, sprites)
+				return append(listSprite, sprites...)
+			}
+			require.Equal(t, tt.wantEqual, reflect.DeepEqual(play(tt.seed1), play(tt.seed2)))
+		})
+	}
+
+	// The games created with the same option don't share their source
+	options := []Option{WithSeed(11)}
+	var candies [][]common.Position
+	for i := 0; i < 2; i++ {
+		aGameState := New(options...)
+		require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+		_, err := aGameState.CreateObjects()
+		require.NoError(t, err)
+		candies = append(candies, aGameState.CandyPositions())
+	}
+	require.Equal(t, candies[0], candies[1])
+}
+
+func TestGameState_WithSnakeStart(t *testing.T) {
+	tests := []struct {
+		name          string
+		options       []Option
+		wantPosition  common.Position
+		wantDirection common.Direction
+	}{
+		{
+			name:          "TestDefaultStart", // The center of the board heading right
+			wantPosition:  testdata.Position2_2,
+			wantDirection: goRight,
+		},
+		{
+			name:          "TestCustomStart",
+			options:       []Option{WithSnakeStart(testdata.Position0_3, goUp)},
+			wantPosition:  testdata.Position0_3,
+			wantDirection: goUp,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := New(tt.options...)
+			require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+			_, err := aGameState.CreateObjects()
+			require.NoError(t, err)
+			gotPosition, err := aGameState.SnakePosition()
+			require.NoError(t, err)
+			gotDirection, err := aGameState.SnakeDirection()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPosition, gotPosition)
+			require.Equal(t, tt.wantDirection, gotDirection)
+		})
+	}
+}
+
+func TestGameState_SnapshotRestore(t *testing.T) {
+	// A restored game continues exactly like the original one
+	tests := []struct {
+		name   string
+		encode func(aSnapshot snapshot.Game) (decoded snapshot.Game)
+	}{
+		{
+			name: "TestInMemory",
+			encode: func(aSnapshot snapshot.Game) (decoded snapshot.Game) {
+				return aSnapshot
+			},
+		},
+		{
+			name: "TestJSON",
+			encode: func(aSnapshot snapshot.Game) (decoded snapshot.Game) {
+				data, err := json.Marshal(aSnapshot)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(data, &decoded))
+				return decoded
+			},
+		},
+		{
+			name: "TestBinary",
+			encode: func(aSnapshot snapshot.Game) (decoded snapshot.Game) {
+				data, err := aSnapshot.MarshalBinary()
+				require.NoError(t, err)
+				require.NoError(t, decoded.UnmarshalBinary(data))
+				return decoded
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			play := func(aGameState GameStater, rounds int) (listSprite []common.Sprite) {
+				for i := 0; i < rounds && aGameState.GameInProgress(); i++ {
+					if i%7 == 3 {
+						aGameState.MoveDown()
+					}
+					if i%7 == 4 {
+						aGameState.MoveRight()
+					}
+					sprites, err := aGameState.Play()
+					require.NoError(t, err)
+					listSprite = append(listSprite, sprites...)
+				}
+				return listSprite
+			}
+			original := New(WithSeed(8))
+			require.NoError(t, original.InitBoard(common.Size{
+				Width:  7,
+				Height: 7,
+			}))
+			_, err := original.CreateObjects()
+			require.NoError(t, err)
+			original.Start()
+			play(original, 20)
+
+			aSnapshot, err := original.Snapshot()
+			require.NoError(t, err)
+			restored := New()
+			require.NoError(t, restored.Restore(tt.encode(aSnapshot)))
+			require.Equal(t, original.Round(), restored.Round())
+			require.Equal(t, original.Score(), restored.Score())
+			require.Equal(t, play(original, 60), play(restored, 60))
+			require.Equal(t, original.Score(), restored.Score())
+		})
+	}
+}
+
+func TestGameState_Restore(t *testing.T) {
+	tests := []struct {
+		name        string
+		snapshot    snapshot.Game
+		wantErrType error
+	}{
+		{
+			name: "TestUnsupportedVersion",
+			snapshot: snapshot.Game{
+				Version: snapshot.Version + 1,
+			},
+			wantErrType: snapshot.ErrUnsupported,
+		},
+		{
+			name: "TestOldVersion",
+			snapshot: snapshot.Game{
+				Version: 1,
+			},
+			wantErrType: snapshot.ErrUnsupported,
+		},
+		{
+			name: "TestNoSnakeInProgress",
+			snapshot: snapshot.Game{
+				Version:        snapshot.Version,
+				GameInProgress: true,
+				Board: snapshot.Board{
+					Size:  testdata.Size3_3,
+					Cells: []string{"   ", "   ", "   "},
+				},
+			},
+			wantErrType: gameboard.ErrInvalidSnapshot,
+		},
+		{
+			name: "TestSnakeOutOfBoard",
+			snapshot: snapshot.Game{
+				Version:        snapshot.Version,
+				GameInProgress: true,
+				Board: snapshot.Board{
+					Size:  testdata.Size3_3,
+					Cells: []string{"   ", "   ", "   "},
+					Snake: snapshot.Snake{Body: []common.Position{{X: 50, Y: 50}}},
+				},
+			},
+			wantErrType: gameboard.ErrInvalidSnapshot,
+		},
+		{
+			name: "TestInvalidCells",
+			snapshot: snapshot.Game{
+				Version: snapshot.Version,
+				Board: snapshot.Board{
+					Size:  testdata.Size3_3,
+					Cells: []string{"   "},
+				},
+			},
+			wantErrType: gameboard.ErrInvalidSnapshot,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().Restore(tt.snapshot)
+			require.ErrorIs(t, err, tt.wantErrType)
+		})
+	}
+
+	// A game in progress restored into a finished one is not over anymore
+	aGameState := New(WithSeed(1), WithSnakeStart(testdata.Position0_0, goRight),
+		WithEdgePolicy(common.UniformEdges(common.WallEdge)))
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+	aSnapshot, err := aGameState.Snapshot()
+	require.NoError(t, err)
+	for aGameState.GameInProgress() {
+		_, err = aGameState.Play()
+		require.NoError(t, err)
+	}
+	require.Equal(t, common.WallCollision, aGameState.GameOverReason())
+
+	require.NoError(t, aGameState.Restore(aSnapshot))
+	require.True(t, aGameState.GameInProgress())
+	require.Equal(t, common.NotOver, aGameState.GameOverReason())
+	require.Equal(t, common.Undecided, aGameState.Outcome())
+}
+
+func TestGameState_EdgePolicy(t *testing.T) {
+	// The snake starts at 0,0 heading right and never turns.
+	// With the seed 1 the candy is at 1,3, out of its way
+	tests := []struct {
+		name               string
+		edgePolicy         common.EdgePolicy
+		wantGameInProgress bool
+		wantGameOverReason common.GameOverReason
+		wantPosition       common.Position
+		wantDirection      common.Direction
+	}{
+		{
+			name:               "TestWrap",
+			edgePolicy:         common.UniformEdges(common.WrapEdge),
+			wantGameInProgress: true,
+			wantGameOverReason: common.NotOver,
+			wantPosition:       common.Position{X: 2, Y: 0},
+			wantDirection:      goRight,
+		},
+		{
+			name:               "TestWall",
+			edgePolicy:         common.UniformEdges(common.WallEdge),
+			wantGameInProgress: false,
+			wantGameOverReason: common.WallCollision,
+			wantPosition:       testdata.Position3_0,
+			wantDirection:      goRight,
+		},
+		{
+			name:               "TestBounce", // The snake turns down at 3,0
+			edgePolicy:         common.UniformEdges(common.BounceEdge),
+			wantGameInProgress: true,
+			wantGameOverReason: common.NotOver,
+			wantPosition:       common.Position{X: 3, Y: 3},
+			wantDirection:      goDown,
+		},
+		{
+			name:               "TestWallVerticallyOnly",
+			edgePolicy:         common.MixedEdges(common.WrapEdge, common.WallEdge),
+			wantGameInProgress: true,
+			wantGameOverReason: common.NotOver,
+			wantPosition:       common.Position{X: 2, Y: 0},
+			wantDirection:      goRight,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := New(
+				WithSeed(1),
+				WithEdgePolicy(tt.edgePolicy),
+				WithSnakeStart(testdata.Position0_0, goRight),
+			)
+			require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+			_, err := aGameState.CreateObjects()
+			require.NoError(t, err)
+			aGameState.Start()
+			for i := 0; i < 6 && aGameState.GameInProgress(); i++ {
+				_, err = aGameState.Play()
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.edgePolicy, aGameState.EdgePolicy())
+			require.Equal(t, tt.wantGameInProgress, aGameState.GameInProgress())
+			require.Equal(t, tt.wantGameOverReason, aGameState.GameOverReason())
+			gotPosition, err := aGameState.SnakePosition()
+			require.NoError(t, err)
+			gotDirection, err := aGameState.SnakeDirection()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantPosition, gotPosition)
+			require.Equal(t, tt.wantDirection, gotDirection)
+		})
+	}
+}
+
+func TestGameState_BounceLongSnake(t *testing.T) {
+	// A snake across the board hits the right edge head-on then the top edge,
+	// it turns along them instead of going back into its neck
+	body := []common.Position{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}}
+	aGameState := restoreSnake(t, body, goRight, WithEdgePolicy(common.UniformEdges(common.BounceEdge)))
+	wantHeads := []common.Position{{X: 4, Y: 1}, {X: 4, Y: 0}, {X: 3, Y: 0}}
+	wantDirections := []common.Direction{goUp, goUp, goLeft}
+	for i := range wantHeads {
+		_, err := aGameState.Play()
+		require.NoError(t, err)
+		require.True(t, aGameState.GameInProgress(), aGameState.GameOverReason())
+		head, err := aGameState.SnakePosition()
+		require.NoError(t, err)
+		require.Equal(t, wantHeads[i], head)
+		direction, err := aGameState.SnakeDirection()
+		require.NoError(t, err)
+		require.Equal(t, wantDirections[i], direction)
+	}
+}
+
+// restoreSnake returns a game on a 5x5 free board holding the given snake
+func restoreSnake(t *testing.T, body []common.Position, direction common.Direction, options ...Option) GameStater {
+	cells := []string{"     ", "     ", "     ", "     ", "     "}
+	for _, position := range body {
+		row := []rune(cells[position.Y])
+		row[position.X] = gameboard.SnakePart
+		cells[position.Y] = string(row)
+	}
+	aGameState := New(options...)
+	require.NoError(t, aGameState.Restore(snapshot.Game{
+		Version:        snapshot.Version,
+		GameInProgress: true,
+		Board: snapshot.Board{
+			Size: common.Size{
+				Width:  5,
+				Height: 5,
+			},
+			Cells: cells,
+			Snake: snapshot.Snake{
+				Body:      body,
+				Direction: direction,
+			},
+		},
+	}))
+	return aGameState
+}
+
+func TestGameState_InputQueue(t *testing.T) {
+	longSnake := []common.Position{
+		{X: 0, Y: 2},
+		{X: 1, Y: 2},
+		{X: 2, Y: 2},
+	}
+	tests := []struct {
+		name           string
+		body           []common.Position
+		options        []Option
+		moves          []func(aGameState GameStater)
+		wantDirections []common.Direction // after each round
+	}{
+		{
+			name: "TestShortSnakeReverses", // A snake of one part has no neck
+			body: []common.Position{{X: 2, Y: 2}},
+			moves: []func(aGameState GameStater){
+				GameStater.MoveLeft,
+			},
+			wantDirections: []common.Direction{goLeft},
+		},
+		{
+			name: "TestLongSnakeDoesntReverse",
+			body: longSnake,
+			moves: []func(aGameState GameStater){
+				GameStater.MoveLeft,
+			},
+			wantDirections: []common.Direction{goRight},
+		},
+		{
+			name: "TestQuickTurns", // Both turns are kept, one per round
+			body: longSnake,
+			moves: []func(aGameState GameStater){
+				GameStater.MoveUp,
+				GameStater.MoveLeft,
+			},
+			wantDirections: []common.Direction{goUp, goLeft, goLeft},
+		},
+		{
+			name: "TestRepeatedIgnored",
+			body: longSnake,
+			moves: []func(aGameState GameStater){
+				GameStater.MoveRight,
+				GameStater.MoveUp,
+				GameStater.MoveUp,
+				GameStater.MoveLeft,
+			},
+			wantDirections: []common.Direction{goUp, goLeft},
+		},
+		{
+			name:    "TestDepth2",
+			body:    longSnake,
+			options: []Option{WithInputDepth(2)},
+			moves: []func(aGameState GameStater){
+				GameStater.MoveUp,
+				GameStater.MoveLeft,
+				GameStater.MoveDown, // Dropped, the queue is full
+			},
+			wantDirections: []common.Direction{goUp, goLeft, goLeft},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := restoreSnake(t, tt.body, goRight, tt.options...)
+			for _, move := range tt.moves {
+				move(aGameState)
+			}
+			var gotDirections []common.Direction
+			for range tt.wantDirections {
+				_, err := aGameState.Play()
+				require.NoError(t, err)
+				require.True(t, aGameState.GameInProgress())
+				direction, err := aGameState.SnakeDirection()
+				require.NoError(t, err)
+				gotDirections = append(gotDirections, direction)
+			}
+			require.Equal(t, tt.wantDirections, gotDirections)
+		})
+	}
+}
+
+func TestGameState_CandyRule(t *testing.T) {
+	// The snake starts at 0,0 heading right toward 3 candies in a row
+	// and eats one of them every round
+	tests := []struct {
+		name            string
+		candyRule       common.CandyRule
+		wantCandyRule   common.CandyRule
+		wantCandyCounts []int
+	}{
+		{
+			name:            "TestCountDefaultsToOne", // Extra candies are not replaced
+			candyRule:       common.CandyRule{},
+			wantCandyRule:   common.CandyRule{Count: 1},
+			wantCandyCounts: []int{2, 1, 1},
+		},
+		{
+			name:            "TestImmediate",
+			candyRule:       common.CandyRule{Count: 3},
+			wantCandyRule:   common.CandyRule{Count: 3},
+			wantCandyCounts: []int{3, 3, 3},
+		},
+		{
+			name:            "TestBatch",
+			candyRule:       common.CandyRule{Count: 3, Respawn: common.RespawnBatch},
+			wantCandyRule:   common.CandyRule{Count: 3, Respawn: common.RespawnBatch},
+			wantCandyCounts: []int{2, 1, 3},
+		},
+		{
+			name:            "TestDelayed", // The candy eaten in round 1 comes back in round 3
+			candyRule:       common.CandyRule{Count: 3, Respawn: common.RespawnDelayed, Delay: 2},
+			wantCandyRule:   common.CandyRule{Count: 3, Respawn: common.RespawnDelayed, Delay: 2},
+			wantCandyCounts: []int{2, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := New(WithSeed(1), WithCandyRule(tt.candyRule))
+			require.Equal(t, tt.wantCandyRule, aGameState.CandyRule())
+			require.NoError(t, aGameState.Restore(snapshot.Game{
+				Version:        snapshot.Version,
+				GameInProgress: true,
+				Board: snapshot.Board{
+					Size:  testdata.Size4_4,
+					Cells: []string{"S***", "    ", "    ", "    "},
+					Snake: snapshot.Snake{
+						Body:      []common.Position{testdata.Position0_0},
+						Direction: goRight,
+					},
+					Candies: []snapshot.Candy{
+						{Alive: true, Position: common.Position{X: 1, Y: 0}},
+						{Alive: true, Position: common.Position{X: 2, Y: 0}},
+						{Alive: true, Position: testdata.Position3_0},
+					},
+				},
+			}))
+			var gotCandyCounts []int
+			for i := range tt.wantCandyCounts {
+				_, err := aGameState.Play()
+				require.NoError(t, err)
+				require.Equal(t, i+1, aGameState.Score())
+				gotCandyCounts = append(gotCandyCounts, aGameState.(*gameState).CandyCount())
+			}
+			require.Equal(t, tt.wantCandyCounts, gotCandyCounts)
+		})
+	}
+}
+
+func TestGameState_CandyKinds(t *testing.T) {
+	// The snake starts at 0,0 heading right, its body goes down to 0,2.
+	// The candy at 1,0 is eaten by the first round
+	doublePoints := func(kind common.CandyKind, properties candy.Properties) Effect {
+		effect := DefaultEffectRule(kind, properties)
+		effect.Points *= 2
+		return effect
+	}
+	tests := []struct {
+		name               string
+		options            []Option
+		candy              snapshot.Candy
+		wantScore          int
+		wantSize           int
+		wantSpeedRounds    int
+		wantGameInProgress bool
+		wantGameOverReason common.GameOverReason
+		wantEvent          event.Event
+	}{
+		{
+			name:               "TestRegular",
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 1, Y: 0}},
+			wantScore:          1,
+			wantSize:           4,
+			wantGameInProgress: true,
+			wantEvent:          event.Event{Kind: event.CandyEaten, Round: 1, Position: common.Position{X: 1, Y: 0}},
+		},
+		{
+			name:               "TestGolden",
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 1, Y: 0}, Kind: common.GoldenCandy, Lifetime: 5},
+			wantScore:          5,
+			wantSize:           4,
+			wantGameInProgress: true,
+			wantEvent:          event.Event{Kind: event.CandyEaten, Round: 1, Position: common.Position{X: 1, Y: 0}, Candy: common.GoldenCandy},
+		},
+		{
+			name:               "TestShrink",
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 1, Y: 0}, Kind: common.ShrinkCandy},
+			wantScore:          1,
+			wantSize:           1,
+			wantGameInProgress: true,
+			wantEvent:          event.Event{Kind: event.CandyEaten, Round: 1, Position: common.Position{X: 1, Y: 0}, Candy: common.ShrinkCandy},
+		},
+		{
+			name:               "TestSpeed",
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 1, Y: 0}, Kind: common.SpeedCandy},
+			wantScore:          1,
+			wantSize:           4,
+			wantSpeedRounds:    20,
+			wantGameInProgress: true,
+			wantEvent:          event.Event{Kind: event.CandyEaten, Round: 1, Position: common.Position{X: 1, Y: 0}, Candy: common.SpeedCandy},
+		},
+		{
+			name:               "TestPoison",
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 1, Y: 0}, Kind: common.PoisonCandy},
+			wantScore:          0,
+			wantSize:           4,
+			wantGameInProgress: false,
+			wantGameOverReason: common.Poisoned,
+			wantEvent:          event.Event{Kind: event.GameOver, Round: 1, Position: common.Position{X: 1, Y: 0}, Reason: common.Poisoned},
+		},
+		{
+			name:               "TestEffectRule",
+			options:            []Option{WithEffectRule(doublePoints)},
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 1, Y: 0}},
+			wantScore:          2,
+			wantSize:           4,
+			wantGameInProgress: true,
+			wantEvent:          event.Event{Kind: event.CandyEaten, Round: 1, Position: common.Position{X: 1, Y: 0}},
+		},
+		{
+			name:               "TestExpired", // The candy is out of the way
+			candy:              snapshot.Candy{Alive: true, Position: common.Position{X: 3, Y: 3}, Kind: common.GoldenCandy, Lifetime: 1},
+			wantScore:          0,
+			wantSize:           3,
+			wantGameInProgress: true,
+			wantEvent:          event.Event{Kind: event.CandyExpired, Round: 1, Position: common.Position{X: 3, Y: 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := []string{"S   ", "S   ", "S   ", "    "}
+			row := []rune(cells[tt.candy.Position.Y])
+			row[tt.candy.Position.X] = candy.DefaultRegistry()[tt.candy.Kind].Body
+			cells[tt.candy.Position.Y] = string(row)
+
+			aGameState := New(append(tt.options, WithSeed(1))...)
+			require.NoError(t, aGameState.Restore(snapshot.Game{
+				Version:        snapshot.Version,
+				GameInProgress: true,
+				Board: snapshot.Board{
+					Size:  testdata.Size4_4,
+					Cells: cells,
+					Snake: snapshot.Snake{
+						Body: []common.Position{
+							{X: 0, Y: 2},
+							testdata.Position0_1,
+							testdata.Position0_0,
+						},
+						Direction: goRight,
+					},
+					Candies: []snapshot.Candy{tt.candy},
+				},
+			}))
+			var gotEvents []event.Event
+			aGameState.Subscribe(func(anEvent event.Event) {
+				gotEvents = append(gotEvents, anEvent)
+			})
+			_, err := aGameState.Play()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantScore, aGameState.Score())
+			gotSize, err := aGameState.SnakeSize()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantSize, gotSize)
+			require.Equal(t, tt.wantSpeedRounds, aGameState.SpeedRounds())
+			require.Equal(t, tt.wantGameInProgress, aGameState.GameInProgress())
+			require.Equal(t, tt.wantGameOverReason, aGameState.GameOverReason())
+			require.Contains(t, gotEvents, tt.wantEvent)
+		})
+	}
+}
+
+func TestGameState_WithObstacles(t *testing.T) {
+	// The snake starts at 0,0 heading right, the obstacle at 2,0 is in its way
+	aGameState := New(
+		WithSeed(1),
+		WithSnakeStart(testdata.Position0_0, goRight),
+		WithObstacles(common.Position{X: 2, Y: 0}),
+	)
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	listSprite, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	require.Equal(t, common.Sprite{Value: gameboard.ObstacleBody, Position: common.Position{X: 2, Y: 0}}, listSprite[0])
+	require.Equal(t, []common.Position{{X: 2, Y: 0}}, aGameState.Obstacles())
+	aGameState.Start()
+	for i := 0; i < 3 && aGameState.GameInProgress(); i++ {
+		_, err = aGameState.Play()
+		require.NoError(t, err)
+	}
+	require.False(t, aGameState.GameInProgress())
+	require.Equal(t, common.ObstacleCollision, aGameState.GameOverReason())
+	require.Equal(t, 2, aGameState.Round())
+}
+
+func TestGameState_WithCandies(t *testing.T) {
+	tests := []struct {
+		name        string
+		candies     []common.Sprite
+		wantCount   int
+		wantErrType error
+	}{
+		{
+			name:      "TestOneFixedOneRandom",
+			candies:   []common.Sprite{{Value: '$', Position: testdata.Position0_0}},
+			wantCount: 2,
+		},
+		{
+			name:      "TestMoreThanTheCount", // The candy rule count doesn't remove any
+			candies:   []common.Sprite{{Value: '*', Position: testdata.Position0_0}, {Value: '*', Position: testdata.Position0_1}, {Value: '!', Position: testdata.Position1_1}},
+			wantCount: 3,
+		},
+		{
+			name:        "TestUnknownBody",
+			candies:     []common.Sprite{{Value: '?', Position: testdata.Position0_0}},
+			wantErrType: ErrUnknownCandy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := New(
+				WithSeed(1),
+				WithCandyRule(common.CandyRule{Count: 2}),
+				WithCandies(tt.candies...),
+			)
+			require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+			listSprite, err := aGameState.CreateObjects()
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.candies, listSprite[1:1+len(tt.candies)])
+			require.Equal(t, tt.wantCount, aGameState.(*gameState).CandyCount())
+		})
+	}
+}
+
+func TestGameState_FullBoard(t *testing.T) {
+	// The snake fills a 2x1 board by eating the candy, it can't be replaced
+	// until a cell is freed and the game goes on
+	for _, rule := range []common.CandyRule{
+		{Count: 1},
+		{Count: 1, Respawn: common.RespawnDelayed, Delay: 1},
+	} {
+		aGameState := New(
+			WithSeed(1),
+			WithCandyRule(rule),
+			WithCandies(common.Sprite{Value: '*', Position: common.Position{X: 1, Y: 0}}),
+			WithSnakeStart(testdata.Position0_0, goRight),
+		)
+		require.NoError(t, aGameState.InitBoard(common.Size{Width: 2, Height: 1}))
+		_, err := aGameState.CreateObjects()
+		require.NoError(t, err)
+		aGameState.Start()
+		for i := 0; i < 3; i++ {
+			_, err = aGameState.Play()
+			require.NoError(t, err)
+			require.True(t, aGameState.GameInProgress())
+			require.Equal(t, 0, aGameState.(*gameState).CandyCount())
+		}
+		require.Equal(t, 1, aGameState.Score())
+		if rule.Respawn == common.RespawnDelayed {
+			require.Len(t, aGameState.(*gameState).pendingCandies, 1)
+		}
+	}
+}
+
+func TestGameState_WithWinCondition(t *testing.T) {
+	// The snake fills a 2x1 board by eating the candy in the first round,
+	// then it follows its tail. The games are stopped after 10 rounds
+	tests := []struct {
+		name        string
+		win         common.WinCondition
+		wantRound   int
+		wantOutcome common.Outcome
+		wantReason  common.GameOverReason
+	}{
+		{
+			name:        "TestNoWin",
+			wantRound:   10,
+			wantOutcome: common.Undecided,
+		},
+		{
+			name:        "TestFillBoard",
+			win:         common.WinCondition{Goal: common.FillBoard},
+			wantRound:   1,
+			wantOutcome: common.Won,
+		},
+		{
+			name:        "TestReachScore",
+			win:         common.WinCondition{Goal: common.ReachScore, Target: 1},
+			wantRound:   1,
+			wantOutcome: common.Won,
+		},
+		{
+			name:        "TestSurviveRounds",
+			win:         common.WinCondition{Goal: common.SurviveRounds, Target: 3},
+			wantRound:   3,
+			wantOutcome: common.Won,
+		},
+		{
+			name:        "TestEatCandies",
+			win:         common.WinCondition{Goal: common.EatCandies, Target: 1, Rounds: 2},
+			wantRound:   1,
+			wantOutcome: common.Won,
+		},
+		{
+			name:        "TestEatCandiesTooLate",
+			win:         common.WinCondition{Goal: common.EatCandies, Target: 2, Rounds: 4},
+			wantRound:   4,
+			wantOutcome: common.Lost,
+			wantReason:  common.RoundLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameState := New(
+				WithSeed(1),
+				WithWinCondition(tt.win),
+				WithCandies(common.Sprite{Value: '*', Position: common.Position{X: 1, Y: 0}}),
+				WithSnakeStart(testdata.Position0_0, goRight),
+			)
+			require.Equal(t, tt.win, aGameState.WinCondition())
+			require.NoError(t, aGameState.InitBoard(common.Size{Width: 2, Height: 1}))
+			_, err := aGameState.CreateObjects()
+			require.NoError(t, err)
+			won := 0
+			aGameState.Subscribe(func(anEvent event.Event) {
+				if anEvent.Kind == event.GameWon {
+					won++
+				}
+			})
+			aGameState.Start()
+			for aGameState.GameInProgress() && aGameState.Round() < 10 {
+				_, err = aGameState.Play()
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRound, aGameState.Round())
+			require.Equal(t, tt.wantOutcome, aGameState.Outcome())
+			require.Equal(t, tt.wantReason, aGameState.GameOverReason())
+			require.Equal(t, tt.wantOutcome == common.Won, won == 1)
+			require.Equal(t, 1, aGameState.CandiesEaten())
+
+			aGameState.Start()
+			require.Equal(t, common.Undecided, aGameState.Outcome())
+			require.Equal(t, 0, aGameState.CandiesEaten())
+		})
+	}
+}
+
+func TestGameState_Result(t *testing.T) {
+	// The games are played on a 5x5 board with walls, the snake 0 starts at 3,2 going right
+	tests := []struct {
+		name       string
+		options    []Option
+		wantResult common.GameResult
+	}{
+		{
+			name: "TestWall",
+			wantResult: common.GameResult{
+				Outcome: common.Lost,
+				Reason:  common.WallCollision,
+				Round:   2,
+				Length:  1,
+				Killer:  common.Position{X: 5, Y: 2},
+			},
+		},
+		{
+			name:    "TestObstacle",
+			options: []Option{WithObstacles(common.Position{X: 4, Y: 2})},
+			wantResult: common.GameResult{
+				Outcome: common.Lost,
+				Reason:  common.ObstacleCollision,
+				Round:   1,
+				Length:  1,
+				Killer:  common.Position{X: 4, Y: 2},
+			},
+		},
+		{
+			name:    "TestPoison",
+			options: []Option{WithCandies(common.Sprite{Value: '!', Position: common.Position{X: 4, Y: 2}})},
+			wantResult: common.GameResult{
+				Outcome: common.Lost,
+				Reason:  common.Poisoned,
+				Round:   1,
+				Length:  2,
+				Killer:  common.Position{X: 4, Y: 2},
+			},
+		},
+		{
+			name: "TestWon",
+			options: []Option{
+				WithCandies(common.Sprite{Value: '$', Position: common.Position{X: 4, Y: 2}}),
+				WithWinCondition(common.WinCondition{Goal: common.ReachScore, Target: 5}),
+			},
+			wantResult: common.GameResult{
+				Outcome: common.Won,
+				Round:   1,
+				Length:  2,
+				Score:   5,
+				Killer:  common.Position{X: 4, Y: 2},
+			},
+		},
+		{
+			name:    "TestLastSnake",
+			options: []Option{WithSnake(common.Position{X: 2, Y: 0}, goRight)},
+			wantResult: common.GameResult{
+				Outcome: common.Lost,
+				Reason:  common.WallCollision,
+				Round:   3,
+				Length:  1,
+				Killer:  common.Position{X: 5, Y: 0},
+				Snake:   1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{
+				WithSeed(1),
+				WithEdgePolicy(common.UniformEdges(common.WallEdge)),
+				WithCandyRule(common.CandyRule{Count: 1, Respawn: common.RespawnDelayed, Delay: 100}),
+				WithSnakeStart(common.Position{X: 3, Y: 2}, goRight),
+			}, tt.options...)
+			aGameState := New(options...)
+			require.NoError(t, aGameState.InitBoard(common.Size{Width: 5, Height: 5}))
+			_, err := aGameState.CreateObjects()
+			require.NoError(t, err)
+			aGameState.Start()
+			for aGameState.GameInProgress() {
+				_, ok := aGameState.Result()
+				require.False(t, ok)
+				_, err = aGameState.Play()
+				require.NoError(t, err)
+			}
+
+			gotResult, ok := aGameState.Result()
+			require.True(t, ok)
+			require.Equal(t, tt.wantResult, gotResult)
+
+			// The result is saved with the game
+			aSnapshot, err := aGameState.Snapshot()
+			require.NoError(t, err)
+			restored := New(options...)
+			require.NoError(t, restored.Restore(aSnapshot))
+			gotResult, ok = restored.Result()
+			require.True(t, ok)
+			require.Equal(t, tt.wantResult, gotResult)
+			require.Equal(t, tt.wantResult.Reason, restored.GameOverReason())
+		})
+	}
+}
+
+func TestGameState_WithSnake(t *testing.T) {
+	// The snake 0 eats the candy at 1,4 while the snake 1 runs into the right wall.
+	// The candy respawns too late to be in the way
+	aGameState := New(
+		WithSeed(1),
+		WithEdgePolicy(common.UniformEdges(common.WallEdge)),
+		WithCandyRule(common.CandyRule{Count: 1, Respawn: common.RespawnDelayed, Delay: 100}),
+		WithCandies(common.Sprite{Value: '*', Position: common.Position{X: 1, Y: 4}}),
+		WithSnakeStart(common.Position{X: 0, Y: 4}, goRight),
+		WithSnake(common.Position{X: 5, Y: 0}, goRight),
+	)
+	var gotDeaths []event.Event
+	aGameState.Subscribe(func(anEvent event.Event) {
+		if anEvent.Kind == event.SnakeDied {
+			gotDeaths = append(gotDeaths, anEvent)
+		}
+	})
+	require.NoError(t, aGameState.InitBoard(common.Size{Width: 7, Height: 7}))
+	listSprite, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	require.Contains(t, listSprite, common.Sprite{Value: gameboard.SnakePart, Position: common.Position{X: 5, Y: 0}})
+	aGameState.Start()
+	require.Equal(t, []common.SnakeID{0, 1}, aGameState.SnakeIDs())
+
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	require.Equal(t, 1, aGameState.SnakeScore(0))
+	require.Equal(t, 0, aGameState.SnakeScore(1))
+	require.Equal(t, aGameState.Score(), aGameState.SnakeScore(0))
+
+	// A game with several snakes is saved with them
+	aSnapshot, err := aGameState.Snapshot()
+	require.NoError(t, err)
+	require.Len(t, aSnapshot.Players, 1)
+	restored := New()
+	require.NoError(t, restored.Restore(aSnapshot))
+	restoredSnapshot, err := restored.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, aSnapshot, restoredSnapshot)
+
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	require.True(t, aGameState.GameInProgress())
+	require.Equal(t, []common.SnakeID{0}, aGameState.SnakeIDs())
+	require.Equal(t, common.WallCollision, aGameState.SnakeGameOverReason(1))
+	require.Equal(t, common.NotOver, aGameState.SnakeGameOverReason(0))
+	aGameState.Steer(1, goDown) // Ignored once the snake is dead
+
+	// The game is over once the last snake dies
+	aGameState.Steer(0, goUp)
+	for i := 0; i < 10 && aGameState.GameInProgress(); i++ {
+		_, err = aGameState.Play()
+		require.NoError(t, err)
+	}
+	require.False(t, aGameState.GameInProgress())
+	require.Equal(t, common.WallCollision, aGameState.GameOverReason())
+	require.Equal(t, common.WallCollision, aGameState.SnakeGameOverReason(0))
+	require.Len(t, gotDeaths, 2)
+	require.Equal(t, common.SnakeID(1), gotDeaths[0].Snake)
+	require.Equal(t, common.SnakeID(0), gotDeaths[1].Snake)
+}
+
+func TestGameState_Clone(t *testing.T) {
+	newGame := func(options ...Option) GameStater {
+		aGameState := New(append(options,
+			WithSnakeStart(testdata.Position0_0, goRight),
+			WithSnake(common.Position{X: 0, Y: 5}, goRight),
+		)...)
+		require.NoError(t, aGameState.InitBoard(common.Size{Width: 10, Height: 10}))
+		_, err := aGameState.CreateObjects()
+		require.NoError(t, err)
+		aGameState.Start()
+		return aGameState
+	}
+	aGameState := newGame(WithSeed(4))
+	require.True(t, aGameState.Deterministic())
+	var gotEvents []event.Event
+	aGameState.Subscribe(func(anEvent event.Event) {
+		gotEvents = append(gotEvents, anEvent)
+	})
+	aGameState.MoveDown()
+	_, err := aGameState.Play()
+	require.NoError(t, err)
+	aGameState.Steer(1, goDown)
+
+	// The clone plays the same rounds as the game, without its subscribers
+	aClone, err := Clone(aGameState)
+	require.NoError(t, err)
+	gotEvents = nil
+	var cloneEvents []event.Event
+	aClone.Subscribe(func(anEvent event.Event) {
+		cloneEvents = append(cloneEvents, anEvent)
+	})
+	for i := 0; i < 20; i++ {
+		if i == 5 {
+			aGameState.Steer(0, goRight)
+			aClone.Steer(0, goRight)
+		}
+		want, err := aGameState.Play()
+		require.NoError(t, err)
+		got, err := aClone.Play()
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	require.NotEmpty(t, gotEvents)
+	require.Equal(t, gotEvents, cloneEvents)
+	want, err := aGameState.Snapshot()
+	require.NoError(t, err)
+	got, err := aClone.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// Playing the clone doesn't change the game
+	aClone.Steer(1, goUp)
+	_, err = aClone.Play()
+	require.NoError(t, err)
+	got, err = aGameState.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	require.False(t, newGame().Deterministic())
+	_, err = Clone(&mocks.GameStater{})
+	require.ErrorIs(t, err, ErrNotCloneable)
+}
+
+func BenchmarkGameState_Clone(b *testing.B) {
+	aGameState := New(WithSeed(1))
+	if err := aGameState.InitBoard(common.Size{Width: 100, Height: 100}); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := aGameState.CreateObjects(); err != nil {
+		b.Fatal(err)
+	}
+	aGameState.Start()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Clone(aGameState); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestGameState_WithDifficulty(t *testing.T) {
+	// A zero size takes the size of the level
+	aGameState := New(WithDifficulty(difficulty.Easy))
+	require.NoError(t, aGameState.InitBoard(common.Size{}))
+	require.Equal(t, difficulty.Easy.Size, aGameState.BoardSize())
+	require.Equal(t, difficulty.Easy.Edges, aGameState.EdgePolicy())
+	require.Equal(t, difficulty.Easy.Candies, aGameState.CandyRule().Count)
+
+	// A size given to InitBoard wins over the level
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	require.Equal(t, testdata.Size4_4, aGameState.BoardSize())
+}
+
+func TestGameState_SnapshotKeepsDraws(t *testing.T) {
+	// A game saved every round draws the same candies as one never saved
+	newGame := func() GameStater {
+		aGameState := New(WithSeed(5), WithSnakeStart(testdata.Position0_0, goRight))
+		require.NoError(t, aGameState.InitBoard(common.Size{Width: 10, Height: 10}))
+		_, err := aGameState.CreateObjects()
+		require.NoError(t, err)
+		aGameState.Start()
+		return aGameState
+	}
+	saved, reference := newGame(), newGame()
+	for round := 0; round < 300 && reference.GameInProgress(); round++ {
+		for _, aGameState := range []GameStater{saved, reference} {
+			// A serpentine through the rows
+			switch round % 10 {
+			case 9:
+				aGameState.MoveDown()
+			case 0:
+				aGameState.MoveRight()
+			}
+			_, err := aGameState.Play()
+			require.NoError(t, err)
+		}
+		_, err := saved.Snapshot()
+		require.NoError(t, err)
+		require.Equal(t, reference.CandyPositions(), saved.CandyPositions())
+	}
+	require.Greater(t, reference.Score(), 3)
+}
+
+func TestGameState_SteerInputQueue(t *testing.T) {
+	aGameState := New(
+		WithSnake(testdata.Position0_0, goRight),
+	)
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Steer(1, goRight) // The snake already goes right
+	aGameState.Steer(1, goDown)
+	aGameState.Steer(2, goDown) // Unknown snake
+	require.Empty(t, aGameState.(*gameState).inputs)
+	require.Equal(t, []common.Direction{goDown}, aGameState.(*gameState).players[0].inputs)
+}
+
+func TestGameState_Pause(t *testing.T) {
+	aGameState := New(
+		WithCandies(common.Sprite{Value: '*', Position: common.Position{X: 3, Y: 3}}),
+		WithSnakeStart(testdata.Position0_0, goRight),
+	)
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+
+	// Play is rejected while paused, the inputs are kept
+	aGameState.Pause()
+	aGameState.MoveDown()
+	_, err = aGameState.Play()
+	require.ErrorIs(t, err, ErrPaused)
+	require.True(t, aGameState.Paused())
+	require.True(t, aGameState.GameInProgress())
+	require.Equal(t, 0, aGameState.Round())
+
+	// A step plays one round with the queued input and stays paused
+	_, err = aGameState.Step()
+	require.NoError(t, err)
+	require.True(t, aGameState.Paused())
+	require.Equal(t, 1, aGameState.Round())
+	position, err := aGameState.SnakePosition()
+	require.NoError(t, err)
+	require.Equal(t, common.Position{X: 0, Y: 1}, position)
+
+	// The pause is saved
+	aSnapshot, err := aGameState.Snapshot()
+	require.NoError(t, err)
+	require.True(t, aSnapshot.Paused)
+	restored := New()
+	require.NoError(t, restored.Restore(aSnapshot))
+	require.True(t, restored.Paused())
+
+	aGameState.Resume()
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	require.False(t, aGameState.Paused())
+	require.Equal(t, 2, aGameState.Round())
+
+	// A new game is not paused
+	aGameState.Pause()
+	aGameState.Start()
+	require.False(t, aGameState.Paused())
+}
+
+func TestGameState_Debug(t *testing.T) {
+	aGameState := New(
+		WithCandies(common.Sprite{Value: '*', Position: common.Position{X: 3, Y: 3}}),
+		WithSnakeStart(testdata.Position0_0, goRight),
+	)
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	_, ok := aGameState.LastDiff()
+	require.False(t, ok)
+
+	aGameState.SetDebug(true)
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	aDiff, ok := aGameState.LastDiff()
+	require.True(t, ok)
+	require.Equal(t, 2, aDiff.Round)
+	require.Equal(t, []common.CellChange{
+		{
+			Position: common.Position{X: 1, Y: 0},
+			Before:   aGameState.SnakePart(),
+			After:    aGameState.FreeSpace(),
+		},
+		{
+			Position: common.Position{X: 2, Y: 0},
+			Before:   aGameState.FreeSpace(),
+			After:    aGameState.SnakePart(),
+		},
+	}, aDiff.Changes)
+	require.Equal(t, []string{"    ", "    ", "   *"}, aDiff.Before[1:])
+	require.Equal(t, aDiff.Before[1:], aDiff.After[1:])
+
+	aGameState.SetDebug(false)
+	_, ok = aGameState.LastDiff()
+	require.False(t, ok)
+}
+
+func TestGameState_TickInterval(t *testing.T) {
+	// The snake eats a golden candy in the first round then a speed candy
+	candyKinds := candy.DefaultRegistry()
+	golden := candyKinds[common.GoldenCandy].Body
+	speed := candyKinds[common.SpeedCandy].Body
+	aGameState := New(
+		WithCandyKinds(candyKinds),
+		WithDifficulty(difficulty.Level{
+			Edges:   common.UniformEdges(common.WallEdge),
+			Candies: 2,
+			Curve:   difficulty.Linear(difficulty.Score, 100*time.Millisecond, 5*time.Millisecond, 10*time.Millisecond),
+		}),
+		WithSnakeStart(testdata.Position0_0, goRight),
+		WithCandies(
+			common.Sprite{Value: golden, Position: common.Position{X: 1, Y: 0}},
+			common.Sprite{Value: speed, Position: common.Position{X: 2, Y: 0}},
+		),
+	)
+	require.Equal(t, common.UniformEdges(common.WallEdge), aGameState.EdgePolicy())
+	require.Equal(t, 2, aGameState.CandyRule().Count)
+	require.NoError(t, aGameState.InitBoard(testdata.Size4_4))
+	_, err := aGameState.CreateObjects()
+	require.NoError(t, err)
+	aGameState.Start()
+	require.Equal(t, 100*time.Millisecond, aGameState.TickInterval())
+
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	wantInterval := 100*time.Millisecond - time.Duration(aGameState.Score())*5*time.Millisecond
+	require.Equal(t, wantInterval, aGameState.TickInterval())
+
+	_, err = aGameState.Play()
+	require.NoError(t, err)
+	require.Greater(t, aGameState.SpeedRounds(), 0)
+	wantInterval = 100*time.Millisecond - time.Duration(aGameState.Score())*5*time.Millisecond
+	require.Equal(t, wantInterval/2, aGameState.TickInterval())
+
+	// Without a curve the interval is constant
+	require.Equal(t, difficulty.DefaultInterval, New(WithSpeedCurve(nil)).TickInterval())
+
+	// A curve going to 0 or below is held at the minimum, even halved
+	aCurve := func(aProgress difficulty.Progress) time.Duration {
+		return time.Duration(1-aProgress.Score) * time.Millisecond
+	}
+	aGameState = New(WithSpeedCurve(aCurve))
+	require.Equal(t, difficulty.MinInterval, aGameState.TickInterval())
+	aGameState.(*gameState).score = 5
+	require.Equal(t, difficulty.MinInterval, aGameState.TickInterval())
+	aGameState.(*gameState).speedRounds = 3
+	require.Equal(t, difficulty.MinInterval, aGameState.TickInterval())
+}
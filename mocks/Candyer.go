@@ -2,9 +2,14 @@
 
 package mocks
 
+import candy "github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
+
 import common "github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
 import mock "github.com/stretchr/testify/mock"
 
+import snapshot "github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+
 // Candyer is an autogenerated mock type for the Candyer type
 type Candyer struct {
 	mock.Mock
@@ -24,11 +29,55 @@ func (_m *Candyer) Alive() bool {
 	return r0
 }
 
+// Clone provides a mock function with given fields:
+func (_m *Candyer) Clone() candy.Candyer {
+	ret := _m.Called()
+
+	var r0 candy.Candyer
+	if rf, ok := ret.Get(0).(func() candy.Candyer); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(candy.Candyer)
+		}
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: newPosition
 func (_m *Candyer) Init(newPosition common.Position) {
 	_m.Called(newPosition)
 }
 
+// Kind provides a mock function with given fields:
+func (_m *Candyer) Kind() common.CandyKind {
+	ret := _m.Called()
+
+	var r0 common.CandyKind
+	if rf, ok := ret.Get(0).(func() common.CandyKind); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.CandyKind)
+	}
+
+	return r0
+}
+
+// Lifetime provides a mock function with given fields:
+func (_m *Candyer) Lifetime() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // Position provides a mock function with given fields:
 func (_m *Candyer) Position() common.Position {
 	ret := _m.Called()
@@ -47,3 +96,41 @@ func (_m *Candyer) Position() common.Position {
 func (_m *Candyer) Remove() {
 	_m.Called()
 }
+
+// Restore provides a mock function with given fields: aSnapshot
+func (_m *Candyer) Restore(aSnapshot snapshot.Candy) {
+	_m.Called(aSnapshot)
+}
+
+// SetKind provides a mock function with given fields: kind, lifetime
+func (_m *Candyer) SetKind(kind common.CandyKind, lifetime int) {
+	_m.Called(kind, lifetime)
+}
+
+// Snapshot provides a mock function with given fields:
+func (_m *Candyer) Snapshot() snapshot.Candy {
+	ret := _m.Called()
+
+	var r0 snapshot.Candy
+	if rf, ok := ret.Get(0).(func() snapshot.Candy); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(snapshot.Candy)
+	}
+
+	return r0
+}
+
+// Tick provides a mock function with given fields:
+func (_m *Candyer) Tick() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
@@ -6,11 +6,34 @@ import common "github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
 
 import mock "github.com/stretchr/testify/mock"
 
+import snapshot "github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+
 // GameBoarder is an autogenerated mock type for the GameBoarder type
 type GameBoarder struct {
 	mock.Mock
 }
 
+// AddSnake provides a mock function with given fields: id, position, direction
+func (_m *GameBoarder) AddSnake(id common.SnakeID, position common.Position, direction common.Direction) (common.Sprite, error) {
+	ret := _m.Called(id, position, direction)
+
+	var r0 common.Sprite
+	if rf, ok := ret.Get(0).(func(common.SnakeID, common.Position, common.Direction) common.Sprite); ok {
+		r0 = rf(id, position, direction)
+	} else {
+		r0 = ret.Get(0).(common.Sprite)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.SnakeID, common.Position, common.Direction) error); ok {
+		r1 = rf(id, position, direction)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BoardSize provides a mock function with given fields:
 func (_m *GameBoarder) BoardSize() common.Size {
 	ret := _m.Called()
@@ -25,34 +48,57 @@ func (_m *GameBoarder) BoardSize() common.Size {
 	return r0
 }
 
-// CandyAlive provides a mock function with given fields:
-func (_m *GameBoarder) CandyAlive() bool {
+// CandyCount provides a mock function with given fields:
+func (_m *GameBoarder) CandyCount() int {
 	ret := _m.Called()
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func() bool); ok {
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(int)
 	}
 
 	return r0
 }
 
-// CandyPosition provides a mock function with given fields:
-func (_m *GameBoarder) CandyPosition() common.Position {
+// CandyPositions provides a mock function with given fields:
+func (_m *GameBoarder) CandyPositions() []common.Position {
 	ret := _m.Called()
 
-	var r0 common.Position
-	if rf, ok := ret.Get(0).(func() common.Position); ok {
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(common.Position)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
 	}
 
 	return r0
 }
 
+// Cell provides a mock function with given fields: position
+func (_m *GameBoarder) Cell(position common.Position) (rune, error) {
+	ret := _m.Called(position)
+
+	var r0 rune
+	if rf, ok := ret.Get(0).(func(common.Position) rune); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(rune)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateCandy provides a mock function with given fields:
 func (_m *GameBoarder) CreateCandy() (common.Sprite, error) {
 	ret := _m.Called()
@@ -95,6 +141,85 @@ func (_m *GameBoarder) CreateSnake(position common.Position, direction common.Di
 	return r0, r1
 }
 
+// Deterministic provides a mock function with given fields:
+func (_m *GameBoarder) Deterministic() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EdgePolicy provides a mock function with given fields:
+func (_m *GameBoarder) EdgePolicy() common.EdgePolicy {
+	ret := _m.Called()
+
+	var r0 common.EdgePolicy
+	if rf, ok := ret.Get(0).(func() common.EdgePolicy); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.EdgePolicy)
+	}
+
+	return r0
+}
+
+// ExpireCandies provides a mock function with given fields:
+func (_m *GameBoarder) ExpireCandies() ([]common.Sprite, error) {
+	ret := _m.Called()
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func() []common.Sprite); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FreeCount provides a mock function with given fields:
+func (_m *GameBoarder) FreeCount() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// HeadOnRule provides a mock function with given fields:
+func (_m *GameBoarder) HeadOnRule() common.HeadOnRule {
+	ret := _m.Called()
+
+	var r0 common.HeadOnRule
+	if rf, ok := ret.Get(0).(func() common.HeadOnRule); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.HeadOnRule)
+	}
+
+	return r0
+}
+
 // InitGameBoard provides a mock function with given fields: size
 func (_m *GameBoarder) InitGameBoard(size common.Size) error {
 	ret := _m.Called(size)
@@ -123,6 +248,20 @@ func (_m *GameBoarder) IsCandy(ch rune) bool {
 	return r0
 }
 
+// IsObstacle provides a mock function with given fields: ch
+func (_m *GameBoarder) IsObstacle(ch rune) bool {
+	ret := _m.Called(ch)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(rune) bool); ok {
+		r0 = rf(ch)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // IsSnakePart provides a mock function with given fields: ch
 func (_m *GameBoarder) IsSnakePart(ch rune) bool {
 	ret := _m.Called(ch)
@@ -137,6 +276,20 @@ func (_m *GameBoarder) IsSnakePart(ch rune) bool {
 	return r0
 }
 
+// IsWall provides a mock function with given fields: ch
+func (_m *GameBoarder) IsWall(ch rune) bool {
+	ret := _m.Called(ch)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(rune) bool); ok {
+		r0 = rf(ch)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // MoveSnake provides a mock function with given fields:
 func (_m *GameBoarder) MoveSnake() (rune, []common.Sprite, error) {
 	ret := _m.Called()
@@ -167,6 +320,124 @@ func (_m *GameBoarder) MoveSnake() (rune, []common.Sprite, error) {
 	return r0, r1, r2
 }
 
+// MoveSnakes provides a mock function with given fields:
+func (_m *GameBoarder) MoveSnakes() ([]common.SnakeMove, []common.Sprite, error) {
+	ret := _m.Called()
+
+	var r0 []common.SnakeMove
+	if rf, ok := ret.Get(0).(func() []common.SnakeMove); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.SnakeMove)
+		}
+	}
+
+	var r1 []common.Sprite
+	if rf, ok := ret.Get(1).(func() []common.Sprite); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]common.Sprite)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NextPosition provides a mock function with given fields: position, direction
+func (_m *GameBoarder) NextPosition(position common.Position, direction common.Direction) (common.Position, common.Direction, bool) {
+	ret := _m.Called(position, direction)
+
+	var r0 common.Position
+	if rf, ok := ret.Get(0).(func(common.Position, common.Direction) common.Position); ok {
+		r0 = rf(position, direction)
+	} else {
+		r0 = ret.Get(0).(common.Position)
+	}
+
+	var r1 common.Direction
+	if rf, ok := ret.Get(1).(func(common.Position, common.Direction) common.Direction); ok {
+		r1 = rf(position, direction)
+	} else {
+		r1 = ret.Get(1).(common.Direction)
+	}
+
+	var r2 bool
+	if rf, ok := ret.Get(2).(func(common.Position, common.Direction) bool); ok {
+		r2 = rf(position, direction)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// Obstacles provides a mock function with given fields:
+func (_m *GameBoarder) Obstacles() []common.Position {
+	ret := _m.Called()
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	return r0
+}
+
+// PlaceCandy provides a mock function with given fields: position, kind
+func (_m *GameBoarder) PlaceCandy(position common.Position, kind common.CandyKind) (common.Sprite, error) {
+	ret := _m.Called(position, kind)
+
+	var r0 common.Sprite
+	if rf, ok := ret.Get(0).(func(common.Position, common.CandyKind) common.Sprite); ok {
+		r0 = rf(position, kind)
+	} else {
+		r0 = ret.Get(0).(common.Sprite)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position, common.CandyKind) error); ok {
+		r1 = rf(position, kind)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlaceObstacle provides a mock function with given fields: position
+func (_m *GameBoarder) PlaceObstacle(position common.Position) (common.Sprite, error) {
+	ret := _m.Called(position)
+
+	var r0 common.Sprite
+	if rf, ok := ret.Get(0).(func(common.Position) common.Sprite); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(common.Sprite)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RandomFreePosition provides a mock function with given fields:
 func (_m *GameBoarder) RandomFreePosition() (common.Position, error) {
 	ret := _m.Called()
@@ -188,9 +459,83 @@ func (_m *GameBoarder) RandomFreePosition() (common.Position, error) {
 	return r0, r1
 }
 
-// RemoveCandy provides a mock function with given fields:
-func (_m *GameBoarder) RemoveCandy() {
-	_m.Called()
+// RemoveCandyAt provides a mock function with given fields: position
+func (_m *GameBoarder) RemoveCandyAt(position common.Position) (common.CandyKind, error) {
+	ret := _m.Called(position)
+
+	var r0 common.CandyKind
+	if rf, ok := ret.Get(0).(func(common.Position) common.CandyKind); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(common.CandyKind)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveObstacle provides a mock function with given fields: position
+func (_m *GameBoarder) RemoveObstacle(position common.Position) (common.Sprite, error) {
+	ret := _m.Called(position)
+
+	var r0 common.Sprite
+	if rf, ok := ret.Get(0).(func(common.Position) common.Sprite); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(common.Sprite)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSnake provides a mock function with given fields: id
+func (_m *GameBoarder) RemoveSnake(id common.SnakeID) ([]common.Sprite, error) {
+	ret := _m.Called(id)
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func(common.SnakeID) []common.Sprite); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.SnakeID) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: aSnapshot
+func (_m *GameBoarder) Restore(aSnapshot snapshot.Board) error {
+	ret := _m.Called(aSnapshot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(snapshot.Board) error); ok {
+		r0 = rf(aSnapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // SetSnakeDirection provides a mock function with given fields: direction
@@ -198,6 +543,29 @@ func (_m *GameBoarder) SetSnakeDirection(direction common.Direction) {
 	_m.Called(direction)
 }
 
+// ShrinkSnake provides a mock function with given fields: id, segments
+func (_m *GameBoarder) ShrinkSnake(id common.SnakeID, segments int) ([]common.Sprite, error) {
+	ret := _m.Called(id, segments)
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func(common.SnakeID, int) []common.Sprite); ok {
+		r0 = rf(id, segments)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.SnakeID, int) error); ok {
+		r1 = rf(id, segments)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SnakeDirection provides a mock function with given fields:
 func (_m *GameBoarder) SnakeDirection() (common.Direction, error) {
 	ret := _m.Called()
@@ -219,6 +587,22 @@ func (_m *GameBoarder) SnakeDirection() (common.Direction, error) {
 	return r0, r1
 }
 
+// SnakeIDs provides a mock function with given fields:
+func (_m *GameBoarder) SnakeIDs() []common.SnakeID {
+	ret := _m.Called()
+
+	var r0 []common.SnakeID
+	if rf, ok := ret.Get(0).(func() []common.SnakeID); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.SnakeID)
+		}
+	}
+
+	return r0
+}
+
 // SnakePosition provides a mock function with given fields:
 func (_m *GameBoarder) SnakePosition() (common.Position, error) {
 	ret := _m.Called()
@@ -261,6 +645,27 @@ func (_m *GameBoarder) SnakeSize() (int, error) {
 	return r0, r1
 }
 
+// SnakeState provides a mock function with given fields: id
+func (_m *GameBoarder) SnakeState(id common.SnakeID) (snapshot.Snake, error) {
+	ret := _m.Called(id)
+
+	var r0 snapshot.Snake
+	if rf, ok := ret.Get(0).(func(common.SnakeID) snapshot.Snake); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(snapshot.Snake)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.SnakeID) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SnakeTail provides a mock function with given fields:
 func (_m *GameBoarder) SnakeTail() (common.Position, error) {
 	ret := _m.Called()
@@ -281,3 +686,38 @@ func (_m *GameBoarder) SnakeTail() (common.Position, error) {
 
 	return r0, r1
 }
+
+// Snapshot provides a mock function with given fields:
+func (_m *GameBoarder) Snapshot() (snapshot.Board, error) {
+	ret := _m.Called()
+
+	var r0 snapshot.Board
+	if rf, ok := ret.Get(0).(func() snapshot.Board); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(snapshot.Board)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TurnSnake provides a mock function with given fields: id, direction
+func (_m *GameBoarder) TurnSnake(id common.SnakeID, direction common.Direction) error {
+	ret := _m.Called(id, direction)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.SnakeID, common.Direction) error); ok {
+		r0 = rf(id, direction)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -2,10 +2,18 @@
 
 package mocks
 
+import candy "github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
+
 import common "github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
 
+import event "github.com/Amari-Mecheri/GoSnakeLogic/pkg/event"
+
 import mock "github.com/stretchr/testify/mock"
 
+import snapshot "github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+
+import time "time"
+
 // GameStater is an autogenerated mock type for the GameStater type
 type GameStater struct {
 	mock.Mock
@@ -25,6 +33,101 @@ func (_m *GameStater) BoardSize() common.Size {
 	return r0
 }
 
+// CandiesEaten provides a mock function with given fields:
+func (_m *GameStater) CandiesEaten() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// CandyBody provides a mock function with given fields:
+func (_m *GameStater) CandyBody() rune {
+	ret := _m.Called()
+
+	var r0 rune
+	if rf, ok := ret.Get(0).(func() rune); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(rune)
+	}
+
+	return r0
+}
+
+// CandyKinds provides a mock function with given fields:
+func (_m *GameStater) CandyKinds() candy.Registry {
+	ret := _m.Called()
+
+	var r0 candy.Registry
+	if rf, ok := ret.Get(0).(func() candy.Registry); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(candy.Registry)
+		}
+	}
+
+	return r0
+}
+
+// CandyPositions provides a mock function with given fields:
+func (_m *GameStater) CandyPositions() []common.Position {
+	ret := _m.Called()
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	return r0
+}
+
+// CandyRule provides a mock function with given fields:
+func (_m *GameStater) CandyRule() common.CandyRule {
+	ret := _m.Called()
+
+	var r0 common.CandyRule
+	if rf, ok := ret.Get(0).(func() common.CandyRule); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.CandyRule)
+	}
+
+	return r0
+}
+
+// Cell provides a mock function with given fields: position
+func (_m *GameStater) Cell(position common.Position) (rune, error) {
+	ret := _m.Called(position)
+
+	var r0 rune
+	if rf, ok := ret.Get(0).(func(common.Position) rune); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(rune)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateObjects provides a mock function with given fields:
 func (_m *GameStater) CreateObjects() ([]common.Sprite, error) {
 	ret := _m.Called()
@@ -48,6 +151,20 @@ func (_m *GameStater) CreateObjects() ([]common.Sprite, error) {
 	return r0, r1
 }
 
+// Deterministic provides a mock function with given fields:
+func (_m *GameStater) Deterministic() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Dirty provides a mock function with given fields:
 func (_m *GameStater) Dirty() bool {
 	ret := _m.Called()
@@ -62,6 +179,34 @@ func (_m *GameStater) Dirty() bool {
 	return r0
 }
 
+// EdgePolicy provides a mock function with given fields:
+func (_m *GameStater) EdgePolicy() common.EdgePolicy {
+	ret := _m.Called()
+
+	var r0 common.EdgePolicy
+	if rf, ok := ret.Get(0).(func() common.EdgePolicy); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.EdgePolicy)
+	}
+
+	return r0
+}
+
+// FreeSpace provides a mock function with given fields:
+func (_m *GameStater) FreeSpace() rune {
+	ret := _m.Called()
+
+	var r0 rune
+	if rf, ok := ret.Get(0).(func() rune); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(rune)
+	}
+
+	return r0
+}
+
 // GameInProgress provides a mock function with given fields:
 func (_m *GameStater) GameInProgress() bool {
 	ret := _m.Called()
@@ -76,6 +221,34 @@ func (_m *GameStater) GameInProgress() bool {
 	return r0
 }
 
+// GameOverReason provides a mock function with given fields:
+func (_m *GameStater) GameOverReason() common.GameOverReason {
+	ret := _m.Called()
+
+	var r0 common.GameOverReason
+	if rf, ok := ret.Get(0).(func() common.GameOverReason); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.GameOverReason)
+	}
+
+	return r0
+}
+
+// HeadOnRule provides a mock function with given fields:
+func (_m *GameStater) HeadOnRule() common.HeadOnRule {
+	ret := _m.Called()
+
+	var r0 common.HeadOnRule
+	if rf, ok := ret.Get(0).(func() common.HeadOnRule); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.HeadOnRule)
+	}
+
+	return r0
+}
+
 // HighScore provides a mock function with given fields:
 func (_m *GameStater) HighScore() int {
 	ret := _m.Called()
@@ -104,6 +277,69 @@ func (_m *GameStater) InitBoard(size common.Size) error {
 	return r0
 }
 
+// InputDepth provides a mock function with given fields:
+func (_m *GameStater) InputDepth() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// IsCandy provides a mock function with given fields: ch
+func (_m *GameStater) IsCandy(ch rune) bool {
+	ret := _m.Called(ch)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(rune) bool); ok {
+		r0 = rf(ch)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsObstacle provides a mock function with given fields: ch
+func (_m *GameStater) IsObstacle(ch rune) bool {
+	ret := _m.Called(ch)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(rune) bool); ok {
+		r0 = rf(ch)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// LastDiff provides a mock function with given fields:
+func (_m *GameStater) LastDiff() (common.BoardDiff, bool) {
+	ret := _m.Called()
+
+	var r0 common.BoardDiff
+	if rf, ok := ret.Get(0).(func() common.BoardDiff); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.BoardDiff)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // MoveDown provides a mock function with given fields:
 func (_m *GameStater) MoveDown() {
 	_m.Called()
@@ -124,6 +360,118 @@ func (_m *GameStater) MoveUp() {
 	_m.Called()
 }
 
+// NextPosition provides a mock function with given fields: position, direction
+func (_m *GameStater) NextPosition(position common.Position, direction common.Direction) (common.Position, common.Direction, bool) {
+	ret := _m.Called(position, direction)
+
+	var r0 common.Position
+	if rf, ok := ret.Get(0).(func(common.Position, common.Direction) common.Position); ok {
+		r0 = rf(position, direction)
+	} else {
+		r0 = ret.Get(0).(common.Position)
+	}
+
+	var r1 common.Direction
+	if rf, ok := ret.Get(1).(func(common.Position, common.Direction) common.Direction); ok {
+		r1 = rf(position, direction)
+	} else {
+		r1 = ret.Get(1).(common.Direction)
+	}
+
+	var r2 bool
+	if rf, ok := ret.Get(2).(func(common.Position, common.Direction) bool); ok {
+		r2 = rf(position, direction)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// ObstacleBody provides a mock function with given fields:
+func (_m *GameStater) ObstacleBody() rune {
+	ret := _m.Called()
+
+	var r0 rune
+	if rf, ok := ret.Get(0).(func() rune); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(rune)
+	}
+
+	return r0
+}
+
+// Obstacles provides a mock function with given fields:
+func (_m *GameStater) Obstacles() []common.Position {
+	ret := _m.Called()
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func() []common.Position); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	return r0
+}
+
+// Outcome provides a mock function with given fields:
+func (_m *GameStater) Outcome() common.Outcome {
+	ret := _m.Called()
+
+	var r0 common.Outcome
+	if rf, ok := ret.Get(0).(func() common.Outcome); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.Outcome)
+	}
+
+	return r0
+}
+
+// Pause provides a mock function with given fields:
+func (_m *GameStater) Pause() {
+	_m.Called()
+}
+
+// Paused provides a mock function with given fields:
+func (_m *GameStater) Paused() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// PlaceObstacle provides a mock function with given fields: position
+func (_m *GameStater) PlaceObstacle(position common.Position) (common.Sprite, error) {
+	ret := _m.Called(position)
+
+	var r0 common.Sprite
+	if rf, ok := ret.Get(0).(func(common.Position) common.Sprite); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(common.Sprite)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Play provides a mock function with given fields:
 func (_m *GameStater) Play() ([]common.Sprite, error) {
 	ret := _m.Called()
@@ -147,6 +495,67 @@ func (_m *GameStater) Play() ([]common.Sprite, error) {
 	return r0, r1
 }
 
+// RemoveObstacle provides a mock function with given fields: position
+func (_m *GameStater) RemoveObstacle(position common.Position) (common.Sprite, error) {
+	ret := _m.Called(position)
+
+	var r0 common.Sprite
+	if rf, ok := ret.Get(0).(func(common.Position) common.Sprite); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(common.Sprite)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Position) error); ok {
+		r1 = rf(position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: aSnapshot
+func (_m *GameStater) Restore(aSnapshot snapshot.Game) error {
+	ret := _m.Called(aSnapshot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(snapshot.Game) error); ok {
+		r0 = rf(aSnapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Result provides a mock function with given fields:
+func (_m *GameStater) Result() (common.GameResult, bool) {
+	ret := _m.Called()
+
+	var r0 common.GameResult
+	if rf, ok := ret.Get(0).(func() common.GameResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.GameResult)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func() bool); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// Resume provides a mock function with given fields:
+func (_m *GameStater) Resume() {
+	_m.Called()
+}
+
 // Round provides a mock function with given fields:
 func (_m *GameStater) Round() int {
 	ret := _m.Called()
@@ -175,11 +584,81 @@ func (_m *GameStater) Score() int {
 	return r0
 }
 
+// SetDebug provides a mock function with given fields: debug
+func (_m *GameStater) SetDebug(debug bool) {
+	_m.Called(debug)
+}
+
 // SetGameInProgress provides a mock function with given fields: _a0
 func (_m *GameStater) SetGameInProgress(_a0 bool) {
 	_m.Called(_a0)
 }
 
+// SnakeDirection provides a mock function with given fields:
+func (_m *GameStater) SnakeDirection() (common.Direction, error) {
+	ret := _m.Called()
+
+	var r0 common.Direction
+	if rf, ok := ret.Get(0).(func() common.Direction); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.Direction)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SnakeGameOverReason provides a mock function with given fields: id
+func (_m *GameStater) SnakeGameOverReason(id common.SnakeID) common.GameOverReason {
+	ret := _m.Called(id)
+
+	var r0 common.GameOverReason
+	if rf, ok := ret.Get(0).(func(common.SnakeID) common.GameOverReason); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(common.GameOverReason)
+	}
+
+	return r0
+}
+
+// SnakeIDs provides a mock function with given fields:
+func (_m *GameStater) SnakeIDs() []common.SnakeID {
+	ret := _m.Called()
+
+	var r0 []common.SnakeID
+	if rf, ok := ret.Get(0).(func() []common.SnakeID); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.SnakeID)
+		}
+	}
+
+	return r0
+}
+
+// SnakePart provides a mock function with given fields:
+func (_m *GameStater) SnakePart() rune {
+	ret := _m.Called()
+
+	var r0 rune
+	if rf, ok := ret.Get(0).(func() rune); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(rune)
+	}
+
+	return r0
+}
+
 // SnakePosition provides a mock function with given fields:
 func (_m *GameStater) SnakePosition() (common.Position, error) {
 	ret := _m.Called()
@@ -201,6 +680,20 @@ func (_m *GameStater) SnakePosition() (common.Position, error) {
 	return r0, r1
 }
 
+// SnakeScore provides a mock function with given fields: id
+func (_m *GameStater) SnakeScore(id common.SnakeID) int {
+	ret := _m.Called(id)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(common.SnakeID) int); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // SnakeSize provides a mock function with given fields:
 func (_m *GameStater) SnakeSize() (int, error) {
 	ret := _m.Called()
@@ -222,7 +715,172 @@ func (_m *GameStater) SnakeSize() (int, error) {
 	return r0, r1
 }
 
+// SnakeState provides a mock function with given fields: id
+func (_m *GameStater) SnakeState(id common.SnakeID) (snapshot.Snake, error) {
+	ret := _m.Called(id)
+
+	var r0 snapshot.Snake
+	if rf, ok := ret.Get(0).(func(common.SnakeID) snapshot.Snake); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(snapshot.Snake)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.SnakeID) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SnakeTail provides a mock function with given fields:
+func (_m *GameStater) SnakeTail() (common.Position, error) {
+	ret := _m.Called()
+
+	var r0 common.Position
+	if rf, ok := ret.Get(0).(func() common.Position); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.Position)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Snapshot provides a mock function with given fields:
+func (_m *GameStater) Snapshot() (snapshot.Game, error) {
+	ret := _m.Called()
+
+	var r0 snapshot.Game
+	if rf, ok := ret.Get(0).(func() snapshot.Game); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(snapshot.Game)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SpeedRounds provides a mock function with given fields:
+func (_m *GameStater) SpeedRounds() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *GameStater) Start() {
 	_m.Called()
 }
+
+// StartCandies provides a mock function with given fields:
+func (_m *GameStater) StartCandies() []common.Sprite {
+	ret := _m.Called()
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func() []common.Sprite); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	return r0
+}
+
+// Steer provides a mock function with given fields: id, direction
+func (_m *GameStater) Steer(id common.SnakeID, direction common.Direction) {
+	_m.Called(id, direction)
+}
+
+// Step provides a mock function with given fields:
+func (_m *GameStater) Step() ([]common.Sprite, error) {
+	ret := _m.Called()
+
+	var r0 []common.Sprite
+	if rf, ok := ret.Get(0).(func() []common.Sprite); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Sprite)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields: handler
+func (_m *GameStater) Subscribe(handler event.Handler) func() {
+	ret := _m.Called(handler)
+
+	var r0 func()
+	if rf, ok := ret.Get(0).(func(event.Handler) func()); ok {
+		r0 = rf(handler)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func())
+		}
+	}
+
+	return r0
+}
+
+// TickInterval provides a mock function with given fields:
+func (_m *GameStater) TickInterval() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// WinCondition provides a mock function with given fields:
+func (_m *GameStater) WinCondition() common.WinCondition {
+	ret := _m.Called()
+
+	var r0 common.WinCondition
+	if rf, ok := ret.Get(0).(func() common.WinCondition); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(common.WinCondition)
+	}
+
+	return r0
+}
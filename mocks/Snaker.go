@@ -3,13 +3,34 @@
 package mocks
 
 import common "github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
 import mock "github.com/stretchr/testify/mock"
 
+import snake "github.com/Amari-Mecheri/GoSnakeLogic/pkg/snake"
+
+import snapshot "github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+
 // Snaker is an autogenerated mock type for the Snaker type
 type Snaker struct {
 	mock.Mock
 }
 
+// Clone provides a mock function with given fields:
+func (_m *Snaker) Clone() snake.Snaker {
+	ret := _m.Called()
+
+	var r0 snake.Snaker
+	if rf, ok := ret.Get(0).(func() snake.Snaker); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(snake.Snaker)
+		}
+	}
+
+	return r0
+}
+
 // Direction provides a mock function with given fields:
 func (_m *Snaker) Direction() (common.Direction, error) {
 	ret := _m.Called()
@@ -87,6 +108,20 @@ func (_m *Snaker) NextMove() (common.Position, error) {
 	return r0, r1
 }
 
+// Occupies provides a mock function with given fields: position
+func (_m *Snaker) Occupies(position common.Position) bool {
+	ret := _m.Called(position)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(common.Position) bool); ok {
+		r0 = rf(position)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Position provides a mock function with given fields:
 func (_m *Snaker) Position() (common.Position, error) {
 	ret := _m.Called()
@@ -108,11 +143,39 @@ func (_m *Snaker) Position() (common.Position, error) {
 	return r0, r1
 }
 
+// Restore provides a mock function with given fields: aSnapshot
+func (_m *Snaker) Restore(aSnapshot snapshot.Snake) {
+	_m.Called(aSnapshot)
+}
+
 // SetDirection provides a mock function with given fields: direction
 func (_m *Snaker) SetDirection(direction common.Direction) {
 	_m.Called(direction)
 }
 
+// Shrink provides a mock function with given fields: segments
+func (_m *Snaker) Shrink(segments int) ([]common.Position, error) {
+	ret := _m.Called(segments)
+
+	var r0 []common.Position
+	if rf, ok := ret.Get(0).(func(int) []common.Position); ok {
+		r0 = rf(segments)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]common.Position)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(segments)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Size provides a mock function with given fields:
 func (_m *Snaker) Size() (int, error) {
 	ret := _m.Called()
@@ -134,6 +197,20 @@ func (_m *Snaker) Size() (int, error) {
 	return r0, r1
 }
 
+// Snapshot provides a mock function with given fields:
+func (_m *Snaker) Snapshot() snapshot.Snake {
+	ret := _m.Called()
+
+	var r0 snapshot.Snake
+	if rf, ok := ret.Get(0).(func() snapshot.Snake); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(snapshot.Snake)
+	}
+
+	return r0
+}
+
 // Tail provides a mock function with given fields:
 func (_m *Snaker) Tail() (common.Position, error) {
 	ret := _m.Called()
@@ -1,7 +1,10 @@
 package candy
 
 import (
+	"sort"
+
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 )
 
 // Candyer is a candy interface
@@ -10,12 +13,73 @@ type Candyer interface {
 	Init(newPosition common.Position)
 	Position() common.Position
 	Alive() bool
+	SetKind(kind common.CandyKind, lifetime int)
+	Kind() common.CandyKind
+	Lifetime() int
+	Tick() (expired bool)
+	Snapshot() snapshot.Candy
+	Restore(aSnapshot snapshot.Candy)
+	Clone() Candyer
 }
 
 // candy has the properties of a candy
 type candy struct {
 	alive    bool //false by default
 	position common.Position
+	kind     common.CandyKind
+	lifetime int // rounds left before the candy expires, 0 for ever
+}
+
+// Properties describes a kind of candy
+type Properties struct {
+	Body     rune `json:"body"`               // drawn on the board
+	Points   int  `json:"points"`             // added to the score
+	Shrink   int  `json:"shrink,omitempty"`   // tail segments removed
+	Speed    int  `json:"speed,omitempty"`    // rounds the snake goes faster
+	Poison   bool `json:"poison,omitempty"`   // ends the game
+	Lifetime int  `json:"lifetime,omitempty"` // rounds before it expires, 0 for ever
+	Weight   int  `json:"weight,omitempty"`   // relative chance to be spawned
+}
+
+// Registry holds the properties of every known kind of candy
+type Registry map[common.CandyKind]Properties
+
+// DefaultRegistry returns the built-in kinds.
+// Only regular candies are spawned, the weights of the
+// other kinds have to be set to get them
+func DefaultRegistry() Registry {
+	return Registry{
+		common.RegularCandy: {Body: '*', Points: 1, Weight: 1},
+		common.GoldenCandy:  {Body: '$', Points: 5, Lifetime: 20},
+		common.ShrinkCandy:  {Body: '-', Points: 1, Shrink: 3},
+		common.SpeedCandy:   {Body: '>', Points: 1, Speed: 20},
+		common.PoisonCandy:  {Body: '!', Poison: true, Lifetime: 30},
+	}
+}
+
+// Kinds returns the registered kinds in increasing order
+func (aRegistry Registry) Kinds() []common.CandyKind {
+	kinds := make([]common.CandyKind, 0, len(aRegistry))
+	for kind := range aRegistry {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	return kinds
+}
+
+// KindOf returns the kind drawn with body. When several kinds share
+// the body the lowest one is returned, whatever the order of the map
+func (aRegistry Registry) KindOf(body rune) (kind common.CandyKind, ok bool) {
+	for aKind, properties := range aRegistry {
+		if properties.Body == body && (!ok || aKind < kind) {
+			kind, ok = aKind, true
+		}
+	}
+
+	return kind, ok
 }
 
 // New returns an instance of candy
@@ -43,3 +107,52 @@ func (aCandy *candy) Position() common.Position {
 func (aCandy *candy) Alive() bool {
 	return aCandy.alive
 }
+
+// SetKind sets the kind of the candy and the rounds it lasts, 0 for ever
+func (aCandy *candy) SetKind(kind common.CandyKind, lifetime int) {
+	aCandy.kind = kind
+	aCandy.lifetime = lifetime
+}
+
+// Kind returns the kind of the candy
+func (aCandy *candy) Kind() common.CandyKind {
+	return aCandy.kind
+}
+
+// Lifetime returns the rounds left before the candy expires, 0 for ever
+func (aCandy *candy) Lifetime() int {
+	return aCandy.lifetime
+}
+
+// Tick counts a round, expired is true when the lifetime is over
+func (aCandy *candy) Tick() (expired bool) {
+	if aCandy.lifetime <= 0 {
+		return false
+	}
+	aCandy.lifetime--
+	return aCandy.lifetime == 0
+}
+
+// Snapshot returns the candy state
+func (aCandy *candy) Snapshot() snapshot.Candy {
+	return snapshot.Candy{
+		Alive:    aCandy.alive,
+		Position: aCandy.position,
+		Kind:     aCandy.kind,
+		Lifetime: aCandy.lifetime,
+	}
+}
+
+// Restore sets the candy state saved by Snapshot
+func (aCandy *candy) Restore(aSnapshot snapshot.Candy) {
+	aCandy.alive = aSnapshot.Alive
+	aCandy.position = aSnapshot.Position
+	aCandy.kind = aSnapshot.Kind
+	aCandy.lifetime = aSnapshot.Lifetime
+}
+
+// Clone returns a copy of the candy
+func (aCandy *candy) Clone() Candyer {
+	aClone := *aCandy
+	return &aClone
+}
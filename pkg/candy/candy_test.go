@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 
 	"github.com/stretchr/testify/require"
 )
@@ -227,3 +228,151 @@ func TestNew(t *testing.T) {
 	var got = New()
 	require.IsType(t, wantType, got)
 }
+
+func TestCandy_SnapshotRestore(t *testing.T) {
+	type fields struct {
+		alive    bool
+		position common.Position
+		kind     common.CandyKind
+		lifetime int
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		wantSnapshot snapshot.Candy
+	}{
+		{
+			name: "TestDeadCandy",
+			fields: fields{
+				alive: false,
+			},
+			wantSnapshot: snapshot.Candy{
+				Alive: false,
+			},
+		},
+		{
+			name: "TestAliveCandy",
+			fields: fields{
+				alive: true,
+				position: common.Position{
+					X: 3,
+					Y: 7,
+				},
+			},
+			wantSnapshot: snapshot.Candy{
+				Alive: true,
+				Position: common.Position{
+					X: 3,
+					Y: 7,
+				},
+			},
+		}, {
+			name: "TestPoisonCandy",
+			fields: fields{
+				alive:    true,
+				kind:     common.PoisonCandy,
+				lifetime: 4,
+			},
+			wantSnapshot: snapshot.Candy{
+				Alive:    true,
+				Kind:     common.PoisonCandy,
+				Lifetime: 4,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candy := &candy{
+				alive:    tt.fields.alive,
+				position: tt.fields.position,
+				kind:     tt.fields.kind,
+				lifetime: tt.fields.lifetime,
+			}
+			gotSnapshot := candy.Snapshot()
+			require.Equal(t, tt.wantSnapshot, gotSnapshot)
+
+			restored := New()
+			restored.Restore(gotSnapshot)
+			require.Equal(t, tt.wantSnapshot.Alive, restored.Alive())
+			require.Equal(t, tt.wantSnapshot.Position, restored.Position())
+			require.Equal(t, tt.wantSnapshot.Kind, restored.Kind())
+			require.Equal(t, tt.wantSnapshot.Lifetime, restored.Lifetime())
+		})
+	}
+}
+
+func TestCandy_Tick(t *testing.T) {
+	type args struct {
+		lifetime int
+	}
+	tests := []struct {
+		name         string
+		args         args
+		ticks        int
+		wantExpired  bool
+		wantLifetime int
+	}{
+		{
+			name:         "TestForever",
+			args:         args{lifetime: 0},
+			ticks:        10,
+			wantExpired:  false,
+			wantLifetime: 0,
+		},
+		{
+			name:         "TestNotYetExpired",
+			args:         args{lifetime: 3},
+			ticks:        2,
+			wantExpired:  false,
+			wantLifetime: 1,
+		},
+		{
+			name:         "TestExpired",
+			args:         args{lifetime: 3},
+			ticks:        3,
+			wantExpired:  true,
+			wantLifetime: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aCandy := New()
+			aCandy.SetKind(common.GoldenCandy, tt.args.lifetime)
+			var gotExpired bool
+			for i := 0; i < tt.ticks; i++ {
+				gotExpired = aCandy.Tick()
+			}
+			require.Equal(t, tt.wantExpired, gotExpired)
+			require.Equal(t, tt.wantLifetime, aCandy.Lifetime())
+			require.Equal(t, common.GoldenCandy, aCandy.Kind())
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	aRegistry := DefaultRegistry()
+	require.Equal(t, []common.CandyKind{
+		common.RegularCandy,
+		common.GoldenCandy,
+		common.ShrinkCandy,
+		common.SpeedCandy,
+		common.PoisonCandy,
+	}, aRegistry.Kinds())
+
+	kind, ok := aRegistry.KindOf('$')
+	require.True(t, ok)
+	require.Equal(t, common.GoldenCandy, kind)
+	_, ok = aRegistry.KindOf('S')
+	require.False(t, ok)
+
+	// Kinds sharing a body always resolve to the lowest one
+	shared := Registry{}
+	for kind := common.CandyKind(9); kind >= 2; kind-- {
+		shared[kind] = Properties{Body: '@'}
+	}
+	for i := 0; i < 20; i++ {
+		kind, ok = shared.KindOf('@')
+		require.True(t, ok)
+		require.Equal(t, common.CandyKind(2), kind)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
 )
 
 // ViewPosition holds coordinates of a view rectangle
@@ -20,6 +21,274 @@ type Direction struct {
 	DY int
 }
 
+// Reverse returns the opposite direction
+func (direction Direction) Reverse() Direction {
+	return Direction{
+		DX: -direction.DX,
+		DY: -direction.DY,
+	}
+}
+
+// Directions the snake can take
+var (
+	Left = Direction{
+		DX: -1,
+		DY: 0,
+	}
+	Right = Direction{
+		DX: 1,
+		DY: 0,
+	}
+	Up = Direction{
+		DX: 0,
+		DY: -1,
+	}
+	Down = Direction{
+		DX: 0,
+		DY: 1,
+	}
+)
+
+// Edge is the behavior of a board edge
+type Edge int
+
+// Edge behaviors, wrap is the default
+const (
+	WrapEdge   Edge = iota // the snake enters again from the opposite edge
+	WallEdge               // the snake dies
+	BounceEdge             // the snake turns along the edge, toward the center
+)
+
+func (edge Edge) String() string {
+	switch edge {
+	case WallEdge:
+		return "wall"
+	case BounceEdge:
+		return "bounce"
+	}
+
+	return "wrap"
+}
+
+// ParseEdge returns the edge called name: wrap, wall or bounce
+func ParseEdge(name string) (edge Edge, err error) {
+	for _, edge := range []Edge{WrapEdge, WallEdge, BounceEdge} {
+		if edge.String() == name {
+			return edge, nil
+		}
+	}
+
+	return edge, fmt.Errorf("unknown edge %q", name)
+}
+
+// EdgePolicy sets the behavior of each board edge
+type EdgePolicy struct {
+	Left   Edge `json:"left"`
+	Right  Edge `json:"right"`
+	Top    Edge `json:"top"`
+	Bottom Edge `json:"bottom"`
+}
+
+// UniformEdges returns a policy using edge on every side
+func UniformEdges(edge Edge) EdgePolicy {
+	return EdgePolicy{
+		Left:   edge,
+		Right:  edge,
+		Top:    edge,
+		Bottom: edge,
+	}
+}
+
+// MixedEdges returns a policy using horizontal on the left and right
+// edges and vertical on the top and bottom edges
+func MixedEdges(horizontal, vertical Edge) EdgePolicy {
+	return EdgePolicy{
+		Left:   horizontal,
+		Right:  horizontal,
+		Top:    vertical,
+		Bottom: vertical,
+	}
+}
+
+// ParseEdgePolicy returns the MixedEdges policy of the edges
+// called horizontal and vertical
+func ParseEdgePolicy(horizontal, vertical string) (edgePolicy EdgePolicy, err error) {
+	horizontalEdge, err := ParseEdge(horizontal)
+	if err != nil {
+		return edgePolicy, err
+	}
+	verticalEdge, err := ParseEdge(vertical)
+	if err != nil {
+		return edgePolicy, err
+	}
+
+	return MixedEdges(horizontalEdge, verticalEdge), nil
+}
+
+// GameOverReason tells why a game ended
+type GameOverReason int
+
+// Reasons of a game over
+const (
+	NotOver           GameOverReason = iota // the game is in progress or hasn't ended by a collision
+	SelfCollision                           // the snake bit itself
+	WallCollision                           // the snake hit a wall edge
+	Poisoned                                // the snake ate a poison candy
+	ObstacleCollision                       // the snake hit an obstacle
+	SnakeCollision                          // the snake hit the body of another snake
+	HeadOnCollision                         // the snake head met another head
+	RoundLimit                              // the win condition wasn't met within its round limit
+	InternalError                           // a round failed, the game can't go on
+)
+
+func (reason GameOverReason) String() string {
+	switch reason {
+	case SelfCollision:
+		return "self collision"
+	case WallCollision:
+		return "wall collision"
+	case Poisoned:
+		return "poisoned"
+	case ObstacleCollision:
+		return "obstacle collision"
+	case SnakeCollision:
+		return "snake collision"
+	case HeadOnCollision:
+		return "head-on collision"
+	case RoundLimit:
+		return "round limit"
+	case InternalError:
+		return "internal error"
+	}
+
+	return "not over"
+}
+
+// CandyKind is the type of a candy, it sets the candy effect
+type CandyKind int
+
+// Candy kinds, regular is the default
+const (
+	RegularCandy CandyKind = iota // one point
+	GoldenCandy                   // more points
+	ShrinkCandy                   // removes tail segments
+	SpeedCandy                    // speeds the snake up for a while
+	PoisonCandy                   // ends the game
+)
+
+func (kind CandyKind) String() string {
+	switch kind {
+	case RegularCandy:
+		return "regular"
+	case GoldenCandy:
+		return "golden"
+	case ShrinkCandy:
+		return "shrink"
+	case SpeedCandy:
+		return "speed"
+	case PoisonCandy:
+		return "poison"
+	}
+
+	return fmt.Sprintf("candy kind %d", int(kind))
+}
+
+// WinGoal is what the player has to do to win a game
+type WinGoal int
+
+// Win goals, by default a game can't be won
+const (
+	NoWin         WinGoal = iota // the game goes on until the snake dies
+	FillBoard                    // the snake fills the board
+	ReachScore                   // the score reaches Target
+	SurviveRounds                // the snake survives Target rounds
+	EatCandies                   // the snake eats Target candies within Rounds rounds
+)
+
+// WinCondition sets how a game is won
+type WinCondition struct {
+	Goal   WinGoal `json:"goal"`
+	Target int     `json:"target,omitempty"`
+	Rounds int     `json:"rounds,omitempty"`
+}
+
+// Outcome tells whether a game has been won or lost
+type Outcome int
+
+// Outcomes of a game
+const (
+	Undecided Outcome = iota // the game is in progress or hasn't started
+	Won                      // the win condition was met
+	Lost                     // the snake died or the win condition was missed
+)
+
+func (outcome Outcome) String() string {
+	switch outcome {
+	case Won:
+		return "won"
+	case Lost:
+		return "lost"
+	}
+
+	return "undecided"
+}
+
+// RespawnMode tells when eaten candies are replaced
+type RespawnMode int
+
+// Respawn modes, immediate is the default
+const (
+	RespawnImmediate RespawnMode = iota // an eaten candy is replaced in the same round
+	RespawnDelayed                      // an eaten candy is replaced Delay rounds later
+	RespawnBatch                        // the candies are replaced once they have all been eaten
+)
+
+// CandyRule sets how many candies are on the board and how they respawn
+type CandyRule struct {
+	Count   int         `json:"count"`
+	Respawn RespawnMode `json:"respawn"`
+	Delay   int         `json:"delay,omitempty"`
+}
+
+// SnakeID identifies a snake of a board, the first snake is 0
+type SnakeID int
+
+// HeadOnRule tells what happens when snake heads move to the same cell
+type HeadOnRule int
+
+// Head-on rules, both snakes die by default
+const (
+	HeadOnBothDie    HeadOnRule = iota // every snake dies
+	HeadOnLongerWins                   // the longest snake survives, they all die on a tie
+)
+
+// SnakeMove is the outcome of a snake move on a board with several snakes.
+// Position is the cell entered by the head, off the board when it hit
+// a wall edge. OldValue is the former content of that cell,
+// Collision is NotOver when the snake survived.
+// Length is the length of the snake before the move
+type SnakeMove struct {
+	ID        SnakeID
+	Position  Position
+	OldValue  rune
+	Collision GameOverReason
+	Length    int
+}
+
+// GameResult sums up a game once it is over.
+// Snake is the snake the result is about: the last one to die, or the best
+// scorer of a game won. Killer is the cell its head ran into, the head
+// itself when the game was won, ran out of rounds or failed
+type GameResult struct {
+	Outcome Outcome        `json:"outcome"`
+	Reason  GameOverReason `json:"reason"`
+	Round   int            `json:"round"`
+	Length  int            `json:"length"`
+	Score   int            `json:"score"`
+	Killer  Position       `json:"killer"`
+	Snake   SnakeID        `json:"snake,omitempty"`
+}
+
 // Position defines coordinates
 type Position struct {
 	X int
@@ -38,6 +307,69 @@ type Sprite struct {
 	Position Position
 }
 
+// CellChange is a cell of the board changed by a round
+type CellChange struct {
+	Position Position
+	Before   rune
+	After    rune
+}
+
+// BoardDiff is what a round played in debug mode did to the board.
+// Before and After hold one string per row, Changes lists the cells
+// that differ row by row
+type BoardDiff struct {
+	Round   int
+	Before  []string
+	After   []string
+	Changes []CellChange
+}
+
+// NewBoardDiff compares the rows of a board before and after round
+func NewBoardDiff(round int, before, after []string) BoardDiff {
+	aDiff := BoardDiff{
+		Round:  round,
+		Before: before,
+		After:  after,
+	}
+	for y := range after {
+		if y >= len(before) {
+			break
+		}
+		beforeRow, afterRow := []rune(before[y]), []rune(after[y])
+		for x := range afterRow {
+			if x < len(beforeRow) && beforeRow[x] != afterRow[x] {
+				aDiff.Changes = append(aDiff.Changes, CellChange{
+					Position: Position{X: x, Y: y},
+					Before:   beforeRow[x],
+					After:    afterRow[x],
+				})
+			}
+		}
+	}
+
+	return aDiff
+}
+
+// String reports the changed cells then draws the board
+// before and after the round side by side
+func (aDiff BoardDiff) String() string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "round %d: %d cells changed\n", aDiff.Round, len(aDiff.Changes))
+	for _, change := range aDiff.Changes {
+		fmt.Fprintf(&report, "(%d,%d) %q -> %q\n",
+			change.Position.X, change.Position.Y, change.Before, change.After)
+	}
+	for y := range aDiff.After {
+		before := ""
+		if y < len(aDiff.Before) {
+			before = aDiff.Before[y]
+		}
+		fmt.Fprintf(&report, "|%s| |%s|\n", before, aDiff.After[y])
+	}
+
+	return report.String()
+}
+
 // GetCurrentFuncName returns the caller's function name
 func GetCurrentFuncName() string {
 	pc, _, _, _ := runtime.Caller(1)
@@ -94,3 +94,66 @@ func TestErrorWrapper(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEdgePolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		horizontal     string
+		vertical       string
+		wantEdgePolicy EdgePolicy
+		wantErr        bool
+	}{
+		{
+			name:           "TestUniform",
+			horizontal:     "wrap",
+			vertical:       "wrap",
+			wantEdgePolicy: UniformEdges(WrapEdge),
+		},
+		{
+			name:           "TestMixed",
+			horizontal:     "bounce",
+			vertical:       "wall",
+			wantEdgePolicy: MixedEdges(BounceEdge, WallEdge),
+		},
+		{
+			name:       "TestUnknownHorizontal",
+			horizontal: "hole",
+			vertical:   "wall",
+			wantErr:    true,
+		},
+		{
+			name:       "TestUnknownVertical",
+			horizontal: "wall",
+			vertical:   "Wall",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotEdgePolicy, err := ParseEdgePolicy(tt.horizontal, tt.vertical)
+			require.Equal(t, tt.wantErr, err != nil, "%v", err)
+			require.Equal(t, tt.wantEdgePolicy, gotEdgePolicy)
+		})
+	}
+}
+
+func TestBoardDiff_String(t *testing.T) {
+	aDiff := NewBoardDiff(3, []string{"S  ", " * "}, []string{" S ", " * "})
+	require.Equal(t, []CellChange{
+		{
+			Position: Position{X: 0, Y: 0},
+			Before:   'S',
+			After:    ' ',
+		},
+		{
+			Position: Position{X: 1, Y: 0},
+			Before:   ' ',
+			After:    'S',
+		},
+	}, aDiff.Changes)
+	require.Equal(t, "round 3: 2 cells changed\n"+
+		"(0,0) 'S' -> ' '\n"+
+		"(1,0) ' ' -> 'S'\n"+
+		"|S  | | S |\n"+
+		"| * | | * |\n", aDiff.String())
+}
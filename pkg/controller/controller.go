@@ -0,0 +1,136 @@
+// Package controller provides bots steering a snake. They read the board
+// through GameStater like a front end does, so they can drive real games,
+// demos and attract screens
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// Controller chooses the next direction of the snake id,
+// ok is false when it keeps the current one
+type Controller interface {
+	Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool)
+}
+
+// ErrUnknownController is a custom error thrown by New
+var ErrUnknownController = errors.New("unknown controller")
+
+// Names of the controllers returned by New
+const (
+	GreedyName      = "greedy"
+	PathName        = "path"
+	TailName        = "tail"
+	HamiltonianName = "hamiltonian"
+)
+
+// directions are tried in this order, the first one wins a tie
+var directions = []common.Direction{common.Up, common.Right, common.Down, common.Left}
+
+// New returns the controller with the given name
+func New(name string) (aController Controller, err error) {
+	switch name {
+	case GreedyName:
+		return NewGreedy(), nil
+	case PathName:
+		return NewPathfinder(), nil
+	case TailName:
+		return NewTailChaser(), nil
+	case HamiltonianName:
+		return NewHamiltonian(), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownController, name)
+}
+
+// Steer queues the direction chosen by aController for the snake id
+func Steer(game gamestate.GameStater, id common.SnakeID, aController Controller) {
+	if direction, ok := aController.Next(game, id); ok {
+		game.Steer(id, direction)
+	}
+}
+
+// greedy heads for the closest candy, as the crow flies,
+// through a cell that doesn't kill the snake in the next round
+type greedy struct{}
+
+// NewGreedy returns a bot heading for the closest candy
+func NewGreedy() Controller {
+	return greedy{}
+}
+
+func (greedy) Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool) {
+	aView, ok := newView(game, id)
+	if !ok {
+		return direction, false
+	}
+	best := -1
+	for _, aMove := range aView.moves() {
+		distance := aView.closestTarget(aMove.position)
+		if best < 0 || distance < best {
+			best, direction, ok = distance, aMove.direction, true
+		}
+	}
+
+	return direction, best >= 0
+}
+
+// pathfinder takes a shortest safe path to a candy,
+// it saves the most room when no candy can be reached
+type pathfinder struct{}
+
+// NewPathfinder returns a bot taking a shortest path to a candy
+func NewPathfinder() Controller {
+	return pathfinder{}
+}
+
+func (pathfinder) Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool) {
+	aView, ok := newView(game, id)
+	if !ok {
+		return direction, false
+	}
+	blocked := aView.occupied(aView.body)
+	if _, direction, ok := aView.path(aView.moves(), blocked, aView.isTarget); ok {
+		return direction, true
+	}
+
+	return aView.roomiest()
+}
+
+// tailChaser only eats a candy when it can still reach its tail afterwards,
+// otherwise it follows its tail to survive
+type tailChaser struct{}
+
+// NewTailChaser returns a bot that keeps a way to its tail
+func NewTailChaser() Controller {
+	return tailChaser{}
+}
+
+func (tailChaser) Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool) {
+	aView, ok := newView(game, id)
+	if !ok {
+		return direction, false
+	}
+	blocked := aView.occupied(aView.body)
+	if path, direction, ok := aView.path(aView.moves(), blocked, aView.isTarget); ok {
+		// The body once the candy is eaten, it has grown by one part
+		body := append(append([]common.Position(nil), aView.body...), path...)
+		body = body[len(body)-len(aView.body)-1:]
+		if aView.reachesTail(body) {
+			return direction, true
+		}
+	}
+	if len(aView.body) > 1 {
+		tail := aView.body[0]
+		isTail := func(position common.Position) bool { return position == tail }
+		if _, direction, ok := aView.path(aView.moves(), blocked, isTail); ok {
+			return direction, true
+		}
+	}
+
+	return aView.roomiest()
+}
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"testing"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{GreedyName, PathName, TailName, HamiltonianName} {
+		aController, err := New(name)
+		require.NoError(t, err)
+		require.NotNil(t, aController)
+	}
+	_, err := New("human")
+	require.ErrorIs(t, err, ErrUnknownController)
+}
+
+func TestControllers_Play(t *testing.T) {
+	// Every bot scores on an 8x8 board, the game is stopped before the
+	// board is full
+	size := common.Size{
+		Width:  8,
+		Height: 8,
+	}
+	tests := []struct {
+		name       string
+		controller string
+		edges      common.Edge
+		wantScore  int
+	}{
+		{name: "TestGreedy", controller: GreedyName, edges: common.WrapEdge, wantScore: 5},
+		{name: "TestPathWrap", controller: PathName, edges: common.WrapEdge, wantScore: 10},
+		{name: "TestPathWall", controller: PathName, edges: common.WallEdge, wantScore: 10},
+		{name: "TestTail", controller: TailName, edges: common.WallEdge, wantScore: 10},
+		{name: "TestHamiltonian", controller: HamiltonianName, edges: common.WallEdge, wantScore: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aController, err := New(tt.controller)
+			require.NoError(t, err)
+			game := gamestate.New(gamestate.WithSeed(2), gamestate.WithEdgePolicy(common.UniformEdges(tt.edges)))
+			require.NoError(t, game.InitBoard(size))
+			_, err = game.CreateObjects()
+			require.NoError(t, err)
+			game.Start()
+			for game.GameInProgress() && game.Round() < 2000 && game.Score() < tt.wantScore {
+				Steer(game, 0, aController)
+				_, err = game.Play()
+				require.NoError(t, err)
+			}
+			require.True(t, game.GameInProgress(), game.GameOverReason())
+			require.Equal(t, tt.wantScore, game.Score())
+		})
+	}
+}
+
+func TestControllers_Next(t *testing.T) {
+	// The snake is at 1,1 heading right on a 6x3 board
+	tests := []struct {
+		name          string
+		controller    string
+		edges         common.Edge
+		candies       []common.Sprite
+		wantDirection common.Direction
+		wantAvoid     common.Direction
+	}{
+		{
+			name:          "TestPathThroughTheEdge",
+			controller:    PathName,
+			edges:         common.WrapEdge,
+			candies:       []common.Sprite{{Value: '*', Position: common.Position{X: 5, Y: 1}}},
+			wantDirection: common.Left,
+		},
+		{
+			name:          "TestPathAlongTheWall",
+			controller:    PathName,
+			edges:         common.WallEdge,
+			candies:       []common.Sprite{{Value: '*', Position: common.Position{X: 5, Y: 1}}},
+			wantDirection: common.Right,
+		},
+		{
+			name:          "TestGreedyThroughTheEdge",
+			controller:    GreedyName,
+			edges:         common.WrapEdge,
+			candies:       []common.Sprite{{Value: '*', Position: common.Position{X: 5, Y: 1}}},
+			wantDirection: common.Left,
+		},
+		{
+			name:       "TestGreedyAvoidsPoison",
+			controller: GreedyName,
+			edges:      common.WallEdge,
+			candies:    []common.Sprite{{Value: '!', Position: common.Position{X: 2, Y: 1}}},
+			wantAvoid:  common.Right,
+		},
+		{
+			name:       "TestPathAvoidsPoison",
+			controller: PathName,
+			edges:      common.WallEdge,
+			candies:    []common.Sprite{{Value: '!', Position: common.Position{X: 2, Y: 1}}},
+			wantAvoid:  common.Right,
+		},
+		{
+			name:       "TestTailAvoidsPoison",
+			controller: TailName,
+			edges:      common.WallEdge,
+			candies:    []common.Sprite{{Value: '!', Position: common.Position{X: 2, Y: 1}}},
+			wantAvoid:  common.Right,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aController, err := New(tt.controller)
+			require.NoError(t, err)
+			game := gamestate.New(
+				gamestate.WithSeed(1),
+				gamestate.WithEdgePolicy(common.UniformEdges(tt.edges)),
+				gamestate.WithSnakeStart(common.Position{X: 1, Y: 1}, common.Right),
+				gamestate.WithCandies(tt.candies...),
+			)
+			require.NoError(t, game.InitBoard(common.Size{Width: 6, Height: 3}))
+			_, err = game.CreateObjects()
+			require.NoError(t, err)
+			direction, ok := aController.Next(game, 0)
+			require.True(t, ok)
+			if tt.wantAvoid != (common.Direction{}) {
+				require.NotEqual(t, tt.wantAvoid, direction)
+				return
+			}
+			require.Equal(t, tt.wantDirection, direction)
+		})
+	}
+}
+
+func Test_cycle(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      common.Size
+		wantCycle bool
+	}{
+		{name: "TestEvenSides", size: common.Size{Width: 4, Height: 4}, wantCycle: true},
+		{name: "TestEvenHeight", size: common.Size{Width: 5, Height: 4}, wantCycle: true},
+		{name: "TestEvenWidth", size: common.Size{Width: 4, Height: 5}, wantCycle: true},
+		{name: "TestSmallest", size: common.Size{Width: 2, Height: 2}, wantCycle: true},
+		{name: "TestOddSides", size: common.Size{Width: 5, Height: 5}},
+		{name: "TestOneRow", size: common.Size{Width: 4, Height: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aCycle := cycle(tt.size)
+			if !tt.wantCycle {
+				require.Nil(t, aCycle)
+				return
+			}
+			// Going from cell to cell by a single step visits the whole board
+			// and comes back to the start
+			cells := tt.size.Width * tt.size.Height
+			require.Len(t, aCycle, cells)
+			start := common.Position{}
+			position := start
+			visited := make(map[common.Position]bool)
+			for i := 0; i < cells; i++ {
+				visited[position] = true
+				next := aCycle[position]
+				require.Equal(t, 1, distance(position.X, next.X, 0, false)+distance(position.Y, next.Y, 0, false))
+				position = next
+			}
+			require.Equal(t, start, position)
+			require.Len(t, visited, cells)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// hamiltonian follows a cycle going through every cell of the board once.
+// The snake never meets itself so it fills a board without obstacles.
+// A board needs an even side for the cycle to exist
+type hamiltonian struct {
+	size  common.Size
+	cycle map[common.Position]common.Position // the cell following each cell
+}
+
+// NewHamiltonian returns a bot following a cycle through every cell
+func NewHamiltonian() Controller {
+	return &hamiltonian{}
+}
+
+func (aBot *hamiltonian) Next(game gamestate.GameStater, id common.SnakeID) (direction common.Direction, ok bool) {
+	if size := game.BoardSize(); size != aBot.size || aBot.cycle == nil {
+		aBot.size = size
+		aBot.cycle = cycle(size)
+	}
+	state, err := game.SnakeState(id)
+	if err != nil || len(state.Body) == 0 || aBot.cycle == nil {
+		return direction, false
+	}
+	head := state.Body[len(state.Body)-1]
+	next := aBot.cycle[head]
+
+	return common.Direction{DX: next.X - head.X, DY: next.Y - head.Y}, true
+}
+
+// cycle returns a Hamiltonian cycle of the board, nil when both sides are
+// odd or shorter than 2. The cycle runs along the first row, zigzags
+// through the other columns and comes back up the first column
+func cycle(size common.Size) map[common.Position]common.Position {
+	width, height := size.Width, size.Height
+	transposed := height%2 != 0
+	if transposed {
+		width, height = height, width
+	}
+	if height%2 != 0 || width < 2 || height < 2 {
+		return nil
+	}
+
+	order := make([]common.Position, 0, width*height)
+	for x := 0; x < width; x++ {
+		order = append(order, common.Position{X: x, Y: 0})
+	}
+	for y := 1; y < height; y++ {
+		for i := 1; i < width; i++ {
+			x := i
+			if y%2 != 0 {
+				x = width - i
+			}
+			order = append(order, common.Position{X: x, Y: y})
+		}
+	}
+	for y := height - 1; y > 0; y-- {
+		order = append(order, common.Position{X: 0, Y: y})
+	}
+
+	aCycle := make(map[common.Position]common.Position, len(order))
+	for i, position := range order {
+		next := order[(i+1)%len(order)]
+		if transposed {
+			position = common.Position{X: position.Y, Y: position.X}
+			next = common.Position{X: next.Y, Y: next.X}
+		}
+		aCycle[position] = next
+	}
+
+	return aCycle
+}
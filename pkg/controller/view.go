@@ -0,0 +1,253 @@
+package controller
+
+import (
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// view is the board as a bot sees it before a round
+type view struct {
+	game      gamestate.GameStater
+	size      common.Size
+	body      []common.Position // starts with the tail
+	direction common.Direction
+	static    [][]bool // obstacles, poison and the other snakes
+	targets   map[common.Position]bool
+}
+
+// move is a direction the snake can take and where it leads
+type move struct {
+	direction common.Direction
+	position  common.Position
+}
+
+// newView reads the board around the snake id, ok is false without the snake
+func newView(game gamestate.GameStater, id common.SnakeID) (aView *view, ok bool) {
+	state, err := game.SnakeState(id)
+	if err != nil || len(state.Body) == 0 {
+		return nil, false
+	}
+	aView = &view{
+		game:      game,
+		size:      game.BoardSize(),
+		body:      state.Body,
+		direction: state.Direction,
+		targets:   make(map[common.Position]bool),
+	}
+	poison := make(map[rune]bool)
+	for _, properties := range game.CandyKinds() {
+		if properties.Poison {
+			poison[properties.Body] = true
+		}
+	}
+	own := make(map[common.Position]bool, len(state.Body))
+	for _, position := range state.Body {
+		own[position] = true
+	}
+
+	aView.static = aView.grid()
+	for x := range aView.static {
+		for y := range aView.static[x] {
+			position := common.Position{X: x, Y: y}
+			cell, err := game.Cell(position)
+			if err != nil {
+				return nil, false
+			}
+			switch {
+			case game.IsCandy(cell) && !poison[cell]:
+				aView.targets[position] = true
+			case cell == game.FreeSpace(), own[position]:
+			default:
+				aView.static[x][y] = true
+			}
+		}
+	}
+
+	return aView, true
+}
+
+// grid returns an empty grid of the board size
+func (aView *view) grid() [][]bool {
+	cells := make([][]bool, aView.size.Width)
+	for x := range cells {
+		cells[x] = make([]bool, aView.size.Height)
+	}
+
+	return cells
+}
+
+// occupied returns the cells a head can't enter when the snake has the
+// given body. The tail leaves its cell, so it is free
+func (aView *view) occupied(body []common.Position) [][]bool {
+	cells := aView.grid()
+	for x := range cells {
+		copy(cells[x], aView.static[x])
+	}
+	for _, position := range body[1:] {
+		cells[position.X][position.Y] = true
+	}
+
+	return cells
+}
+
+func (aView *view) head() common.Position {
+	return aView.body[len(aView.body)-1]
+}
+
+func (aView *view) isTarget(position common.Position) bool {
+	return aView.targets[position]
+}
+
+// steps returns the free cells next to position
+func (aView *view) steps(position common.Position, blocked [][]bool) (steps []move) {
+	for _, direction := range directions {
+		next, _, ok := aView.game.NextPosition(position, direction)
+		if ok && !blocked[next.X][next.Y] {
+			steps = append(steps, move{
+				direction: direction,
+				position:  next,
+			})
+		}
+	}
+
+	return steps
+}
+
+// moves returns the directions the snake can take this round without dying.
+// Going back into the neck is ignored by the game so it is left out
+func (aView *view) moves() (moves []move) {
+	for _, aMove := range aView.steps(aView.head(), aView.occupied(aView.body)) {
+		if len(aView.body) > 1 && aMove.direction == aView.direction.Reverse() {
+			continue
+		}
+		moves = append(moves, aMove)
+	}
+
+	return moves
+}
+
+// path runs a breadth first search from the first moves to a cell accepted
+// by goal. It returns the cells of a shortest path, the goal included,
+// and the direction of its first move
+func (aView *view) path(first []move, blocked [][]bool, goal func(position common.Position) bool) (
+	path []common.Position, direction common.Direction, ok bool) {
+	visited := aView.grid()
+	parents := make(map[common.Position]common.Position)
+	origins := make(map[common.Position]common.Direction)
+	var queue []common.Position
+	for _, aMove := range first {
+		if visited[aMove.position.X][aMove.position.Y] {
+			continue
+		}
+		visited[aMove.position.X][aMove.position.Y] = true
+		origins[aMove.position] = aMove.direction
+		queue = append(queue, aMove.position)
+	}
+
+	for len(queue) > 0 {
+		position := queue[0]
+		queue = queue[1:]
+		if goal(position) {
+			for {
+				path = append([]common.Position{position}, path...)
+				parent, ok := parents[position]
+				if !ok {
+					break
+				}
+				position = parent
+			}
+			return path, origins[position], true
+		}
+		for _, aStep := range aView.steps(position, blocked) {
+			if visited[aStep.position.X][aStep.position.Y] {
+				continue
+			}
+			visited[aStep.position.X][aStep.position.Y] = true
+			parents[aStep.position] = position
+			queue = append(queue, aStep.position)
+		}
+	}
+
+	return nil, direction, false
+}
+
+// reachesTail tells if the head of body can still reach its tail
+func (aView *view) reachesTail(body []common.Position) bool {
+	tail := body[0]
+	head := body[len(body)-1]
+	blocked := aView.occupied(body)
+	isTail := func(position common.Position) bool { return position == tail }
+	_, _, ok := aView.path(aView.steps(head, blocked), blocked, isTail)
+
+	return ok
+}
+
+// roomiest returns the move leaving the most cells within reach
+func (aView *view) roomiest() (direction common.Direction, ok bool) {
+	best := -1
+	blocked := aView.occupied(aView.body)
+	for _, aMove := range aView.moves() {
+		room := aView.room(aMove.position, blocked)
+		if room > best {
+			best, direction, ok = room, aMove.direction, true
+		}
+	}
+
+	return direction, ok
+}
+
+// room counts the free cells that can be reached from position
+func (aView *view) room(position common.Position, blocked [][]bool) int {
+	visited := aView.grid()
+	visited[position.X][position.Y] = true
+	queue := []common.Position{position}
+	count := 0
+	for len(queue) > 0 {
+		position := queue[0]
+		queue = queue[1:]
+		count++
+		for _, aStep := range aView.steps(position, blocked) {
+			if !visited[aStep.position.X][aStep.position.Y] {
+				visited[aStep.position.X][aStep.position.Y] = true
+				queue = append(queue, aStep.position)
+			}
+		}
+	}
+
+	return count
+}
+
+// closestTarget returns the distance from position to the closest candy,
+// counting the shortcuts through the edges that wrap
+func (aView *view) closestTarget(position common.Position) int {
+	edgePolicy := aView.game.EdgePolicy()
+	wrapX := edgePolicy.Left == common.WrapEdge && edgePolicy.Right == common.WrapEdge
+	wrapY := edgePolicy.Top == common.WrapEdge && edgePolicy.Bottom == common.WrapEdge
+	best := -1
+	for target := range aView.targets {
+		d := distance(position.X, target.X, aView.size.Width, wrapX) +
+			distance(position.Y, target.Y, aView.size.Height, wrapY)
+		if best < 0 || d < best {
+			best = d
+		}
+	}
+	if best < 0 {
+		return 0 // No candy, every move is as good
+	}
+
+	return best
+}
+
+// distance returns the distance between two coordinates on a line of
+// the given length, going round when wrap is set
+func distance(a, b, length int, wrap bool) int {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	if wrap && length-d < d {
+		return length - d
+	}
+
+	return d
+}
@@ -0,0 +1,203 @@
+// Package difficulty sets how fast a game is played and how hard its board is.
+//
+// A Curve gives the delay between two rounds from the progress of the game,
+// Linear, Stepped and Exponential build the usual ones from a Measure of
+// the progress. A Level names a board size, a candy count, an edge policy
+// and a curve; Easy, Normal and Hard are the default ones
+package difficulty
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// DefaultInterval is the delay between two rounds of a game without a curve
+const DefaultInterval = 120 * time.Millisecond
+
+// MinInterval is the shortest delay between two rounds, the curves
+// never go below it
+const MinInterval = time.Millisecond
+
+// ErrUnknownLevel is a custom error thrown when no level has the given name
+var ErrUnknownLevel = errors.New("unknown difficulty level")
+
+// Progress is how far a game has gone, Length is the length of the snake 0
+type Progress struct {
+	Score  int
+	Length int
+	Round  int
+}
+
+// Measure is the value of the progress a curve follows
+type Measure int
+
+// Measures of the progress
+const (
+	Score Measure = iota
+	Length
+	Round
+)
+
+func (measure Measure) String() string {
+	switch measure {
+	case Score:
+		return "score"
+	case Length:
+		return "length"
+	case Round:
+		return "round"
+	}
+
+	return "unknown"
+}
+
+// of returns the value of the measure, never negative
+func (measure Measure) of(aProgress Progress) int {
+	var value int
+	switch measure {
+	case Score:
+		value = aProgress.Score
+	case Length:
+		value = aProgress.Length
+	case Round:
+		value = aProgress.Round
+	}
+	if value < 0 {
+		return 0
+	}
+
+	return value
+}
+
+// Curve returns the delay between two rounds for the progress of a game.
+// Any function can be used, the presets below cover the common cases
+type Curve func(aProgress Progress) time.Duration
+
+// Constant plays every round after interval, at least MinInterval
+func Constant(interval time.Duration) Curve {
+	interval = AtLeastMin(interval)
+	return func(aProgress Progress) time.Duration {
+		return interval
+	}
+}
+
+// The presets below stop at fastest, a fastest below MinInterval
+// is replaced by MinInterval
+
+// Linear starts at start and goes down by step for each unit of measure,
+// it stops at fastest
+func Linear(measure Measure, start, step, fastest time.Duration) Curve {
+	return func(aProgress Progress) time.Duration {
+		return atLeast(start-time.Duration(measure.of(aProgress))*step, fastest)
+	}
+}
+
+// Stepped starts at start and is multiplied by factor each time measure
+// goes past a multiple of every, it stops at fastest
+func Stepped(measure Measure, start time.Duration, every int, factor float64, fastest time.Duration) Curve {
+	if every < 1 {
+		every = 1
+	}
+	return func(aProgress Progress) time.Duration {
+		steps := measure.of(aProgress) / every
+		return atLeast(scale(start, math.Pow(factor, float64(steps))), fastest)
+	}
+}
+
+// Exponential starts at start and is multiplied by factor for each unit
+// of measure, it stops at fastest
+func Exponential(measure Measure, start time.Duration, factor float64, fastest time.Duration) Curve {
+	return func(aProgress Progress) time.Duration {
+		return atLeast(scale(start, math.Pow(factor, float64(measure.of(aProgress)))), fastest)
+	}
+}
+
+func scale(interval time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(interval) * factor)
+}
+
+func atLeast(interval, fastest time.Duration) time.Duration {
+	fastest = AtLeastMin(fastest)
+	if interval < fastest {
+		return fastest
+	}
+
+	return interval
+}
+
+// AtLeastMin returns interval, or MinInterval if it is shorter
+func AtLeastMin(interval time.Duration) time.Duration {
+	if interval < MinInterval {
+		return MinInterval
+	}
+
+	return interval
+}
+
+// Level is a named difficulty: the board, the candies and the speed of a game
+type Level struct {
+	Name    string
+	Size    common.Size
+	Candies int
+	Edges   common.EdgePolicy
+	Curve   Curve
+}
+
+// Default levels, the snake goes faster as it scores
+var (
+	Easy = Level{
+		Name:    "easy",
+		Size:    common.Size{Width: 30, Height: 15},
+		Candies: 3,
+		Edges:   common.UniformEdges(common.WrapEdge),
+		Curve:   Linear(Score, 200*time.Millisecond, 2*time.Millisecond, 120*time.Millisecond),
+	}
+	Normal = Level{
+		Name:    "normal",
+		Size:    common.Size{Width: 40, Height: 20},
+		Candies: 1,
+		Edges:   common.UniformEdges(common.WrapEdge),
+		Curve:   Stepped(Score, 150*time.Millisecond, 5, 0.9, 70*time.Millisecond),
+	}
+	Hard = Level{
+		Name:    "hard",
+		Size:    common.Size{Width: 40, Height: 20},
+		Candies: 1,
+		Edges:   common.UniformEdges(common.WallEdge),
+		Curve:   Exponential(Score, 110*time.Millisecond, 0.97, 40*time.Millisecond),
+	}
+)
+
+// Levels returns the default levels by name
+func Levels() map[string]Level {
+	return map[string]Level{
+		Easy.Name:   Easy,
+		Normal.Name: Normal,
+		Hard.Name:   Hard,
+	}
+}
+
+// Lookup returns the default level called name
+func Lookup(name string) (aLevel Level, err error) {
+	aLevel, ok := Levels()[name]
+	if !ok {
+		return aLevel, fmt.Errorf("%w %q, expected one of %v", ErrUnknownLevel, name, Names())
+	}
+
+	return aLevel, nil
+}
+
+// Names returns the names of the default levels in alphabetical order
+func Names() (names []string) {
+	for name := range Levels() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,114 @@
+package difficulty
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurves(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name         string
+		curve        Curve
+		progress     Progress
+		wantInterval time.Duration
+	}{
+		{
+			name:         "TestConstant",
+			curve:        Constant(100 * ms),
+			progress:     Progress{Score: 50},
+			wantInterval: 100 * ms,
+		},
+		{
+			name:         "TestLinear",
+			curve:        Linear(Score, 100*ms, 5*ms, 50*ms),
+			progress:     Progress{Score: 4, Length: 20, Round: 30},
+			wantInterval: 80 * ms,
+		},
+		{
+			name:         "TestLinearFastest",
+			curve:        Linear(Score, 100*ms, 5*ms, 50*ms),
+			progress:     Progress{Score: 40},
+			wantInterval: 50 * ms,
+		},
+		{
+			name:         "TestLinearLength",
+			curve:        Linear(Length, 100*ms, 5*ms, 50*ms),
+			progress:     Progress{Score: 4, Length: 2},
+			wantInterval: 90 * ms,
+		},
+		{
+			name:         "TestNegativeMeasure",
+			curve:        Linear(Score, 100*ms, 5*ms, 50*ms),
+			progress:     Progress{Score: -4},
+			wantInterval: 100 * ms,
+		},
+		{
+			name:         "TestSteppedBeforeStep",
+			curve:        Stepped(Round, 100*ms, 10, 0.5, 10*ms),
+			progress:     Progress{Round: 9},
+			wantInterval: 100 * ms,
+		},
+		{
+			name:         "TestStepped",
+			curve:        Stepped(Round, 100*ms, 10, 0.5, 10*ms),
+			progress:     Progress{Round: 25},
+			wantInterval: 25 * ms,
+		},
+		{
+			name:         "TestExponential",
+			curve:        Exponential(Score, 100*ms, 0.5, 10*ms),
+			progress:     Progress{Score: 3},
+			wantInterval: 12500 * time.Microsecond,
+		},
+		{
+			name:         "TestExponentialFastest",
+			curve:        Exponential(Score, 100*ms, 0.5, 20*ms),
+			progress:     Progress{Score: 3},
+			wantInterval: 20 * ms,
+		},
+		{
+			name:         "TestConstantZero",
+			curve:        Constant(0),
+			wantInterval: MinInterval,
+		},
+		{
+			name:         "TestLinearZeroFastest",
+			curve:        Linear(Score, 100*ms, 5*ms, 0),
+			progress:     Progress{Score: 40},
+			wantInterval: MinInterval,
+		},
+		{
+			name:         "TestSteppedNegativeFastest",
+			curve:        Stepped(Round, 100*ms, 1, 0, -ms),
+			progress:     Progress{Round: 1},
+			wantInterval: MinInterval,
+		},
+		{
+			name:         "TestExponentialZeroFastest",
+			curve:        Exponential(Score, 100*ms, 0.5, 0),
+			progress:     Progress{Score: 100},
+			wantInterval: MinInterval,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantInterval, tt.curve(tt.progress))
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	require.Equal(t, []string{"easy", "hard", "normal"}, Names())
+	for _, name := range Names() {
+		aLevel, err := Lookup(name)
+		require.NoError(t, err)
+		require.Equal(t, name, aLevel.Name)
+		// The levels get faster as the snake scores
+		require.Greater(t, aLevel.Curve(Progress{}), aLevel.Curve(Progress{Score: 20}))
+	}
+	_, err := Lookup("nightmare")
+	require.ErrorIs(t, err, ErrUnknownLevel)
+}
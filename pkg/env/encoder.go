@@ -0,0 +1,297 @@
+package env
+
+import (
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// Observation is a tensor of ints stored row major:
+// the last dimension of Shape varies the fastest in Data
+type Observation struct {
+	Shape []int
+	Data  []int
+}
+
+// Encoder turns the game into what the agent observes
+type Encoder interface {
+	Shape(size common.Size) []int
+	Encode(game gamestate.GameStater) (observation Observation, err error)
+}
+
+// Codes of the cells in the grid and the window observations
+const (
+	CellFree     = iota
+	CellBody     // a part of the agent snake
+	CellHead     // the head of the agent snake
+	CellCandy    // a candy that isn't poison
+	CellPoison   // a poison candy
+	CellObstacle // an obstacle
+	CellSnake    // a part of another snake
+	CellWall     // outside the board, only in the window
+)
+
+// directions are the order of the dangers, of the direction
+// and of the candy flags in the feature vector
+var directions = []common.Direction{common.Up, common.Right, common.Down, common.Left}
+
+// GridEncoder observes the whole board as a Height x Width grid of cell codes
+type GridEncoder struct{}
+
+// Shape returns [Height, Width]
+func (GridEncoder) Shape(size common.Size) []int {
+	return []int{size.Height, size.Width}
+}
+
+// Encode returns the code of every cell
+func (anEncoder GridEncoder) Encode(game gamestate.GameStater) (observation Observation, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	size := game.BoardSize()
+	codes, err := cellCodes(game)
+	if err != nil {
+		return observation, err
+	}
+	observation = Observation{
+		Shape: anEncoder.Shape(size),
+		Data:  make([]int, 0, size.Width*size.Height),
+	}
+	for y := 0; y < size.Height; y++ {
+		for x := 0; x < size.Width; x++ {
+			observation.Data = append(observation.Data, codes[x][y])
+		}
+	}
+
+	return observation, nil
+}
+
+// WindowEncoder observes the square of cells within Radius of the head,
+// the head is in its middle. It goes round the edges that wrap,
+// the cells beyond the other edges are walls
+type WindowEncoder struct {
+	Radius int
+}
+
+// Shape returns [2*Radius+1, 2*Radius+1]
+func (anEncoder WindowEncoder) Shape(common.Size) []int {
+	side := 2*anEncoder.Radius + 1
+	return []int{side, side}
+}
+
+// Encode returns the codes of the cells around the head, all walls without a snake
+func (anEncoder WindowEncoder) Encode(game gamestate.GameStater) (observation Observation, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	size := game.BoardSize()
+	side := 2*anEncoder.Radius + 1
+	observation = Observation{
+		Shape: anEncoder.Shape(size),
+		Data:  make([]int, side*side),
+	}
+	head, err := game.SnakePosition()
+	if err != nil {
+		for i := range observation.Data {
+			observation.Data[i] = CellWall
+		}
+		return observation, nil
+	}
+	codes, err := cellCodes(game)
+	if err != nil {
+		return observation, err
+	}
+
+	wrapX, wrapY := wraps(game.EdgePolicy())
+	for dy := -anEncoder.Radius; dy <= anEncoder.Radius; dy++ {
+		for dx := -anEncoder.Radius; dx <= anEncoder.Radius; dx++ {
+			x, okX := inside(head.X+dx, size.Width, wrapX)
+			y, okY := inside(head.Y+dy, size.Height, wrapY)
+			code := CellWall
+			if okX && okY {
+				code = codes[x][y]
+			}
+			observation.Data[(dy+anEncoder.Radius)*side+dx+anEncoder.Radius] = code
+		}
+	}
+
+	return observation, nil
+}
+
+// FeatureEncoder observes 12 flags, 0 or 1, by groups of four
+// in the order up, right, down and left:
+// the moves that kill the snake, the direction of the snake
+// and the directions of the closest candy
+type FeatureEncoder struct{}
+
+// FeatureCount is the length of the feature vector
+const FeatureCount = 12
+
+// Shape returns [FeatureCount]
+func (FeatureEncoder) Shape(common.Size) []int {
+	return []int{FeatureCount}
+}
+
+// Encode returns the flags, all zero without a snake
+func (anEncoder FeatureEncoder) Encode(game gamestate.GameStater) (observation Observation, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	observation = Observation{
+		Shape: anEncoder.Shape(game.BoardSize()),
+		Data:  make([]int, FeatureCount),
+	}
+	state, err := game.SnakeState(0)
+	if err != nil || len(state.Body) == 0 {
+		return observation, nil
+	}
+	codes, err := cellCodes(game)
+	if err != nil {
+		return observation, err
+	}
+	head := state.Body[len(state.Body)-1]
+	tail := state.Body[0]
+
+	candy, found := closestCandy(game, head)
+	delta := common.Position{}
+	if found >= 0 {
+		size := game.BoardSize()
+		wrapX, wrapY := wraps(game.EdgePolicy())
+		delta.X = offset(head.X, candy.X, size.Width, wrapX)
+		delta.Y = offset(head.Y, candy.Y, size.Height, wrapY)
+	}
+	for i, direction := range directions {
+		next, _, ok := game.NextPosition(head, direction)
+		if !ok || (deadly(codes[next.X][next.Y]) && next != tail) {
+			observation.Data[i] = 1
+		}
+		if direction == state.Direction {
+			observation.Data[4+i] = 1
+		}
+		if found >= 0 && (delta.X*direction.DX > 0 || delta.Y*direction.DY > 0) {
+			observation.Data[8+i] = 1
+		}
+	}
+
+	return observation, nil
+}
+
+// cellCodes returns the code of every cell, indexed by x then y
+func cellCodes(game gamestate.GameStater) (codes [][]int, err error) {
+	size := game.BoardSize()
+	poison := poisonBodies(game)
+	own := make(map[common.Position]int)
+	if state, err := game.SnakeState(0); err == nil {
+		for i, position := range state.Body {
+			own[position] = CellBody
+			if i == len(state.Body)-1 {
+				own[position] = CellHead
+			}
+		}
+	}
+
+	codes = make([][]int, size.Width)
+	for x := range codes {
+		codes[x] = make([]int, size.Height)
+		for y := range codes[x] {
+			position := common.Position{X: x, Y: y}
+			cell, err := game.Cell(position)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case own[position] != CellFree:
+				codes[x][y] = own[position]
+			case cell == game.FreeSpace():
+				codes[x][y] = CellFree
+			case game.IsCandy(cell) && poison[cell]:
+				codes[x][y] = CellPoison
+			case game.IsCandy(cell):
+				codes[x][y] = CellCandy
+			case game.IsObstacle(cell):
+				codes[x][y] = CellObstacle
+			default:
+				codes[x][y] = CellSnake
+			}
+		}
+	}
+
+	return codes, nil
+}
+
+// deadly tells if the head dies entering a cell of this code
+func deadly(code int) bool {
+	switch code {
+	case CellFree, CellCandy:
+		return false
+	}
+
+	return true
+}
+
+func poisonBodies(game gamestate.GameStater) map[rune]bool {
+	poison := make(map[rune]bool)
+	for _, properties := range game.CandyKinds() {
+		if properties.Poison {
+			poison[properties.Body] = true
+		}
+	}
+
+	return poison
+}
+
+// closestCandy returns the closest candy that isn't poison and its distance,
+// counting the shortcuts through the edges that wrap. The distance is -1 without a candy
+func closestCandy(game gamestate.GameStater, position common.Position) (candy common.Position, distance int) {
+	size := game.BoardSize()
+	wrapX, wrapY := wraps(game.EdgePolicy())
+	poison := poisonBodies(game)
+	distance = -1
+	for _, candyPosition := range game.CandyPositions() {
+		if cell, err := game.Cell(candyPosition); err != nil || poison[cell] {
+			continue
+		}
+		d := abs(offset(position.X, candyPosition.X, size.Width, wrapX)) +
+			abs(offset(position.Y, candyPosition.Y, size.Height, wrapY))
+		if distance < 0 || d < distance {
+			candy, distance = candyPosition, d
+		}
+	}
+
+	return candy, distance
+}
+
+// wraps tells if the horizontal and the vertical edges wrap
+func wraps(edgePolicy common.EdgePolicy) (wrapX, wrapY bool) {
+	return edgePolicy.Left == common.WrapEdge && edgePolicy.Right == common.WrapEdge,
+		edgePolicy.Top == common.WrapEdge && edgePolicy.Bottom == common.WrapEdge
+}
+
+// offset returns the shortest signed step from a to b on a line of
+// the given length, going round when wrap is set
+func offset(a, b, length int, wrap bool) int {
+	d := b - a
+	if wrap {
+		if d > length/2 {
+			d -= length
+		} else if d < -length/2 {
+			d += length
+		}
+	}
+
+	return d
+}
+
+// inside brings a coordinate back on a line of the given length
+// when wrap is set, ok is false when it is off the line
+func inside(a, length int, wrap bool) (int, bool) {
+	if wrap {
+		return ((a % length) + length) % length, true
+	}
+
+	return a, a >= 0 && a < length
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
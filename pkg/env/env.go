@@ -0,0 +1,223 @@
+// Package env wraps a game in a reinforcement learning environment:
+// Reset starts an episode and Step plays an action, they return what the
+// agent observes, Step also returns the reward and whether the episode is done.
+// The agent steers the snake 0
+package env
+
+import (
+	"errors"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// Action is what the agent does before a round
+type Action int
+
+// Actions of the agent, Keep leaves the snake going in its direction
+const (
+	Keep Action = iota
+	Left
+	Right
+	Up
+	Down
+)
+
+// ActionCount is the number of actions
+const ActionCount = 5
+
+// Defines custom errors
+var (
+	ErrNotReset      = errors.New("the environment has not been reset")
+	ErrDone          = errors.New("the episode is done, reset the environment")
+	ErrInvalidAction = errors.New("invalid action")
+)
+
+// Reward shapes the reward returned by Step
+type Reward struct {
+	Point  float64 // for each point scored
+	Death  float64 // when the game is lost
+	Win    float64 // when the game is won
+	Step   float64 // for every round played
+	Closer float64 // when the head gets closer to a candy, it is taken back when it goes away
+}
+
+// DefaultReward rewards the points and the win, it punishes the death
+var DefaultReward = Reward{
+	Point: 1,
+	Death: -1,
+	Win:   1,
+}
+
+// Info tells more about the game after a step.
+// Truncated is set when the episode is stopped by the round limit
+type Info struct {
+	Round     int
+	Score     int
+	Length    int
+	Reason    common.GameOverReason
+	Outcome   common.Outcome
+	Truncated bool
+}
+
+// Env is a game the agent plays episode after episode
+type Env struct {
+	size        common.Size
+	gameOptions []gamestate.Option
+	encoder     Encoder
+	reward      Reward
+	maxRounds   int
+	game        gamestate.GameStater
+	done        bool
+}
+
+// Option configures an Env created by New
+type Option func(anEnv *Env)
+
+// WithEncoder sets how the game is observed, GridEncoder by default
+func WithEncoder(encoder Encoder) Option {
+	return func(anEnv *Env) {
+		anEnv.encoder = encoder
+	}
+}
+
+// WithReward replaces DefaultReward
+func WithReward(reward Reward) Option {
+	return func(anEnv *Env) {
+		anEnv.reward = reward
+	}
+}
+
+// WithMaxRounds stops the episodes after rounds rounds, 0 never stops them
+func WithMaxRounds(rounds int) Option {
+	return func(anEnv *Env) {
+		anEnv.maxRounds = rounds
+	}
+}
+
+// WithGameOptions sets the options of the games, the seed is set by Reset
+func WithGameOptions(options ...gamestate.Option) Option {
+	return func(anEnv *Env) {
+		anEnv.gameOptions = append(anEnv.gameOptions, options...)
+	}
+}
+
+// New returns an environment playing on boards of the given size
+func New(size common.Size, options ...Option) *Env {
+	anEnv := &Env{
+		size:    size,
+		encoder: GridEncoder{},
+		reward:  DefaultReward,
+	}
+	for _, option := range options {
+		option(anEnv)
+	}
+
+	return anEnv
+}
+
+// Reset starts an episode with the game of the given seed
+func (anEnv *Env) Reset(seed int64) (observation Observation, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	options := append(append([]gamestate.Option(nil), anEnv.gameOptions...), gamestate.WithSeed(seed))
+	game := gamestate.New(options...)
+	if err = game.InitBoard(anEnv.size); err != nil {
+		return observation, err
+	}
+	if _, err = game.CreateObjects(); err != nil {
+		return observation, err
+	}
+	game.Start()
+	anEnv.game = game
+	anEnv.done = false
+
+	return anEnv.encoder.Encode(game)
+}
+
+// Step plays a round after the action
+func (anEnv *Env) Step(action Action) (observation Observation, reward float64, done bool, info Info, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if anEnv.game == nil {
+		return observation, 0, true, info, ErrNotReset
+	}
+	if anEnv.done {
+		return observation, 0, true, anEnv.info(), ErrDone
+	}
+	game := anEnv.game
+	switch action {
+	case Keep:
+	case Left:
+		game.MoveLeft()
+	case Right:
+		game.MoveRight()
+	case Up:
+		game.MoveUp()
+	case Down:
+		game.MoveDown()
+	default:
+		return observation, 0, false, anEnv.info(), ErrInvalidAction
+	}
+
+	score := game.Score()
+	before := anEnv.candyDistance()
+	if _, err = game.Play(); err != nil {
+		anEnv.done = true
+		return observation, 0, true, anEnv.info(), err
+	}
+
+	reward = anEnv.reward.Step + anEnv.reward.Point*float64(game.Score()-score)
+	info = anEnv.info()
+	switch {
+	case game.Outcome() == common.Won:
+		reward += anEnv.reward.Win
+		anEnv.done = true
+	case !game.GameInProgress():
+		reward += anEnv.reward.Death
+		anEnv.done = true
+	case anEnv.maxRounds > 0 && game.Round() >= anEnv.maxRounds:
+		info.Truncated = true
+		anEnv.done = true
+	}
+	if after := anEnv.candyDistance(); game.Score() == score && before >= 0 && after >= 0 {
+		reward += anEnv.reward.Closer * float64(before-after)
+	}
+	if observation, err = anEnv.encoder.Encode(game); err != nil {
+		return observation, reward, anEnv.done, info, err
+	}
+
+	return observation, reward, anEnv.done, info, nil
+}
+
+// Game returns the game of the current episode, it must not be played directly
+func (anEnv *Env) Game() gamestate.GameStater {
+	return anEnv.game
+}
+
+// ObservationShape returns the shape of the observations
+func (anEnv *Env) ObservationShape() []int {
+	return anEnv.encoder.Shape(anEnv.size)
+}
+
+func (anEnv *Env) info() Info {
+	length, _ := anEnv.game.SnakeSize()
+	return Info{
+		Round:   anEnv.game.Round(),
+		Score:   anEnv.game.Score(),
+		Length:  length,
+		Reason:  anEnv.game.GameOverReason(),
+		Outcome: anEnv.game.Outcome(),
+	}
+}
+
+// candyDistance returns the distance from the head to the closest candy,
+// -1 without a snake or a candy
+func (anEnv *Env) candyDistance() int {
+	head, err := anEnv.game.SnakePosition()
+	if err != nil {
+		return -1
+	}
+	_, distance := closestCandy(anEnv.game, head)
+	return distance
+}
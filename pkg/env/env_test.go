@@ -0,0 +1,242 @@
+package env
+
+import (
+	"testing"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+var size5_5 = common.Size{
+	Width:  5,
+	Height: 5,
+}
+
+// levelOptions set a 5x5 board:
+//
+//	.....
+//	.#...
+//	.@.*.
+//	.!...
+//	.....
+//
+// The snake goes right, the candy doesn't respawn while the tests run
+func levelOptions(edge common.Edge) []gamestate.Option {
+	return []gamestate.Option{
+		gamestate.WithEdgePolicy(common.UniformEdges(edge)),
+		gamestate.WithCandyRule(common.CandyRule{Count: 1, Respawn: common.RespawnDelayed, Delay: 100}),
+		gamestate.WithCandies(
+			common.Sprite{Value: '*', Position: common.Position{X: 3, Y: 2}},
+			common.Sprite{Value: '!', Position: common.Position{X: 1, Y: 3}},
+		),
+		gamestate.WithObstacles(common.Position{X: 1, Y: 1}),
+		gamestate.WithSnakeStart(common.Position{X: 1, Y: 2}, common.Right),
+	}
+}
+
+func TestEnv_Step(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []Option
+		actions     []Action
+		wantRewards []float64
+		wantDone    bool
+		wantInfo    Info
+	}{
+		{
+			name:        "TestCandy",
+			actions:     []Action{Keep, Keep},
+			wantRewards: []float64{0, 1},
+			wantInfo:    Info{Round: 2, Score: 1, Length: 2},
+		},
+		{
+			name:        "TestWall",
+			actions:     []Action{Keep, Keep, Keep, Keep},
+			wantRewards: []float64{0, 1, 0, -1},
+			wantDone:    true,
+			wantInfo:    Info{Round: 4, Score: 1, Length: 2, Reason: common.WallCollision, Outcome: common.Lost},
+		},
+		{
+			name:        "TestWin",
+			options:     []Option{WithGameOptions(gamestate.WithWinCondition(common.WinCondition{Goal: common.ReachScore, Target: 1}))},
+			actions:     []Action{Keep, Keep},
+			wantRewards: []float64{0, 2},
+			wantDone:    true,
+			wantInfo:    Info{Round: 2, Score: 1, Length: 2, Outcome: common.Won},
+		},
+		{
+			name:        "TestTurn",
+			actions:     []Action{Keep, Up, Up},
+			wantRewards: []float64{0, 0, 0},
+			wantInfo:    Info{Round: 3, Length: 1},
+		},
+		{
+			name:        "TestTruncated",
+			options:     []Option{WithMaxRounds(3)},
+			actions:     []Action{Keep, Up, Up},
+			wantRewards: []float64{0, 0, 0},
+			wantDone:    true,
+			wantInfo:    Info{Round: 3, Length: 1, Truncated: true},
+		},
+		{
+			name:        "TestShaping",
+			options:     []Option{WithReward(Reward{Point: 2, Step: -0.1, Closer: 0.5})},
+			actions:     []Action{Keep, Keep, Keep},
+			wantRewards: []float64{0.4, 1.9, -0.1},
+			wantInfo:    Info{Round: 3, Score: 1, Length: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{WithGameOptions(levelOptions(common.WallEdge)...)}, tt.options...)
+			anEnv := New(size5_5, options...)
+			_, err := anEnv.Reset(1)
+			require.NoError(t, err)
+
+			var done bool
+			var info Info
+			for i, action := range tt.actions {
+				var reward float64
+				_, reward, done, info, err = anEnv.Step(action)
+				require.NoError(t, err)
+				require.InDelta(t, tt.wantRewards[i], reward, 1e-9, "step %d", i)
+			}
+			require.Equal(t, tt.wantDone, done)
+			require.Equal(t, tt.wantInfo, info)
+			if done {
+				_, _, _, _, err = anEnv.Step(Keep)
+				require.ErrorIs(t, err, ErrDone)
+			}
+		})
+	}
+}
+
+func TestEnv_Errors(t *testing.T) {
+	anEnv := New(size5_5, WithGameOptions(levelOptions(common.WallEdge)...))
+	_, _, _, _, err := anEnv.Step(Keep)
+	require.ErrorIs(t, err, ErrNotReset)
+
+	_, err = anEnv.Reset(1)
+	require.NoError(t, err)
+	_, _, _, _, err = anEnv.Step(ActionCount)
+	require.ErrorIs(t, err, ErrInvalidAction)
+	require.Equal(t, 0, anEnv.Game().Round())
+}
+
+func TestEnv_Reset(t *testing.T) {
+	// The same seed plays the same episode
+	play := func() (observations []Observation) {
+		anEnv := New(size5_5, WithEncoder(GridEncoder{}))
+		observation, err := anEnv.Reset(7)
+		require.NoError(t, err)
+		observations = append(observations, observation)
+		for i := 0; i < 10; i++ {
+			observation, _, done, _, err := anEnv.Step(Action(i % ActionCount))
+			require.NoError(t, err)
+			observations = append(observations, observation)
+			if done {
+				break
+			}
+		}
+		return observations
+	}
+	require.Equal(t, play(), play())
+}
+
+func TestEncoders(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoder Encoder
+		edge    common.Edge
+		want    Observation
+	}{
+		{
+			name:    "TestGrid",
+			encoder: GridEncoder{},
+			edge:    common.WallEdge,
+			want: Observation{
+				Shape: []int{5, 5},
+				Data: []int{
+					0, 0, 0, 0, 0,
+					0, 5, 0, 0, 0,
+					0, 2, 0, 3, 0,
+					0, 4, 0, 0, 0,
+					0, 0, 0, 0, 0,
+				},
+			},
+		},
+		{
+			name:    "TestWindowWall",
+			encoder: WindowEncoder{Radius: 2},
+			edge:    common.WallEdge,
+			want: Observation{
+				Shape: []int{5, 5},
+				Data: []int{
+					7, 0, 0, 0, 0,
+					7, 0, 5, 0, 0,
+					7, 0, 2, 0, 3,
+					7, 0, 4, 0, 0,
+					7, 0, 0, 0, 0,
+				},
+			},
+		},
+		{
+			name:    "TestWindowWrap",
+			encoder: WindowEncoder{Radius: 1},
+			edge:    common.WrapEdge,
+			want: Observation{
+				Shape: []int{3, 3},
+				Data: []int{
+					0, 5, 0,
+					0, 2, 0,
+					0, 4, 0,
+				},
+			},
+		},
+		{
+			name:    "TestFeatures",
+			encoder: FeatureEncoder{},
+			edge:    common.WallEdge,
+			want: Observation{
+				Shape: []int{FeatureCount},
+				Data: []int{
+					1, 0, 1, 0, // dangers: the obstacle and the poison
+					0, 1, 0, 0, // going right
+					0, 1, 0, 0, // the candy is on the right
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anEnv := New(size5_5, WithEncoder(tt.encoder), WithGameOptions(levelOptions(tt.edge)...))
+			observation, err := anEnv.Reset(1)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, observation)
+			require.Equal(t, tt.want.Shape, anEnv.ObservationShape())
+		})
+	}
+}
+
+func Test_offset(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   int
+		length int
+		wrap   bool
+		wantD  int
+	}{
+		{name: "TestForward", a: 1, b: 3, length: 5, wantD: 2},
+		{name: "TestBackward", a: 3, b: 1, length: 5, wantD: -2},
+		{name: "TestNoWrap", a: 0, b: 4, length: 5, wantD: 4},
+		{name: "TestWrap", a: 0, b: 4, length: 5, wrap: true, wantD: -1},
+		{name: "TestWrapBack", a: 4, b: 0, length: 5, wrap: true, wantD: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantD, offset(tt.a, tt.b, tt.length, tt.wrap))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package event
+
+import (
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// Kind is the kind of an event
+type Kind int
+
+// Kinds of events sent by a game
+const (
+	CandyEaten       Kind = iota + 1 // the snake ate the Candy at Position
+	ScoreChanged                     // the score is now Score
+	HighScoreChanged                 // the high score is now Score
+	CandySpawned                     // a Candy appeared at Position
+	GameOver                         // the game ended for Reason, the snake head is at Position
+	CandyExpired                     // the candy at Position disappeared uneaten
+	SnakeDied                        // the Snake died for Reason, its head was at Position
+	GameWon                          // the win condition was met, the snake head is at Position
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case CandyEaten:
+		return "candy eaten"
+	case ScoreChanged:
+		return "score changed"
+	case HighScoreChanged:
+		return "high score changed"
+	case CandySpawned:
+		return "candy spawned"
+	case GameOver:
+		return "game over"
+	case CandyExpired:
+		return "candy expired"
+	case SnakeDied:
+		return "snake died"
+	case GameWon:
+		return "game won"
+	}
+
+	return "unknown"
+}
+
+// Event is something that happened during a round.
+// The meaning of the fields depends on the Kind, Snake is
+// the snake that ate a candy, scored or died
+type Event struct {
+	Kind     Kind
+	Round    int
+	Position common.Position
+	Score    int
+	Reason   common.GameOverReason
+	Candy    common.CandyKind
+	Snake    common.SnakeID
+}
+
+// Handler receives the events of a game
+type Handler func(anEvent Event)
+
+// Dispatcher calls the handlers subscribed to it
+type Dispatcher struct {
+	handlers []subscription
+	nextID   int
+}
+
+// subscription is a handler and the identifier used to unsubscribe
+type subscription struct {
+	id      int
+	handler Handler
+}
+
+// Subscribe adds a handler, calling unsubscribe removes it
+func (aDispatcher *Dispatcher) Subscribe(handler Handler) (unsubscribe func()) {
+	aDispatcher.nextID++
+	id := aDispatcher.nextID
+	aDispatcher.handlers = append(aDispatcher.handlers, subscription{
+		id:      id,
+		handler: handler,
+	})
+
+	return func() {
+		for i, aSubscription := range aDispatcher.handlers {
+			if aSubscription.id == id {
+				aDispatcher.handlers = append(aDispatcher.handlers[:i:i], aDispatcher.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Emit sends the event to every handler in subscription order
+func (aDispatcher *Dispatcher) Emit(anEvent Event) {
+	for _, aSubscription := range aDispatcher.handlers {
+		aSubscription.handler(anEvent)
+	}
+}
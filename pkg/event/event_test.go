@@ -0,0 +1,64 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_Subscribe(t *testing.T) {
+	tests := []struct {
+		name        string
+		unsubscribe []int // indexes of the handlers removed before the emit
+		wantCalls   []int
+	}{
+		{
+			name:      "TestNoUnsubscribe",
+			wantCalls: []int{0, 1, 2},
+		},
+		{
+			name:        "TestUnsubscribeMiddle",
+			unsubscribe: []int{1},
+			wantCalls:   []int{0, 2},
+		},
+		{
+			name:        "TestUnsubscribeTwice", // The second call does nothing
+			unsubscribe: []int{0, 0},
+			wantCalls:   []int{1, 2},
+		},
+		{
+			name:        "TestUnsubscribeAll",
+			unsubscribe: []int{2, 0, 1},
+			wantCalls:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var aDispatcher Dispatcher
+			var gotCalls []int
+			var unsubscribes []func()
+			for i := 0; i < 3; i++ {
+				index := i
+				unsubscribes = append(unsubscribes, aDispatcher.Subscribe(func(anEvent Event) {
+					require.Equal(t, CandyEaten, anEvent.Kind)
+					gotCalls = append(gotCalls, index)
+				}))
+			}
+			for _, index := range tt.unsubscribe {
+				unsubscribes[index]()
+			}
+			aDispatcher.Emit(Event{
+				Kind: CandyEaten,
+			})
+			require.Equal(t, tt.wantCalls, gotCalls)
+		})
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	require.Equal(t, "candy eaten", CandyEaten.String())
+	require.Equal(t, "game over", GameOver.String())
+	require.Equal(t, "candy expired", CandyExpired.String())
+	require.Equal(t, "game won", GameWon.String())
+	require.Equal(t, "unknown", Kind(0).String())
+}
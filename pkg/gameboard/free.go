@@ -0,0 +1,99 @@
+package gameboard
+
+import "github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
+// freeCells indexes the free cells of a board so one can be drawn in O(log n).
+// The cells are ranked column by column, the cell x,y has the rank x*height+y.
+// The free cell at rank i only depends on which cells are free, not on the
+// moves that freed them: a board and its restored snapshot draw the same cells.
+// tree is a Fenwick tree counting the free cells of each range of ranks
+type freeCells struct {
+	height int
+	free   []bool
+	tree   []int
+	total  int
+}
+
+// newFreeCells indexes the free cells of board
+func newFreeCells(board [][]rune) *freeCells {
+	free := &freeCells{}
+	if len(board) > 0 {
+		free.height = len(board[0])
+	}
+	cells := len(board) * free.height
+	free.free = make([]bool, cells)
+	free.tree = make([]int, cells+1)
+	for x := range board {
+		for y := range board[x] {
+			if board[x][y] == FreeSpace {
+				rank := x*free.height + y
+				free.free[rank] = true
+				free.tree[rank+1] = 1
+				free.total++
+			}
+		}
+	}
+	// Each node adds its count to its parent, in O(n)
+	for i := 1; i <= cells; i++ {
+		if parent := i + i&-i; parent <= cells {
+			free.tree[parent] += free.tree[i]
+		}
+	}
+
+	return free
+}
+
+func (free *freeCells) count() int {
+	return free.total
+}
+
+// at returns the free cell at rank i among the free cells, 0 <= i < count
+func (free *freeCells) at(i int) common.Position {
+	cells := len(free.free)
+	step := 1
+	for step*2 <= cells {
+		step *= 2
+	}
+	// rank is the last rank having at most i free cells up to it
+	rank := 0
+	for ; step > 0; step /= 2 {
+		if rank+step <= cells && free.tree[rank+step] <= i {
+			rank += step
+			i -= free.tree[rank]
+		}
+	}
+
+	return common.Position{X: rank / free.height, Y: rank % free.height}
+}
+
+// set records the new value of a cell
+func (free *freeCells) set(position common.Position, value rune) {
+	rank := position.X*free.height + position.Y
+	isFree := value == FreeSpace
+	if free.free[rank] == isFree {
+		return
+	}
+	free.free[rank] = isFree
+	delta := 1
+	if !isFree {
+		delta = -1
+	}
+	free.total += delta
+	for i := rank + 1; i < len(free.tree); i += i & -i {
+		free.tree[i] += delta
+	}
+}
+
+// clone returns a copy of the index, nil for a nil index
+func (free *freeCells) clone() *freeCells {
+	if free == nil {
+		return nil
+	}
+
+	return &freeCells{
+		height: free.height,
+		free:   append([]bool(nil), free.free...),
+		tree:   append([]int(nil), free.tree...),
+		total:  free.total,
+	}
+}
@@ -0,0 +1,126 @@
+package gameboard
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameBoard_freeCells(t *testing.T) {
+	// setCell keeps the index of the free cells up to date
+	aGameBoard := New().(*gameBoard)
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+	last := common.Position{X: 2, Y: 1}
+	for x := 0; x < 3; x++ {
+		for y := 0; y < 3; y++ {
+			if position := (common.Position{X: x, Y: y}); position != last {
+				require.NoError(t, aGameBoard.setCell(position, ObstacleBody))
+			}
+		}
+	}
+	for i := 0; i < 10; i++ {
+		position, err := aGameBoard.RandomFreePosition()
+		require.NoError(t, err)
+		require.Equal(t, last, position)
+	}
+
+	require.NoError(t, aGameBoard.setCell(last, CandyBody))
+	_, err := aGameBoard.RandomFreePosition()
+	require.ErrorIs(t, err, ErrBoardFull)
+
+	require.NoError(t, aGameBoard.setCell(testdata.Position0_0, FreeSpace))
+	require.NoError(t, aGameBoard.setCell(testdata.Position0_0, FreeSpace))
+	require.Equal(t, 1, aGameBoard.freeCells().count())
+	position, err := aGameBoard.RandomFreePosition()
+	require.NoError(t, err)
+	require.Equal(t, testdata.Position0_0, position)
+}
+
+func TestFreeCells_at(t *testing.T) {
+	// The free cells are ranked column by column whatever freed them
+	aGameBoard := filledBoard(t, 0.5)
+	aGameBoard.freeCells()
+	aRandom := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		position := common.Position{X: aRandom.Intn(100), Y: aRandom.Intn(100)}
+		value := FreeSpace
+		if aRandom.Intn(2) == 0 {
+			value = SnakePart
+		}
+		require.NoError(t, aGameBoard.setCell(position, value))
+	}
+	var want []common.Position
+	for x := range aGameBoard.board {
+		for y := range aGameBoard.board[x] {
+			if aGameBoard.board[x][y] == FreeSpace {
+				want = append(want, common.Position{X: x, Y: y})
+			}
+		}
+	}
+	free := aGameBoard.freeCells()
+	require.Equal(t, len(want), free.count())
+	for i, position := range want {
+		require.Equal(t, position, free.at(i))
+	}
+	require.Equal(t, newFreeCells(aGameBoard.board), free)
+}
+
+// rejectionFreePosition is the former RandomFreePosition: it draws cells
+// until one is free, it never returns on a full board
+func rejectionFreePosition(aGameBoard *gameBoard) (position common.Position, err error) {
+	for {
+		if position.X, err = aGameBoard.random(aGameBoard.size.Width); err != nil {
+			return position, err
+		}
+		if position.Y, err = aGameBoard.random(aGameBoard.size.Height); err != nil {
+			return position, err
+		}
+		if aGameBoard.board[position.X][position.Y] == FreeSpace {
+			return position, nil
+		}
+	}
+}
+
+// filledBoard returns a 100x100 board with the given part of its cells taken
+func filledBoard(b testing.TB, fill float64) *gameBoard {
+	aGameBoard := New().(*gameBoard)
+	size := common.Size{
+		Width:  100,
+		Height: 100,
+	}
+	require.NoError(b, aGameBoard.InitGameBoard(size))
+	cells := size.Width * size.Height
+	for _, i := range rand.New(rand.NewSource(1)).Perm(cells)[:int(fill*float64(cells))] {
+		require.NoError(b, aGameBoard.setCell(common.Position{X: i % size.Width, Y: i / size.Width}, SnakePart))
+	}
+
+	return aGameBoard
+}
+
+func BenchmarkRandomFreePosition(b *testing.B) {
+	for _, fill := range []float64{0.1, 0.9, 0.999} {
+		b.Run(fmt.Sprintf("Indexed/%.1f%%", fill*100), func(b *testing.B) {
+			aGameBoard := filledBoard(b, fill)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := aGameBoard.RandomFreePosition(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Rejection/%.1f%%", fill*100), func(b *testing.B) {
+			aGameBoard := filledBoard(b, fill)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := rejectionFreePosition(aGameBoard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -1,35 +1,57 @@
 package gameboard
 
 import (
-	"crypto/rand"
 	"errors"
-	"math/big"
+	"fmt"
+	"sort"
 
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/random"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snake"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 )
 
 // Objects' body representation
 const (
-	FreeSpace rune = ' '
-	SnakePart rune = 'S'
-	CandyBody rune = '*'
+	FreeSpace    rune = ' '
+	SnakePart    rune = 'S'
+	CandyBody    rune = '*' // body of the regular candies
+	WallBody     rune = '#' // returned by MoveSnake when the snake hits a wall edge
+	ObstacleBody rune = 'X'
 )
 
 // Defines custom errors
 var (
 	ErrInvalidSnakeReference = errors.New("the snake object is nil")
 	ErrInvalidCandyReference = errors.New("the candy object is nil")
+	ErrNoCandy               = errors.New("there is no candy at this position")
 	ErrInvalidSize           = errors.New("invalid board size")
 	ErrInvalidPosition       = errors.New("invalid position")
+	ErrInvalidSnapshot       = errors.New("invalid board snapshot")
+	ErrCellNotFree           = errors.New("the cell is not free")
+	ErrNoObstacle            = errors.New("there is no obstacle at this position")
+	ErrUnknownSnake          = errors.New("there is no snake with this ID")
+	ErrSnakeExists           = errors.New("a snake with this ID is on the board")
+	ErrBoardFull             = errors.New("there is no free cell on the board")
+	ErrNotCloneable          = errors.New("the board can't be cloned")
+	errHitWall               = errors.New("the snake hit a wall")
 )
 
 // GameBoarder is the interface defining gameBoard exported methods
 type GameBoarder interface {
 	InitGameBoard(size common.Size) (err error)
 	BoardSize() common.Size
+	Cell(position common.Position) (value rune, err error)
+	NextPosition(position common.Position, direction common.Direction) (
+		next common.Position, nextDirection common.Direction, ok bool)
 	IsSnakePart(ch rune) bool
+	IsWall(ch rune) bool
+	IsObstacle(ch rune) bool
+	PlaceObstacle(position common.Position) (sprite common.Sprite, err error)
+	RemoveObstacle(position common.Position) (sprite common.Sprite, err error)
+	Obstacles() []common.Position
+	EdgePolicy() common.EdgePolicy
 	SetSnakeDirection(direction common.Direction)
 	SnakeSize() (size int, err error)
 	MoveSnake() (oldValue rune, listSprite []common.Sprite, err error)
@@ -39,26 +61,95 @@ type GameBoarder interface {
 	SnakeTail() (position common.Position, err error)
 	SnakeDirection() (direction common.Direction, err error)
 	IsCandy(ch rune) bool
-	CandyPosition() common.Position
-	CandyAlive() bool
-	RemoveCandy()
+	CandyPositions() []common.Position
+	CandyCount() int
+	RemoveCandyAt(position common.Position) (kind common.CandyKind, err error)
 	CreateCandy() (sprite common.Sprite, err error)
+	PlaceCandy(position common.Position, kind common.CandyKind) (sprite common.Sprite, err error)
+	ExpireCandies() (listSprite []common.Sprite, err error)
+	ShrinkSnake(id common.SnakeID, segments int) (listSprite []common.Sprite, err error)
+	AddSnake(id common.SnakeID, position common.Position,
+		direction common.Direction) (sprite common.Sprite, err error)
+	RemoveSnake(id common.SnakeID) (listSprite []common.Sprite, err error)
+	SnakeIDs() []common.SnakeID
+	SnakeState(id common.SnakeID) (aSnapshot snapshot.Snake, err error)
+	TurnSnake(id common.SnakeID, direction common.Direction) (err error)
+	MoveSnakes() (moves []common.SnakeMove, listSprite []common.Sprite, err error)
+	HeadOnRule() common.HeadOnRule
 	RandomFreePosition() (position common.Position, err error)
+	FreeCount() int
+	Snapshot() (aSnapshot snapshot.Board, err error)
+	Restore(aSnapshot snapshot.Board) (err error)
+	Deterministic() bool
 }
 
-// gameBoard defines the properties of a game board
+// gameBoard defines the properties of a game board.
+// movingSnake is the snake 0, snakes holds the other ones.
+// free indexes the free cells of board, setCell keeps it up to date
 type gameBoard struct {
 	size        common.Size
 	board       [][]rune
+	free        *freeCells
 	movingSnake snake.Snaker
-	candy       candy.Candyer
+	snakes      map[common.SnakeID]snake.Snaker
+	headOnRule  common.HeadOnRule
+	candies     []candy.Candyer
+	candyKinds  candy.Registry
+	randomizer  random.Randomer
+	edgePolicy  common.EdgePolicy
+}
+
+// Option configures a gameBoard created by New
+type Option func(aGameBoard *gameBoard)
+
+// WithRandom sets the random source used to place the candies.
+// A seeded source makes the candy sequence reproducible
+func WithRandom(randomizer random.Randomer) Option {
+	return func(aGameBoard *gameBoard) {
+		aGameBoard.randomizer = randomizer
+	}
+}
+
+// WithSeed gives each board created with the option its own random
+// source seeded by seed: they all place the same candies in the same order
+func WithSeed(seed int64) Option {
+	return func(aGameBoard *gameBoard) {
+		aGameBoard.randomizer = random.NewSeeded(seed)
+	}
+}
+
+// WithEdgePolicy sets what happens when the snake crosses an edge.
+// The zero policy wraps around every edge
+func WithEdgePolicy(edgePolicy common.EdgePolicy) Option {
+	return func(aGameBoard *gameBoard) {
+		aGameBoard.edgePolicy = edgePolicy
+	}
+}
+
+// WithCandyKinds sets the kinds of candy that can be spawned
+// and how they are drawn
+func WithCandyKinds(candyKinds candy.Registry) Option {
+	return func(aGameBoard *gameBoard) {
+		aGameBoard.candyKinds = candyKinds
+	}
+}
+
+// WithHeadOnRule sets what happens when snake heads move to the same cell
+func WithHeadOnRule(rule common.HeadOnRule) Option {
+	return func(aGameBoard *gameBoard) {
+		aGameBoard.headOnRule = rule
+	}
 }
 
 // New returns an instance of gameBoard
-func New() GameBoarder {
+func New(options ...Option) GameBoarder {
 	var aGameBoard gameBoard
 	aGameBoard.movingSnake = snake.New()
-	aGameBoard.candy = candy.New()
+	aGameBoard.randomizer = random.NewCrypto()
+	aGameBoard.candyKinds = candy.DefaultRegistry()
+	for _, option := range options {
+		option(&aGameBoard)
+	}
 	return &aGameBoard
 }
 
@@ -86,6 +177,7 @@ func (aGameBoard *gameBoard) createBoard(size common.Size) (err error) {
 	for i := range aGameBoard.board {
 		aGameBoard.board[i] = make([]rune, size.Height)
 	}
+	aGameBoard.free = nil
 	aGameBoard.size = size
 	return nil
 }
@@ -99,6 +191,7 @@ func (aGameBoard *gameBoard) clearBoard() (err error) {
 			aGameBoard.board[i][j] = FreeSpace
 		}
 	}
+	aGameBoard.free = nil
 
 	return nil
 }
@@ -112,7 +205,79 @@ func (aGameBoard *gameBoard) IsSnakePart(ch rune) bool {
 }
 
 func (aGameBoard *gameBoard) IsCandy(ch rune) bool {
-	return ch == CandyBody
+	if ch == CandyBody {
+		return true
+	}
+	_, ok := aGameBoard.kinds().KindOf(ch)
+	return ok && ch != FreeSpace && ch != SnakePart
+}
+
+func (aGameBoard *gameBoard) IsWall(ch rune) bool {
+	return ch == WallBody
+}
+
+func (aGameBoard *gameBoard) IsObstacle(ch rune) bool {
+	return ch == ObstacleBody
+}
+
+// PlaceObstacle puts an obstacle on a free cell
+func (aGameBoard *gameBoard) PlaceObstacle(position common.Position) (sprite common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	value, err := aGameBoard.cell(position)
+	if err != nil {
+		return sprite, err
+	}
+	if value != FreeSpace {
+		return sprite, ErrCellNotFree
+	}
+	if err = aGameBoard.setCell(position, ObstacleBody); err != nil {
+		return sprite, err
+	}
+
+	return common.Sprite{
+		Value:    ObstacleBody,
+		Position: position,
+	}, nil
+}
+
+// RemoveObstacle frees the cell of an obstacle
+func (aGameBoard *gameBoard) RemoveObstacle(position common.Position) (sprite common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	value, err := aGameBoard.cell(position)
+	if err != nil {
+		return sprite, err
+	}
+	if value != ObstacleBody {
+		return sprite, ErrNoObstacle
+	}
+	if err = aGameBoard.setCell(position, FreeSpace); err != nil {
+		return sprite, err
+	}
+
+	return common.Sprite{
+		Value:    FreeSpace,
+		Position: position,
+	}, nil
+}
+
+// Obstacles returns the positions of the obstacles, row by row
+func (aGameBoard *gameBoard) Obstacles() []common.Position {
+	var positions []common.Position
+	for y := 0; y < aGameBoard.size.Height; y++ {
+		for x := 0; x < aGameBoard.size.Width; x++ {
+			if aGameBoard.board[x][y] == ObstacleBody {
+				positions = append(positions, common.Position{X: x, Y: y})
+			}
+		}
+	}
+
+	return positions
+}
+
+func (aGameBoard *gameBoard) EdgePolicy() common.EdgePolicy {
+	return aGameBoard.edgePolicy
 }
 
 func (aGameBoard *gameBoard) CreateSnake(position common.Position,
@@ -155,16 +320,67 @@ func (aGameBoard *gameBoard) SnakeDirection() (direction common.Direction, err e
 	return aGameBoard.movingSnake.Direction()
 }
 
-func (aGameBoard *gameBoard) CandyPosition() common.Position {
-	return aGameBoard.candy.Position()
+// CandyPositions returns the positions of the candies on the board
+func (aGameBoard *gameBoard) CandyPositions() []common.Position {
+	positions := make([]common.Position, 0, len(aGameBoard.candies))
+	for _, aCandy := range aGameBoard.candies {
+		positions = append(positions, aCandy.Position())
+	}
+
+	return positions
 }
 
-func (aGameBoard *gameBoard) CandyAlive() bool {
-	return aGameBoard.candy.Alive()
+// CandyCount returns the number of candies on the board
+func (aGameBoard *gameBoard) CandyCount() int {
+	return len(aGameBoard.candies)
 }
 
-func (aGameBoard *gameBoard) RemoveCandy() {
-	aGameBoard.candy.Remove()
+// RemoveCandyAt removes the candy at position once it has been eaten
+// and returns its kind.
+// The cell is not changed since the snake head is already on it
+func (aGameBoard *gameBoard) RemoveCandyAt(position common.Position) (kind common.CandyKind, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	for i, aCandy := range aGameBoard.candies {
+		if aCandy == nil {
+			return kind, ErrInvalidCandyReference
+		}
+		if aCandy.Position() == position {
+			aCandy.Remove()
+			aGameBoard.candies = append(aGameBoard.candies[:i:i], aGameBoard.candies[i+1:]...)
+			return aCandy.Kind(), nil
+		}
+	}
+
+	return kind, ErrNoCandy
+}
+
+// ExpireCandies counts a round for every candy and
+// frees the cells of the candies whose lifetime is over
+func (aGameBoard *gameBoard) ExpireCandies() (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	candies := aGameBoard.candies[:0]
+	for _, aCandy := range aGameBoard.candies {
+		if aCandy == nil {
+			return listSprite, ErrInvalidCandyReference
+		}
+		if !aCandy.Tick() {
+			candies = append(candies, aCandy)
+			continue
+		}
+		aCandy.Remove()
+		if err = aGameBoard.setCell(aCandy.Position(), FreeSpace); err != nil {
+			return listSprite, err
+		}
+		listSprite = append(listSprite, common.Sprite{
+			Value:    FreeSpace,
+			Position: aCandy.Position(),
+		})
+	}
+	aGameBoard.candies = candies
+
+	return listSprite, nil
 }
 
 func (aGameBoard *gameBoard) CreateCandy() (sprite common.Sprite, err error) {
@@ -176,87 +392,673 @@ func (aGameBoard *gameBoard) CreateCandy() (sprite common.Sprite, err error) {
 		return sprite, err
 	}
 
+	// Picks its kind
+	kind, err := aGameBoard.randomKind()
+	if err != nil {
+		return sprite, err
+	}
+
+	return aGameBoard.PlaceCandy(position, kind)
+}
+
+// PlaceCandy puts a candy of the given kind on a free cell
+func (aGameBoard *gameBoard) PlaceCandy(position common.Position, kind common.CandyKind) (sprite common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	value, err := aGameBoard.cell(position)
+	if err != nil {
+		return sprite, err
+	}
+	if value != FreeSpace {
+		return sprite, ErrCellNotFree
+	}
+	properties := aGameBoard.kinds()[kind]
+	body := properties.Body
+	if body == 0 {
+		body = CandyBody
+	}
+
 	// Creates a candy
-	aGameBoard.candy = candy.New()
-	aGameBoard.candy.Init(position)
+	aCandy := candy.New()
+	aCandy.Init(position)
+	aCandy.SetKind(kind, properties.Lifetime)
+	aGameBoard.candies = append(aGameBoard.candies, aCandy)
 
 	// Sets the candy on the board
-	if err = aGameBoard.setCell(position, CandyBody); err != nil {
+	if err = aGameBoard.setCell(position, body); err != nil {
 		return sprite, err
 	}
 
 	return common.Sprite{
-		Value:    CandyBody,
+		Value:    body,
 		Position: position,
 	}, nil
 }
 
+// randomKind draws the kind of a new candy according to the weights.
+// Nothing is drawn when a single kind can be spawned
+func (aGameBoard *gameBoard) randomKind() (kind common.CandyKind, err error) {
+	candyKinds := aGameBoard.kinds()
+	totalWeight := 0
+	for _, aKind := range candyKinds.Kinds() {
+		if weight := candyKinds[aKind].Weight; weight > 0 {
+			totalWeight += weight
+			kind = aKind
+		}
+	}
+	if totalWeight == 0 {
+		return common.RegularCandy, nil
+	}
+	if totalWeight == candyKinds[kind].Weight {
+		return kind, nil
+	}
+
+	rnd, err := aGameBoard.random(totalWeight)
+	if err != nil {
+		return kind, err
+	}
+	for _, aKind := range candyKinds.Kinds() {
+		weight := candyKinds[aKind].Weight
+		if weight <= 0 {
+			continue
+		}
+		if rnd < weight {
+			return aKind, nil
+		}
+		rnd -= weight
+	}
+
+	return kind, nil // Shouldn't happen
+}
+
+// kinds returns the candy kinds, the default ones when none is set
+func (aGameBoard *gameBoard) kinds() candy.Registry {
+	if aGameBoard.candyKinds == nil {
+		aGameBoard.candyKinds = candy.DefaultRegistry()
+	}
+
+	return aGameBoard.candyKinds
+}
+
+// RandomFreePosition draws a free cell, ErrBoardFull is returned when there is none
 func (aGameBoard *gameBoard) RandomFreePosition() (position common.Position, err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	if aGameBoard.size.Width <= 0 || aGameBoard.size.Height <= 0 {
 		return position, ErrInvalidSize
 	}
-	// Randomly defines the candy position
-	maxW := aGameBoard.size.Width
-	maxH := aGameBoard.size.Height
-
-	rndX, err := random(maxW)
+	free := aGameBoard.freeCells()
+	if free.count() == 0 {
+		return position, ErrBoardFull
+	}
+	rnd, err := aGameBoard.random(free.count())
 	if err != nil {
 		return position, err
 	}
 
-	rndY, err := random(maxH)
+	return free.at(rnd), nil
+}
+
+// FreeCount returns the number of free cells
+func (aGameBoard *gameBoard) FreeCount() int {
+	if aGameBoard.size.Width <= 0 || aGameBoard.size.Height <= 0 {
+		return 0
+	}
+
+	return aGameBoard.freeCells().count()
+}
+
+// freeCells returns the index of the free cells, it is built on first use
+// after the board is created
+func (aGameBoard *gameBoard) freeCells() *freeCells {
+	if aGameBoard.free == nil {
+		aGameBoard.free = newFreeCells(aGameBoard.board)
+	}
+
+	return aGameBoard.free
+}
+
+// random draws a number in [0, max) from the board random source
+func (aGameBoard *gameBoard) random(max int) (rnd int, err error) {
+	if aGameBoard.randomizer == nil {
+		aGameBoard.randomizer = random.NewCrypto()
+	}
+
+	return aGameBoard.randomizer.Intn(max)
+}
+
+func (aGameBoard *gameBoard) Snapshot() (aSnapshot snapshot.Board, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aGameBoard.movingSnake == nil {
+		return aSnapshot, ErrInvalidSnakeReference
+	}
+
+	aSnapshot.Size = aGameBoard.size
+	aSnapshot.Cells = make([]string, aGameBoard.size.Height)
+	row := make([]rune, aGameBoard.size.Width)
+	for y := range aSnapshot.Cells {
+		for x := range row {
+			if row[x], err = aGameBoard.cell(common.Position{X: x, Y: y}); err != nil {
+				return aSnapshot, err
+			}
+		}
+		aSnapshot.Cells[y] = string(row)
+	}
+	aSnapshot.Snake = aGameBoard.movingSnake.Snapshot()
+	for _, id := range aGameBoard.SnakeIDs() {
+		if id == 0 {
+			continue
+		}
+		snakeSnapshot := aGameBoard.snakes[id].Snapshot()
+		snakeSnapshot.ID = id
+		aSnapshot.Snakes = append(aSnapshot.Snakes, snakeSnapshot)
+	}
+	for _, aCandy := range aGameBoard.candies {
+		if aCandy == nil {
+			return aSnapshot, ErrInvalidCandyReference
+		}
+		aSnapshot.Candies = append(aSnapshot.Candies, aCandy.Snapshot())
+	}
+	if aStater, ok := aGameBoard.randomizer.(random.Stater); ok {
+		state := aStater.State()
+		aSnapshot.RandomState = &state
+	}
+
+	return aSnapshot, nil
+}
+
+// Clone returns a copy of a board created by New, its snakes, its candies
+// and its random source: both boards play the same from then on and don't
+// affect each other. A random source which can't be copied is shared
+func Clone(aGameBoarder GameBoarder) (aClone GameBoarder, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aGameBoard, ok := aGameBoarder.(*gameBoard)
+	if !ok {
+		return nil, ErrNotCloneable
+	}
+
+	return aGameBoard.clone(), nil
+}
+
+func (aGameBoard *gameBoard) clone() *gameBoard {
+	aClone := *aGameBoard
+	aClone.board = make([][]rune, len(aGameBoard.board))
+	for x := range aGameBoard.board {
+		aClone.board[x] = append([]rune(nil), aGameBoard.board[x]...)
+	}
+	aClone.free = aGameBoard.free.clone()
+	if aGameBoard.movingSnake != nil {
+		aClone.movingSnake = aGameBoard.movingSnake.Clone()
+	}
+	if aGameBoard.snakes != nil {
+		aClone.snakes = make(map[common.SnakeID]snake.Snaker, len(aGameBoard.snakes))
+		for id, aSnake := range aGameBoard.snakes {
+			aClone.snakes[id] = aSnake.Clone()
+		}
+	}
+	aClone.candies = nil
+	for _, aCandy := range aGameBoard.candies {
+		if aCandy != nil {
+			aCandy = aCandy.Clone()
+		}
+		aClone.candies = append(aClone.candies, aCandy)
+	}
+	if aCloner, ok := aGameBoard.randomizer.(random.Cloner); ok {
+		aClone.randomizer = aCloner.Clone()
+	}
+
+	return &aClone
+}
+
+// Deterministic tells whether the board always plays the same
+// from the same state, that is its random source is seeded
+func (aGameBoard *gameBoard) Deterministic() bool {
+	_, ok := aGameBoard.randomizer.(random.Stater)
+	return ok
+}
+
+func (aGameBoard *gameBoard) Restore(aSnapshot snapshot.Board) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err = aGameBoard.checkSnapshot(aSnapshot); err != nil {
+		return err
+	}
+	if err = aGameBoard.createBoard(aSnapshot.Size); err != nil {
+		return err
+	}
+	for y, line := range aSnapshot.Cells {
+		for x, value := range []rune(line) {
+			aGameBoard.board[x][y] = value
+		}
+	}
+
+	aGameBoard.movingSnake = snake.New()
+	aGameBoard.movingSnake.Restore(aSnapshot.Snake)
+	aGameBoard.snakes = nil
+	for _, snakeSnapshot := range aSnapshot.Snakes {
+		aSnake := snake.New()
+		aSnake.Restore(snakeSnapshot)
+		if aGameBoard.snakes == nil {
+			aGameBoard.snakes = make(map[common.SnakeID]snake.Snaker)
+		}
+		aGameBoard.snakes[snakeSnapshot.ID] = aSnake
+	}
+	aGameBoard.candies = nil
+	for _, candySnapshot := range aSnapshot.Candies {
+		aCandy := candy.New()
+		aCandy.Restore(candySnapshot)
+		aGameBoard.candies = append(aGameBoard.candies, aCandy)
+	}
+
+	if aSnapshot.RandomState != nil {
+		aStater, ok := aGameBoard.randomizer.(random.Stater)
+		if !ok {
+			// The saved sequence can only be continued by a seeded source
+			aGameBoard.randomizer = random.NewSeeded(0)
+			aStater = aGameBoard.randomizer.(random.Stater)
+		}
+		aStater.SetState(*aSnapshot.RandomState)
+	}
+
+	return nil
+}
+
+// checkSnapshot checks the cells of aSnapshot fill its size, and its
+// snakes and candies are on the cells holding them. The snake 0 may have
+// no body, it has been removed from a game with several snakes
+func (aGameBoard *gameBoard) checkSnapshot(aSnapshot snapshot.Board) error {
+	if len(aSnapshot.Cells) != aSnapshot.Size.Height {
+		return ErrInvalidSnapshot
+	}
+	rows := make([][]rune, len(aSnapshot.Cells))
+	for y, line := range aSnapshot.Cells {
+		if rows[y] = []rune(line); len(rows[y]) != aSnapshot.Size.Width {
+			return ErrInvalidSnapshot
+		}
+	}
+	cell := func(position common.Position) (value rune, ok bool) {
+		if position.X < 0 || position.X >= aSnapshot.Size.Width || position.Y < 0 || position.Y >= aSnapshot.Size.Height {
+			return 0, false
+		}
+		return rows[position.Y][position.X], true
+	}
+
+	for i, snakeSnapshot := range append([]snapshot.Snake{aSnapshot.Snake}, aSnapshot.Snakes...) {
+		if i > 0 && snakeSnapshot.ID <= 0 {
+			return ErrInvalidSnapshot
+		}
+		if i > 0 && len(snakeSnapshot.Body) == 0 {
+			return fmt.Errorf("%w: the snake %d has no body", ErrInvalidSnapshot, snakeSnapshot.ID)
+		}
+		for _, position := range snakeSnapshot.Body {
+			if value, ok := cell(position); !ok || value != SnakePart {
+				return fmt.Errorf("%w: no part of the snake %d at %v", ErrInvalidSnapshot, snakeSnapshot.ID, position)
+			}
+		}
+	}
+	for _, candySnapshot := range aSnapshot.Candies {
+		value, ok := cell(candySnapshot.Position)
+		if !ok {
+			return fmt.Errorf("%w: a candy is out of the board at %v", ErrInvalidSnapshot, candySnapshot.Position)
+		}
+		if candySnapshot.Alive && !aGameBoard.IsCandy(value) {
+			return fmt.Errorf("%w: no candy at %v", ErrInvalidSnapshot, candySnapshot.Position)
+		}
+	}
+
+	return nil
+}
+
+func (aGameBoard *gameBoard) SetSnakeDirection(direction common.Direction) {
+	aGameBoard.movingSnake.SetDirection(direction)
+}
+
+// ShrinkSnake removes up to segments parts from the tail of the snake id
+// and frees their cells
+func (aGameBoard *gameBoard) ShrinkSnake(id common.SnakeID, segments int) (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSnake, err := aGameBoard.snakeOf(id)
 	if err != nil {
-		return position, err
+		return nil, err
 	}
+	removed, err := aSnake.Shrink(segments)
+	if err != nil {
+		return nil, err
+	}
+	for _, position := range removed {
+		if err = aGameBoard.setCell(position, FreeSpace); err != nil {
+			return listSprite, err
+		}
+		listSprite = append(listSprite, common.Sprite{
+			Value:    FreeSpace,
+			Position: position,
+		})
+	}
+
+	return listSprite, nil
+}
+
+// AddSnake puts the snake id on a free cell.
+// The ID 0 is the snake created by CreateSnake
+func (aGameBoard *gameBoard) AddSnake(id common.SnakeID, position common.Position,
+	direction common.Direction) (sprite common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	// loops for a free spot
-	val, err := aGameBoard.cell(common.Position{
-		X: rndX,
-		Y: rndY,
-	})
+	if id < 0 {
+		return sprite, ErrUnknownSnake
+	}
+	if aSnake, err := aGameBoard.snakeOf(id); err == nil {
+		if size, _ := aSnake.Size(); size > 0 {
+			return sprite, ErrSnakeExists
+		}
+	}
+	value, err := aGameBoard.cell(position)
 	if err != nil {
-		return position, err
+		return sprite, err
+	}
+	if value != FreeSpace {
+		return sprite, ErrCellNotFree
+	}
+	if id == 0 {
+		return aGameBoard.CreateSnake(position, direction)
 	}
 
-	for val != FreeSpace {
-		rndX, err = random(maxW)
-		if err != nil {
-			break
+	aSnake := snake.New()
+	aSnake.SetDirection(direction)
+	if err = aSnake.GrowTo(position); err != nil {
+		return sprite, err // Shouldn't happen
+	}
+	if err = aGameBoard.setCell(position, SnakePart); err != nil {
+		return sprite, err
+	}
+	if aGameBoard.snakes == nil {
+		aGameBoard.snakes = make(map[common.SnakeID]snake.Snaker)
+	}
+	aGameBoard.snakes[id] = aSnake
+
+	return common.Sprite{
+		Value:    SnakePart,
+		Position: position,
+	}, nil
+}
+
+// RemoveSnake takes the snake id off the board and frees its cells
+func (aGameBoard *gameBoard) RemoveSnake(id common.SnakeID) (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSnake, err := aGameBoard.snakeOf(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, position := range aSnake.Snapshot().Body {
+		if err = aGameBoard.setCell(position, FreeSpace); err != nil {
+			return listSprite, err
+		}
+		listSprite = append(listSprite, common.Sprite{
+			Value:    FreeSpace,
+			Position: position,
+		})
+	}
+	if id == 0 {
+		aGameBoard.movingSnake = snake.New()
+	} else {
+		delete(aGameBoard.snakes, id)
+	}
+
+	return listSprite, nil
+}
+
+// SnakeIDs returns the sorted IDs of the snakes on the board
+func (aGameBoard *gameBoard) SnakeIDs() []common.SnakeID {
+	var ids []common.SnakeID
+	if aGameBoard.movingSnake != nil {
+		if size, _ := aGameBoard.movingSnake.Size(); size > 0 {
+			ids = append(ids, 0)
+		}
+	}
+	others := make([]common.SnakeID, 0, len(aGameBoard.snakes))
+	for id := range aGameBoard.snakes {
+		others = append(others, id)
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+
+	return append(ids, others...)
+}
+
+// SnakeState returns a copy of the body and direction of the snake id
+func (aGameBoard *gameBoard) SnakeState(id common.SnakeID) (aSnapshot snapshot.Snake, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSnake, err := aGameBoard.snakeOf(id)
+	if err != nil {
+		return aSnapshot, err
+	}
+	aSnapshot = aSnake.Snapshot()
+	aSnapshot.ID = id
+
+	return aSnapshot, nil
+}
+
+// TurnSnake sets the direction of the snake id
+func (aGameBoard *gameBoard) TurnSnake(id common.SnakeID, direction common.Direction) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSnake, err := aGameBoard.snakeOf(id)
+	if err != nil {
+		return err
+	}
+	aSnake.SetDirection(direction)
+
+	return nil
+}
+
+func (aGameBoard *gameBoard) HeadOnRule() common.HeadOnRule {
+	return aGameBoard.headOnRule
+}
+
+// snakeOf returns the snake id
+func (aGameBoard *gameBoard) snakeOf(id common.SnakeID) (aSnake snake.Snaker, err error) {
+	if id == 0 {
+		if aGameBoard.movingSnake == nil {
+			return nil, ErrInvalidSnakeReference
 		}
+		return aGameBoard.movingSnake, nil
+	}
+	aSnake, ok := aGameBoard.snakes[id]
+	if !ok {
+		return nil, ErrUnknownSnake
+	}
+
+	return aSnake, nil
+}
 
-		rndY, err = random(maxH)
+// MoveSnakes moves every snake at once.
+// A snake dies when its head hits a wall edge, an obstacle, a snake body
+// or another head, the head-on rule telling which heads survive.
+// The dead snakes are removed from the board, the candies eaten by the
+// others are left to the caller
+func (aGameBoard *gameBoard) MoveSnakes() (moves []common.SnakeMove, listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	ids := aGameBoard.SnakeIDs()
+	moves = make([]common.SnakeMove, len(ids))
+	snakes := make([]snake.Snaker, len(ids))
+	directions := make([]common.Direction, len(ids))
+	growing := make(map[common.SnakeID]bool, len(ids))
+	for i, id := range ids {
+		moves[i].ID = id
+		if snakes[i], err = aGameBoard.snakeOf(id); err != nil {
+			return nil, nil, err
+		}
+		if moves[i].Length, err = snakes[i].Size(); err != nil {
+			return nil, nil, err
+		}
+		requestedPosition, err := snakes[i].NextMove()
+		if err != nil {
+			return nil, nil, err
+		}
+		direction, err := snakes[i].Direction()
+		if err != nil {
+			return nil, nil, err
+		}
+		position, actualDirection, err := aGameBoard.translatePosition(requestedPosition, direction)
+		if errors.Is(err, errHitWall) {
+			// The snake doesn't move
+			moves[i].Position = requestedPosition
+			moves[i].OldValue = WallBody
+			moves[i].Collision = common.WallCollision
+			continue
+		}
 		if err != nil {
-			break
+			return nil, nil, err
 		}
+		directions[i] = actualDirection
+		moves[i].Position = position
+		if moves[i].OldValue, err = aGameBoard.cell(position); err != nil {
+			return nil, nil, err
+		}
+		growing[id] = aGameBoard.IsCandy(moves[i].OldValue)
+	}
 
-		val, err = aGameBoard.cell(common.Position{
-			X: rndX,
-			Y: rndY,
+	// Checks what the heads run into
+	for i := range moves {
+		if moves[i].Collision != common.NotOver {
+			continue
+		}
+		if aGameBoard.IsObstacle(moves[i].OldValue) {
+			moves[i].Collision = common.ObstacleCollision
+		} else if owner, ok := bodyOwner(moves[i].Position, ids, snakes, growing); ok {
+			moves[i].Collision = common.SnakeCollision
+			if owner == moves[i].ID {
+				moves[i].Collision = common.SelfCollision
+			}
+		}
+	}
+	if err = aGameBoard.resolveHeadOn(moves, snakes); err != nil {
+		return nil, nil, err
+	}
+
+	// Removes the dead snakes, then frees the tails before drawing the heads
+	// so a head can follow another snake's tail
+	for i := range moves {
+		if moves[i].Collision == common.NotOver {
+			continue
+		}
+		removed, err := aGameBoard.RemoveSnake(moves[i].ID)
+		listSprite = append(listSprite, removed...)
+		if err != nil {
+			return nil, listSprite, err
+		}
+	}
+	var heads []common.Sprite
+	for i := range moves {
+		if moves[i].Collision != common.NotOver {
+			continue
+		}
+		snakes[i].SetDirection(directions[i])
+		heads = append(heads, common.Sprite{
+			Value:    SnakePart,
+			Position: moves[i].Position,
 		})
+		if growing[moves[i].ID] {
+			if err = snakes[i].GrowTo(moves[i].Position); err != nil {
+				return nil, listSprite, err
+			}
+			continue
+		}
+		oldTail, err := snakes[i].MoveTo(moves[i].Position)
 		if err != nil {
-			break
+			return nil, listSprite, err
+		}
+		if err = aGameBoard.setCell(oldTail, FreeSpace); err != nil {
+			return nil, listSprite, err
+		}
+		listSprite = append(listSprite, common.Sprite{
+			Value:    FreeSpace,
+			Position: oldTail,
+		})
+	}
+	for _, head := range heads {
+		if err = aGameBoard.setCell(head.Position, head.Value); err != nil {
+			return nil, listSprite, err
 		}
 	}
 
-	return common.Position{
-		X: rndX,
-		Y: rndY,
-	}, err
+	return moves, append(listSprite, heads...), nil
 }
 
-func random(max int) (rnd int, err error) {
-	aBig, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
-	if err == nil {
-		rnd = int(aBig.Int64())
+// bodyOwner returns the snake whose body is on position, ok is false when
+// a head can enter it. The tail of a snake that doesn't grow leaves its cell
+func bodyOwner(position common.Position, ids []common.SnakeID, snakes []snake.Snaker,
+	growing map[common.SnakeID]bool) (owner common.SnakeID, ok bool) {
+	for i, id := range ids {
+		if !snakes[i].Occupies(position) {
+			continue
+		}
+		if tail, err := snakes[i].Tail(); err == nil && tail == position && !growing[id] {
+			continue
+		}
+		return id, true
 	}
 
-	return rnd, err
+	return owner, false
 }
 
-func (aGameBoard *gameBoard) SetSnakeDirection(direction common.Direction) {
-	aGameBoard.movingSnake.SetDirection(direction)
+// resolveHeadOn applies the head-on rule to the live heads entering the
+// same cell or swapping their cells
+func (aGameBoard *gameBoard) resolveHeadOn(moves []common.SnakeMove, snakes []snake.Snaker) (err error) {
+	heads := make(map[common.Position][]int)
+	current := make(map[common.Position]int)
+	for i := range moves {
+		if moves[i].Collision == common.NotOver {
+			heads[moves[i].Position] = append(heads[moves[i].Position], i)
+			position, err := snakes[i].Position()
+			if err != nil {
+				return err
+			}
+			current[position] = i
+		}
+	}
+	var groups [][]int
+	for _, group := range heads {
+		groups = append(groups, group)
+	}
+	for i := range moves {
+		if j, ok := current[moves[i].Position]; ok && i < j && moves[i].Collision == common.NotOver {
+			if position, _ := snakes[i].Position(); moves[j].Position == position {
+				groups = append(groups, []int{i, j})
+			}
+		}
+	}
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		longest, winners := 0, 0
+		sizes := make([]int, len(group))
+		for j, i := range group {
+			if sizes[j], err = snakes[i].Size(); err != nil {
+				return err
+			}
+			if sizes[j] > longest {
+				longest, winners = sizes[j], 0
+			}
+			if sizes[j] == longest {
+				winners++
+			}
+		}
+		for j, i := range group {
+			if aGameBoard.headOnRule == common.HeadOnLongerWins && sizes[j] == longest && winners == 1 {
+				continue
+			}
+			moves[i].Collision = common.HeadOnCollision
+		}
+	}
+
+	return nil
 }
 
 func (aGameBoard *gameBoard) SnakeSize() (size int, err error) {
@@ -278,11 +1080,24 @@ func (aGameBoard *gameBoard) MoveSnake() (oldValue rune, listSprite []common.Spr
 		return oldValue, listSprite, err
 	}
 
+	direction, err := aGameBoard.movingSnake.Direction()
+	if err != nil {
+		return oldValue, listSprite, err
+	}
+
 	// Translates the requested position inside the board
-	actualPosition, err := aGameBoard.translatePosition(requestedPosition)
+	actualPosition, actualDirection, err := aGameBoard.translatePosition(requestedPosition, direction)
+	if errors.Is(err, errHitWall) {
+		// The snake doesn't move
+		return WallBody, listSprite, nil
+	}
 	if err != nil {
 		return oldValue, listSprite, err
 	}
+	if actualDirection != direction {
+		// The snake bounced
+		aGameBoard.movingSnake.SetDirection(actualDirection)
+	}
 
 	// Gets the content at the actual position
 	oldValue, err = aGameBoard.getOldValue(actualPosition)
@@ -322,7 +1137,7 @@ func (aGameBoard *gameBoard) actualMove(position common.Position, oldValue rune)
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
 	// has the snake eaten a candy?
-	if oldValue == CandyBody {
+	if aGameBoard.IsCandy(oldValue) {
 		// Grow the snake
 		err = aGameBoard.movingSnake.GrowTo(position)
 		if err != nil {
@@ -345,14 +1160,14 @@ func (aGameBoard *gameBoard) actualMove(position common.Position, oldValue rune)
 		return nil, err
 	}
 
-	// update the board with the new head
-	err = aGameBoard.setCell(position, SnakePart)
+	// Remove the tail first, the head may take its cell
+	err = aGameBoard.setCell(oldTail, FreeSpace)
 	if err != nil {
 		return nil, err
 	}
 
-	// Remove the tail
-	err = aGameBoard.setCell(oldTail, FreeSpace)
+	// update the board with the new head
+	err = aGameBoard.setCell(position, SnakePart)
 	// The tail and the head have been updated
 	return []common.Sprite{
 		{
@@ -366,6 +1181,27 @@ func (aGameBoard *gameBoard) actualMove(position common.Position, oldValue rune)
 	}, err
 }
 
+// Cell returns the content of the cell at position
+func (aGameBoard *gameBoard) Cell(position common.Position) (value rune, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	return aGameBoard.cell(position)
+}
+
+// NextPosition returns where a head at position going to direction lands
+// according to the edge policy, and its direction once there.
+// ok is false when it hits a wall edge
+func (aGameBoard *gameBoard) NextPosition(position common.Position, direction common.Direction) (
+	next common.Position, nextDirection common.Direction, ok bool) {
+	requestedPosition := common.Position{
+		X: position.X + direction.DX,
+		Y: position.Y + direction.DY,
+	}
+	next, nextDirection, err := aGameBoard.translatePosition(requestedPosition, direction)
+
+	return next, nextDirection, err == nil
+}
+
 func (aGameBoard *gameBoard) cell(position common.Position) (value rune, err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
@@ -392,6 +1228,9 @@ func (aGameBoard *gameBoard) setCell(position common.Position, value rune) (err
 	}
 
 	aGameBoard.board[position.X][position.Y] = value
+	if aGameBoard.free != nil {
+		aGameBoard.free.set(position, value)
+	}
 	return nil
 }
 
@@ -406,30 +1245,82 @@ func (aGameBoard *gameBoard) checkPosition(position common.Position) bool {
 	return true
 }
 
-func (aGameBoard *gameBoard) translatePosition(requestedPosition common.Position) (translatedPostion common.Position, err error) {
+func (aGameBoard *gameBoard) translatePosition(requestedPosition common.Position, direction common.Direction) (
+	translatedPosition common.Position, translatedDirection common.Direction, err error) {
 	// The position is kept inside the board
-	// If it gets out one side, it enters the other side
+	// What happens at an edge depends on the edge policy:
+	// it may enter the other side, hit a wall or bounce.
+	// A bounce turns the snake along the edge, toward the center of the
+	// board: going back would run into its own neck
 
 	if aGameBoard.size.Width <= 0 || aGameBoard.size.Height <= 0 {
-		return translatedPostion, ErrInvalidSize
+		return translatedPosition, direction, ErrInvalidSize
 	}
 
-	translatedPosition := requestedPosition
+	translatedPosition = requestedPosition
+	translatedDirection = direction
 	maxW := aGameBoard.size.Width - 1
 	maxH := aGameBoard.size.Height - 1
 
+	var bouncedX, bouncedY bool
 	if translatedPosition.X < 0 {
-		translatedPosition.X = maxW
+		bouncedX, err = crossEdge(aGameBoard.edgePolicy.Left, &translatedPosition.X, &translatedDirection.DX, maxW)
+	} else if translatedPosition.X > maxW {
+		bouncedX, err = crossEdge(aGameBoard.edgePolicy.Right, &translatedPosition.X, &translatedDirection.DX, 0)
+	}
+	if err == nil && bouncedX {
+		err = turn(&translatedPosition.Y, &translatedDirection.DY, maxH)
 	}
-	if translatedPosition.X > maxW {
-		translatedPosition.X = 0
+	if err != nil {
+		return requestedPosition, direction, err
 	}
+
 	if translatedPosition.Y < 0 {
-		translatedPosition.Y = maxH
+		bouncedY, err = crossEdge(aGameBoard.edgePolicy.Top, &translatedPosition.Y, &translatedDirection.DY, maxH)
+	} else if translatedPosition.Y > maxH {
+		bouncedY, err = crossEdge(aGameBoard.edgePolicy.Bottom, &translatedPosition.Y, &translatedDirection.DY, 0)
+	}
+	if err == nil && bouncedY {
+		err = turn(&translatedPosition.X, &translatedDirection.DX, maxW)
 	}
-	if translatedPosition.Y > maxH {
-		translatedPosition.Y = 0
+	if err != nil {
+		return requestedPosition, direction, err
+	}
+
+	return translatedPosition, translatedDirection, nil
+}
+
+// crossEdge updates a coordinate that went past an edge.
+// opposite is the coordinate of the other side used to wrap.
+// On a bounce the coordinate stays on the edge and bounced is true,
+// the other coordinate has to turn
+func crossEdge(edge common.Edge, coordinate *int, delta *int, opposite int) (bounced bool, err error) {
+	switch edge {
+	case common.WallEdge:
+		return false, errHitWall
+	case common.BounceEdge:
+		*coordinate -= *delta
+		*delta = 0
+		return true, nil
+	default:
+		*coordinate = opposite
 	}
 
-	return translatedPosition, nil
+	return false, nil
+}
+
+// turn moves a coordinate one step toward the center of its axis,
+// max is the last coordinate. A board of one row or column has no room
+// to turn, the bounce is a wall
+func turn(coordinate *int, delta *int, max int) error {
+	if max == 0 {
+		return errHitWall
+	}
+	*delta = -1
+	if 2**coordinate < max {
+		*delta = 1
+	}
+	*coordinate += *delta
+
+	return nil
 }
@@ -6,7 +6,9 @@ import (
 	"github.com/Amari-Mecheri/GoSnakeLogic/mocks"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/random"
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snake"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
 
 	"github.com/stretchr/testify/require"
@@ -17,7 +19,7 @@ func TestGameBoard_createBoard(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		size common.Size
@@ -81,7 +83,7 @@ func TestGameBoard_createBoard(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			err := aGameBoard.createBoard(tt.args.size)
 			gotErr := (err != nil)
@@ -95,7 +97,7 @@ func TestGameBoard_clearBoard(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name    string
@@ -141,7 +143,7 @@ func TestGameBoard_clearBoard(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			err := aGameBoard.clearBoard()
 			gotErr := (err != nil)
@@ -155,7 +157,7 @@ func TestGameBoard_InitGameBoard(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		size common.Size
@@ -168,7 +170,7 @@ func TestGameBoard_InitGameBoard(t *testing.T) {
 		wantErrType error
 	}{
 		{
-			name: "TestNegativeSize",
+			name: "TestEmptyRows",
 			args: args{
 				size: testdata.SizeMinus1_Minus1,
 			},
@@ -193,7 +195,7 @@ func TestGameBoard_InitGameBoard(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			err := aGameBoard.InitGameBoard(tt.args.size)
 			gotErr := (err != nil)
@@ -210,7 +212,7 @@ func TestGameBoard_BoardSize(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name     string
@@ -248,7 +250,7 @@ func TestGameBoard_BoardSize(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			gotSize := aGameBoard.BoardSize()
 			require.Equal(t, tt.wantSize, gotSize)
@@ -261,7 +263,7 @@ func TestGameBoard_IsSnakePart(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		ch rune
@@ -301,7 +303,7 @@ func TestGameBoard_IsSnakePart(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			got := aGameBoard.IsSnakePart(tt.args.ch)
 			require.Equal(t, tt.want, got)
@@ -314,7 +316,7 @@ func TestGameBoard_IsCandy(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		ch rune
@@ -347,6 +349,28 @@ func TestGameBoard_IsCandy(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "GoldenCandyBody",
+			fields: fields{
+				size:  testdata.Size3_3,
+				board: testdata.Duplicate(testdata.Board3_3),
+			},
+			args: args{
+				ch: candy.DefaultRegistry()[common.GoldenCandy].Body,
+			},
+			want: true,
+		},
+		{
+			name: "FreeSpace",
+			fields: fields{
+				size:  testdata.Size3_3,
+				board: testdata.Duplicate(testdata.Board3_3),
+			},
+			args: args{
+				ch: FreeSpace,
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -354,7 +378,7 @@ func TestGameBoard_IsCandy(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			got := aGameBoard.IsCandy(tt.args.ch)
 			require.Equal(t, tt.want, got)
@@ -367,7 +391,7 @@ func TestGameBoard_CreateSnake(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		position  common.Position
@@ -451,7 +475,7 @@ func TestGameBoard_CreateSnake(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			gotSprite, err := aGameBoard.CreateSnake(tt.args.position, tt.args.direction)
 			gotErr := (err != nil)
@@ -471,7 +495,7 @@ func TestGameBoard_SnakePosition(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name         string
@@ -494,7 +518,7 @@ func TestGameBoard_SnakePosition(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			aSnake := &mocks.Snaker{}
 			aSnake.On("Position").Return(tt.mockPosition, nil)
@@ -510,25 +534,33 @@ func TestGameBoard_SnakePosition(t *testing.T) {
 	}
 }
 
-func TestGameBoard_CandyPosition(t *testing.T) {
-	// At this point this method just returns candy.Position
-	// In this test the returned value should be the mocked value
+func TestGameBoard_CandyPositions(t *testing.T) {
+	// This method returns the position of every candy
+	// In this test the returned values should be the mocked values
 	type fields struct {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
 	}
 	tests := []struct {
-		name         string
-		fields       fields
-		mockPosition common.Position
-		wantPosition common.Position
+		name          string
+		fields        fields
+		mockPositions []common.Position
+		wantPositions []common.Position
 	}{
 		{
-			name:         "TestEmptyBoardPos4,3",
-			mockPosition: testdata.Position4_3,
-			wantPosition: testdata.Position4_3,
+			name:          "TestNoCandy",
+			wantPositions: []common.Position{},
+		},
+		{
+			name:          "TestEmptyBoardPos4,3",
+			mockPositions: []common.Position{testdata.Position4_3},
+			wantPositions: []common.Position{testdata.Position4_3},
+		},
+		{
+			name:          "TestEmptyBoardPos4,3And1,1",
+			mockPositions: []common.Position{testdata.Position4_3, testdata.Position1_1},
+			wantPositions: []common.Position{testdata.Position4_3, testdata.Position1_1},
 		},
 	}
 	for _, tt := range tests {
@@ -537,57 +569,79 @@ func TestGameBoard_CandyPosition(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
 			}
-			aCandy := &mocks.Candyer{}
-			aCandy.On("Position").Return(tt.mockPosition)
-			aGameBoard.candy = aCandy
-			gotPosition := aGameBoard.CandyPosition()
-			require.Equal(t, tt.wantPosition, gotPosition)
-
+			for _, position := range tt.mockPositions {
+				aCandy := &mocks.Candyer{}
+				aCandy.On("Position").Return(position)
+				aGameBoard.candies = append(aGameBoard.candies, aCandy)
+			}
+			gotPositions := aGameBoard.CandyPositions()
+			require.Equal(t, tt.wantPositions, gotPositions)
+			require.Equal(t, len(tt.wantPositions), aGameBoard.CandyCount())
 		})
 	}
 }
 
-func TestGameBoard_CandyAlive(t *testing.T) {
-	// At this point this method just returns candy.CandyAlive
-	// In this test the returned value should be the mocked value
-	type fields struct {
-		size        common.Size
-		board       [][]rune
-		movingSnake snake.Snaker
-		candy       candy.Candyer
+func TestGameBoard_RemoveCandyAt(t *testing.T) {
+	type args struct {
+		position common.Position
 	}
 	tests := []struct {
-		name       string
-		fields     fields
-		mockStatus bool
-		wantStatus bool
+		name          string
+		mockPositions []common.Position
+		args          args
+		wantPositions []common.Position
+		wantErrType   error
 	}{
 		{
-			name:       "TestEmptyBoardAlive",
-			mockStatus: true,
-			wantStatus: true,
+			name:          "TestNoCandy",
+			args:          args{position: testdata.Position1_1},
+			wantPositions: []common.Position{},
+			wantErrType:   ErrNoCandy,
+		},
+		{
+			name:          "TestNoCandyAtPosition",
+			mockPositions: []common.Position{testdata.Position4_3},
+			args:          args{position: testdata.Position1_1},
+			wantPositions: []common.Position{testdata.Position4_3},
+			wantErrType:   ErrNoCandy,
+		},
+		{
+			name:          "TestRemoveFirst",
+			mockPositions: []common.Position{testdata.Position1_1, testdata.Position4_3},
+			args:          args{position: testdata.Position1_1},
+			wantPositions: []common.Position{testdata.Position4_3},
 		},
 		{
-			name:       "TestEmptyBoardAlive",
-			mockStatus: false,
-			wantStatus: false,
+			name:          "TestRemoveLast",
+			mockPositions: []common.Position{testdata.Position4_3, testdata.Position1_1},
+			args:          args{position: testdata.Position1_1},
+			wantPositions: []common.Position{testdata.Position4_3},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aGameBoard := &gameBoard{
-				size:        tt.fields.size,
-				board:       tt.fields.board,
-				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+			aGameBoard := &gameBoard{}
+			var removed *mocks.Candyer
+			for _, position := range tt.mockPositions {
+				aCandy := &mocks.Candyer{}
+				aCandy.On("Position").Return(position)
+				if position == tt.args.position {
+					aCandy.On("Remove").Return()
+					aCandy.On("Kind").Return(common.GoldenCandy)
+					removed = aCandy
+				}
+				aGameBoard.candies = append(aGameBoard.candies, aCandy)
+			}
+			gotKind, err := aGameBoard.RemoveCandyAt(tt.args.position)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, common.GoldenCandy, gotKind)
+				removed.AssertCalled(t, "Remove")
 			}
-			aCandy := &mocks.Candyer{}
-			aCandy.On("Alive").Return(tt.mockStatus)
-			aGameBoard.candy = aCandy
-			gotStatus := aGameBoard.CandyAlive()
-			require.Equal(t, tt.wantStatus, gotStatus)
+			require.Equal(t, tt.wantPositions, aGameBoard.CandyPositions())
 		})
 	}
 }
@@ -597,7 +651,7 @@ func TestGameBoard_CreateCandy(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name        string
@@ -622,7 +676,6 @@ func TestGameBoard_CreateCandy(t *testing.T) {
 			fields: fields{
 				size:  testdata.Size3_3,
 				board: testdata.Duplicate(testdata.Board3_3_OneFreeSpotPos1_1),
-				candy: candy.New(),
 			},
 			wantSprite: common.Sprite{
 				Value:    CandyBody,
@@ -637,7 +690,7 @@ func TestGameBoard_CreateCandy(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			gotSprite, err := aGameBoard.CreateCandy()
 			gotErr := (err != nil)
@@ -650,13 +703,132 @@ func TestGameBoard_CreateCandy(t *testing.T) {
 	}
 }
 
+func TestGameBoard_CreateCandyKinds(t *testing.T) {
+	// Golden candies can be spawned along with the regular ones
+	candyKinds := candy.DefaultRegistry()
+	regular := candyKinds[common.RegularCandy]
+	golden := candyKinds[common.GoldenCandy]
+	golden.Weight = 3
+	candyKinds[common.GoldenCandy] = golden
+
+	aGameBoard := New(WithRandom(random.NewSeeded(1)), WithCandyKinds(candyKinds))
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size4_4))
+	counts := map[rune]int{}
+	for i := 0; i < 8; i++ {
+		sprite, err := aGameBoard.CreateCandy()
+		require.NoError(t, err)
+		counts[sprite.Value]++
+		cell, err := aGameBoard.(*gameBoard).cell(sprite.Position)
+		require.NoError(t, err)
+		require.Equal(t, sprite.Value, cell)
+		require.True(t, aGameBoard.IsCandy(sprite.Value))
+	}
+	require.Equal(t, 8, counts[regular.Body]+counts[golden.Body])
+	require.NotZero(t, counts[regular.Body])
+	require.NotZero(t, counts[golden.Body])
+	for _, aCandy := range aGameBoard.(*gameBoard).candies {
+		require.Equal(t, candyKinds[aCandy.Kind()].Lifetime, aCandy.Lifetime())
+	}
+}
+
+func TestGameBoard_ExpireCandies(t *testing.T) {
+	tests := []struct {
+		name           string
+		lifetimes      []int
+		wantListSprite []common.Sprite
+		wantLifetimes  []int
+	}{
+		{
+			name:          "TestForever",
+			lifetimes:     []int{0, 0},
+			wantLifetimes: []int{0, 0},
+		},
+		{
+			name:          "TestCountdown",
+			lifetimes:     []int{2, 3},
+			wantLifetimes: []int{1, 2},
+		},
+		{
+			name:      "TestOneExpired",
+			lifetimes: []int{1, 0},
+			wantListSprite: []common.Sprite{
+				{
+					Value:    FreeSpace,
+					Position: testdata.Position0_0,
+				},
+			},
+			wantLifetimes: []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameBoard := &gameBoard{}
+			require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+			for i, lifetime := range tt.lifetimes {
+				position := common.Position{X: i, Y: 0}
+				aCandy := candy.New()
+				aCandy.Init(position)
+				aCandy.SetKind(common.GoldenCandy, lifetime)
+				aGameBoard.candies = append(aGameBoard.candies, aCandy)
+				require.NoError(t, aGameBoard.setCell(position, '$'))
+			}
+			gotListSprite, err := aGameBoard.ExpireCandies()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantListSprite, gotListSprite)
+			var gotLifetimes []int
+			for _, aCandy := range aGameBoard.candies {
+				gotLifetimes = append(gotLifetimes, aCandy.Lifetime())
+			}
+			require.Equal(t, tt.wantLifetimes, gotLifetimes)
+			for _, sprite := range gotListSprite {
+				cell, err := aGameBoard.cell(sprite.Position)
+				require.NoError(t, err)
+				require.Equal(t, FreeSpace, cell)
+			}
+		})
+	}
+}
+
+func TestGameBoard_ShrinkSnake(t *testing.T) {
+	aGameBoard := &gameBoard{}
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+	_, err := aGameBoard.ShrinkSnake(0, 1)
+	require.ErrorIs(t, err, ErrInvalidSnakeReference)
+
+	_, err = aGameBoard.CreateSnake(testdata.Position0_0, testdata.Direction1_0)
+	require.NoError(t, err)
+	for _, position := range []common.Position{{X: 1, Y: 0}, {X: 2, Y: 0}} {
+		require.NoError(t, aGameBoard.movingSnake.GrowTo(position))
+		require.NoError(t, aGameBoard.setCell(position, SnakePart))
+	}
+
+	gotListSprite, err := aGameBoard.ShrinkSnake(0, 5) // The head is kept
+	require.NoError(t, err)
+	require.Equal(t, []common.Sprite{
+		{
+			Value:    FreeSpace,
+			Position: testdata.Position0_0,
+		},
+		{
+			Value:    FreeSpace,
+			Position: common.Position{X: 1, Y: 0},
+		},
+	}, gotListSprite)
+	size, err := aGameBoard.SnakeSize()
+	require.NoError(t, err)
+	require.Equal(t, 1, size)
+	cell, err := aGameBoard.cell(testdata.Position0_0)
+	require.NoError(t, err)
+	require.Equal(t, FreeSpace, cell)
+}
+
 func TestGameBoard_RandomFreePosition(t *testing.T) {
 	// To test we just a provide a board with 1 free spot
 	type fields struct {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name         string
@@ -673,6 +845,14 @@ func TestGameBoard_RandomFreePosition(t *testing.T) {
 			wantPosition: testdata.Position1_1,
 			wantErr:      false,
 		},
+		{
+			name: "TestBoard3_3_Full",
+			fields: fields{
+				size:  testdata.Size3_3,
+				board: testdata.Duplicate(testdata.Board3_3),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -680,7 +860,7 @@ func TestGameBoard_RandomFreePosition(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			gotPosition, err := aGameBoard.RandomFreePosition()
 			gotErr := (err != nil)
@@ -690,12 +870,56 @@ func TestGameBoard_RandomFreePosition(t *testing.T) {
 	}
 }
 
+func TestGameBoard_RandomFreePositionSeeded(t *testing.T) {
+	// Two boards sharing a seed place their candies at the same positions
+	tests := []struct {
+		name      string
+		seed1     int64
+		seed2     int64
+		wantEqual bool
+	}{
+		{
+			name:      "TestSameSeed",
+			seed1:     3,
+			seed2:     3,
+			wantEqual: true,
+		},
+		{
+			name:      "TestOtherSeed",
+			seed1:     3,
+			seed2:     4,
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameBoard1 := New(WithRandom(random.NewSeeded(tt.seed1)))
+			aGameBoard2 := New(WithRandom(random.NewSeeded(tt.seed2)))
+			size := common.Size{
+				Width:  20,
+				Height: 20,
+			}
+			require.NoError(t, aGameBoard1.InitGameBoard(size))
+			require.NoError(t, aGameBoard2.InitGameBoard(size))
+			gotEqual := true
+			for i := 0; i < 20; i++ {
+				position1, err := aGameBoard1.RandomFreePosition()
+				require.NoError(t, err)
+				position2, err := aGameBoard2.RandomFreePosition()
+				require.NoError(t, err)
+				gotEqual = gotEqual && position1 == position2
+			}
+			require.Equal(t, tt.wantEqual, gotEqual)
+		})
+	}
+}
+
 func TestGameBoard_SnakeSize(t *testing.T) {
 	type fields struct {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name         string
@@ -739,7 +963,7 @@ func TestGameBoard_SnakeSize(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			if tt.wantMockSize {
 				// we shall mock a snake
@@ -763,11 +987,12 @@ func TestGameBoard_MoveSnake(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	tests := []struct {
 		name           string
 		fields         fields
+		edgePolicy     common.EdgePolicy
 		mockNextMove   common.Position
 		mockOldTail    common.Position
 		wantOldValue   rune
@@ -802,18 +1027,30 @@ func TestGameBoard_MoveSnake(t *testing.T) {
 			mockNextMove: testdata.Position1_1,
 			mockOldTail:  testdata.Position0_0,
 			wantOldValue: testdata.Duplicate(testdata.Board3_3)[1][1],
-			wantListSprite: []common.Sprite{
-				{
-					Value:    SnakePart,
-					Position: testdata.Position1_1,
-				},
+			wantListSprite: []common.Sprite{ // The tail is freed before the head is drawn
 				{
 					Value:    FreeSpace,
 					Position: testdata.Position0_0,
 				},
+				{
+					Value:    SnakePart,
+					Position: testdata.Position1_1,
+				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "TestSnakeHitsWall", // The snake moves out of the board and doesn't move
+			fields: fields{
+				size:  testdata.Size3_3,
+				board: testdata.Duplicate(testdata.Board3_3),
+			},
+			edgePolicy:   common.UniformEdges(common.WallEdge),
+			mockNextMove: testdata.Position0_Minus1,
+			mockOldTail:  testdata.Position0_0,
+			wantOldValue: WallBody,
+			wantErr:      false,
+		},
 		{
 			name: "TestSnakePos0,0GrowTo1,1", // The snake eats a candy and grow from 0,0 to 1,1
 			fields: fields{
@@ -838,12 +1075,14 @@ func TestGameBoard_MoveSnake(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
+				edgePolicy:  tt.edgePolicy,
 			}
 			if aGameBoard.movingSnake == nil {
 				// we shall mock a snake
 				aSnake := &mocks.Snaker{}
 				aSnake.On("NextMove").Return(tt.mockNextMove, nil)
+				aSnake.On("Direction").Return(testdata.Direction1_0, nil)
 				aSnake.On("GrowTo", tt.mockNextMove).Return(nil)
 				aSnake.On("Tail").Return(tt.mockOldTail, nil)
 				aSnake.On("MoveTo", tt.mockNextMove).Return(tt.mockOldTail, nil)
@@ -866,7 +1105,7 @@ func TestGameBoard_actualMove(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		position common.Position
@@ -918,15 +1157,15 @@ func TestGameBoard_actualMove(t *testing.T) {
 			},
 			mockNextMove: testdata.Position1_1,
 			mockOldTail:  testdata.Position0_0,
-			wantListSprite: []common.Sprite{
-				{
-					Value:    SnakePart,
-					Position: testdata.Position1_1,
-				},
+			wantListSprite: []common.Sprite{ // The tail is freed before the head is drawn
 				{
 					Value:    FreeSpace,
 					Position: testdata.Position0_0,
 				},
+				{
+					Value:    SnakePart,
+					Position: testdata.Position1_1,
+				},
 			},
 			wantErr: false,
 		},
@@ -957,12 +1196,13 @@ func TestGameBoard_actualMove(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			if aGameBoard.movingSnake == nil {
 				// we shall mock a snake
 				aSnake := &mocks.Snaker{}
 				aSnake.On("NextMove").Return(tt.mockNextMove, nil)
+				aSnake.On("Direction").Return(testdata.Direction1_0, nil)
 				aSnake.On("GrowTo", tt.mockNextMove).Return(nil)
 				aSnake.On("MoveTo", tt.mockNextMove).Return(tt.mockOldTail, nil)
 				aGameBoard.movingSnake = aSnake
@@ -979,12 +1219,35 @@ func TestGameBoard_actualMove(t *testing.T) {
 	}
 }
 
+func TestGameBoard_MoveSnakeToItsTail(t *testing.T) {
+	// The snake fills a 2*2 board and its head moves to the cell left by its tail
+	aSnake := snake.New()
+	aGameBoard := &gameBoard{
+		size:        common.Size{Width: 2, Height: 2},
+		board:       [][]rune{{SnakePart, SnakePart}, {SnakePart, SnakePart}},
+		movingSnake: aSnake,
+	}
+	for _, position := range []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}} {
+		require.NoError(t, aSnake.GrowTo(position))
+	}
+	// The head goes around the board, each move takes the cell of the tail
+	for _, direction := range []common.Direction{common.Up, common.Right, common.Down, common.Left} {
+		aSnake.SetDirection(direction)
+		oldValue, _, err := aGameBoard.MoveSnake()
+		require.NoError(t, err)
+		require.Equal(t, FreeSpace, oldValue)
+		// The head keeps its cell, the board stays full
+		require.Equal(t, [][]rune{{SnakePart, SnakePart}, {SnakePart, SnakePart}}, aGameBoard.board)
+		require.Zero(t, aGameBoard.freeCells().count())
+	}
+}
+
 func TestGameBoard_cell(t *testing.T) {
 	type fields struct {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		position common.Position
@@ -1037,7 +1300,7 @@ func TestGameBoard_cell(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			gotValue, err := aGameBoard.cell(tt.args.position)
 
@@ -1056,7 +1319,7 @@ func TestGameBoard_setCell(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		position common.Position
@@ -1109,7 +1372,7 @@ func TestGameBoard_setCell(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			err := aGameBoard.setCell(tt.args.position, tt.args.value)
 			gotErr := (err != nil)
@@ -1126,17 +1389,20 @@ func TestGameBoard_translatePosition(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
+		edgePolicy  common.EdgePolicy
 	}
 	type args struct {
 		requestedPosition common.Position
+		direction         common.Direction
 	}
 	tests := []struct {
-		name         string
-		fields       fields
-		args         args
-		wantPosition common.Position
-		wantErr      error
+		name          string
+		fields        fields
+		args          args
+		wantPosition  common.Position
+		wantDirection common.Direction
+		wantErr       error
 	}{
 		{
 			name: "TestEmptyBoard", // The size of the board is 0,0
@@ -1153,8 +1419,10 @@ func TestGameBoard_translatePosition(t *testing.T) {
 			},
 			args: args{
 				requestedPosition: testdata.Position1_1,
+				direction:         testdata.Direction1_0,
 			},
-			wantPosition: testdata.Position1_1,
+			wantPosition:  testdata.Position1_1,
+			wantDirection: testdata.Direction1_0,
 		},
 		{
 			name: "TestSize4_4Pos4,3",
@@ -1163,8 +1431,10 @@ func TestGameBoard_translatePosition(t *testing.T) {
 			},
 			args: args{
 				requestedPosition: testdata.Position4_3,
+				direction:         testdata.Direction1_0,
 			},
-			wantPosition: testdata.Position0_3,
+			wantPosition:  testdata.Position0_3,
+			wantDirection: testdata.Direction1_0,
 		},
 		{
 			name: "TestSize4_4Pos3,4",
@@ -1173,8 +1443,10 @@ func TestGameBoard_translatePosition(t *testing.T) {
 			},
 			args: args{
 				requestedPosition: testdata.Position3_4,
+				direction:         common.Down,
 			},
-			wantPosition: testdata.Position3_0,
+			wantPosition:  testdata.Position3_0,
+			wantDirection: common.Down,
 		},
 		{
 			name: "TestSize4_4PosMinus1,Minus1",
@@ -1183,8 +1455,135 @@ func TestGameBoard_translatePosition(t *testing.T) {
 			},
 			args: args{
 				requestedPosition: testdata.PositionMinus1_Minus1,
+				direction:         testdata.DirectionMinus1_Minus1,
+			},
+			wantPosition:  testdata.Position2_2,
+			wantDirection: testdata.DirectionMinus1_Minus1,
+		},
+		{
+			name: "TestWallPos4,3", // The snake hits the right wall and doesn't move
+			fields: fields{
+				size:       testdata.Size4_4,
+				edgePolicy: common.UniformEdges(common.WallEdge),
+			},
+			args: args{
+				requestedPosition: testdata.Position4_3,
+				direction:         testdata.Direction1_0,
+			},
+			wantPosition:  testdata.Position4_3,
+			wantDirection: testdata.Direction1_0,
+			wantErr:       errHitWall,
+		},
+		{
+			name: "TestWallInside", // Walls only matter at the edges
+			fields: fields{
+				size:       testdata.Size4_4,
+				edgePolicy: common.UniformEdges(common.WallEdge),
+			},
+			args: args{
+				requestedPosition: testdata.Position1_2,
+				direction:         common.Down,
+			},
+			wantPosition:  testdata.Position1_2,
+			wantDirection: common.Down,
+		},
+		{
+			name: "TestBouncePos4,3", // The snake turns from 3,3 to 3,2 heading up, toward the center
+			fields: fields{
+				size:       testdata.Size4_4,
+				edgePolicy: common.UniformEdges(common.BounceEdge),
+			},
+			args: args{
+				requestedPosition: testdata.Position4_3,
+				direction:         testdata.Direction1_0,
+			},
+			wantPosition: common.Position{
+				X: 3,
+				Y: 2,
+			},
+			wantDirection: common.Up,
+		},
+		{
+			name: "TestBouncePos0,Minus1", // The snake turns from 0,0 to 1,0 heading right
+			fields: fields{
+				size:       testdata.Size4_4,
+				edgePolicy: common.UniformEdges(common.BounceEdge),
+			},
+			args: args{
+				requestedPosition: testdata.Position0_Minus1,
+				direction:         common.Up,
+			},
+			wantPosition:  common.Position{X: 1, Y: 0},
+			wantDirection: common.Right,
+		},
+		{
+			name: "TestBounceOneColumn", // The snake turns down along the column
+			fields: fields{
+				size: common.Size{
+					Width:  1,
+					Height: 4,
+				},
+				edgePolicy: common.UniformEdges(common.BounceEdge),
 			},
-			wantPosition: testdata.Position2_2,
+			args: args{
+				requestedPosition: common.Position{
+					X: 1,
+					Y: 0,
+				},
+				direction: testdata.Direction1_0,
+			},
+			wantPosition:  testdata.Position0_1,
+			wantDirection: common.Down,
+		},
+		{
+			name: "TestBounceOneRow", // There is no room to turn
+			fields: fields{
+				size: common.Size{
+					Width:  4,
+					Height: 1,
+				},
+				edgePolicy: common.UniformEdges(common.BounceEdge),
+			},
+			args: args{
+				requestedPosition: common.Position{
+					X: 4,
+					Y: 0,
+				},
+				direction: testdata.Direction1_0,
+			},
+			wantPosition: common.Position{
+				X: 4,
+				Y: 0,
+			},
+			wantDirection: testdata.Direction1_0,
+			wantErr:       errHitWall,
+		},
+		{
+			name: "TestMixedWrapHorizontally",
+			fields: fields{
+				size:       testdata.Size4_4,
+				edgePolicy: common.MixedEdges(common.WrapEdge, common.WallEdge),
+			},
+			args: args{
+				requestedPosition: testdata.Position4_3,
+				direction:         testdata.Direction1_0,
+			},
+			wantPosition:  testdata.Position0_3,
+			wantDirection: testdata.Direction1_0,
+		},
+		{
+			name: "TestMixedWallVertically",
+			fields: fields{
+				size:       testdata.Size4_4,
+				edgePolicy: common.MixedEdges(common.WrapEdge, common.WallEdge),
+			},
+			args: args{
+				requestedPosition: testdata.Position3_4,
+				direction:         common.Down,
+			},
+			wantPosition:  testdata.Position3_4,
+			wantDirection: common.Down,
+			wantErr:       errHitWall,
 		},
 	}
 	for _, tt := range tests {
@@ -1193,10 +1592,12 @@ func TestGameBoard_translatePosition(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
+				edgePolicy:  tt.fields.edgePolicy,
 			}
-			gotPosition, gotErr := aGameBoard.translatePosition(tt.args.requestedPosition)
+			gotPosition, gotDirection, gotErr := aGameBoard.translatePosition(tt.args.requestedPosition, tt.args.direction)
 			require.Equal(t, tt.wantPosition, gotPosition)
+			require.Equal(t, tt.wantDirection, gotDirection)
 			require.Equal(t, tt.wantErr, gotErr)
 		})
 	}
@@ -1213,23 +1614,44 @@ func Test_random(t *testing.T) {
 		max int
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantRnd int
-		wantErr bool
+		name       string
+		randomizer random.Randomer
+		args       args
+		wantRnd    int
+		wantErr    bool
 	}{
 		{
-			name: "TestRandomMax1",
+			name: "TestRandomMax1", // A nil source falls back to crypto/rand
 			args: args{
 				max: 1,
 			},
 			wantRnd: 0,
 			wantErr: false,
 		},
+		{
+			name:       "TestSeededMax1",
+			randomizer: random.NewSeeded(1),
+			args: args{
+				max: 1,
+			},
+			wantRnd: 0,
+			wantErr: false,
+		},
+		{
+			name: "TestRandomMax0",
+			args: args{
+				max: 0,
+			},
+			wantRnd: 0,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRnd, err := random(tt.args.max)
+			aGameBoard := &gameBoard{
+				randomizer: tt.randomizer,
+			}
+			gotRnd, err := aGameBoard.random(tt.args.max)
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
 			require.Equal(t, tt.wantRnd, gotRnd)
@@ -1242,7 +1664,7 @@ func Test_gameBoard_getOldValue(t *testing.T) {
 		size        common.Size
 		board       [][]rune
 		movingSnake snake.Snaker
-		candy       candy.Candyer
+		candies     []candy.Candyer
 	}
 	type args struct {
 		position common.Position
@@ -1333,7 +1755,7 @@ func Test_gameBoard_getOldValue(t *testing.T) {
 				size:        tt.fields.size,
 				board:       tt.fields.board,
 				movingSnake: tt.fields.movingSnake,
-				candy:       tt.fields.candy,
+				candies:     tt.fields.candies,
 			}
 			aSnake := &mocks.Snaker{}
 			aSnake.On("Tail").Return(tt.mockTail, tt.mockTailErr)
@@ -1348,3 +1770,545 @@ func Test_gameBoard_getOldValue(t *testing.T) {
 		})
 	}
 }
+
+func TestGameBoard_Snapshot(t *testing.T) {
+	type fields struct {
+		size        common.Size
+		board       [][]rune
+		movingSnake snake.Snaker
+		candies     []candy.Candyer
+	}
+	tests := []struct {
+		name        string
+		fields      fields
+		wantCells   []string
+		wantErrType error
+		wantErr     bool
+	}{
+		{
+			name: "TestNilSnake",
+			fields: fields{
+				size:    testdata.Size3_3,
+				board:   testdata.Duplicate(testdata.Board3_3),
+				candies: []candy.Candyer{candy.New()},
+			},
+			wantErrType: ErrInvalidSnakeReference,
+			wantErr:     true,
+		},
+		{
+			name: "TestNilCandy",
+			fields: fields{
+				size:        testdata.Size3_3,
+				board:       testdata.Duplicate(testdata.Board3_3),
+				movingSnake: snake.New(),
+				candies:     []candy.Candyer{nil},
+			},
+			wantErrType: ErrInvalidCandyReference,
+			wantErr:     true,
+		},
+		{
+			name: "TestBoard3_3Candy1_1", // The board is indexed by column first
+			fields: fields{
+				size:        testdata.Size3_3,
+				board:       testdata.Duplicate(testdata.Board3_3Candy1_1),
+				movingSnake: snake.New(),
+				candies:     []candy.Candyer{candy.New()},
+			},
+			wantCells: []string{"aaa", "b*b", "ccc"},
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameBoard := &gameBoard{
+				size:        tt.fields.size,
+				board:       tt.fields.board,
+				movingSnake: tt.fields.movingSnake,
+				candies:     tt.fields.candies,
+			}
+			gotSnapshot, err := aGameBoard.Snapshot()
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+			}
+			if gotErr {
+				return
+			}
+			require.Equal(t, tt.wantCells, gotSnapshot.Cells)
+			require.Nil(t, gotSnapshot.RandomState) // crypto/rand can't be saved
+
+			restored := New()
+			require.NoError(t, restored.Restore(gotSnapshot))
+			require.Equal(t, tt.fields.board, restored.(*gameBoard).board)
+		})
+	}
+}
+
+func TestGameBoard_Restore(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot snapshot.Board
+		wantErr  bool
+	}{
+		{
+			name: "TestMissingRow",
+			snapshot: snapshot.Board{
+				Size:  testdata.Size3_3,
+				Cells: []string{"   ", "   "},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestShortRow",
+			snapshot: snapshot.Board{
+				Size:  testdata.Size3_3,
+				Cells: []string{"   ", "  ", "   "},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestEmptyRows",
+			snapshot: snapshot.Board{
+				Size: common.Size{
+					Width:  1,
+					Height: 0,
+				},
+				Cells: []string{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "TestValid",
+			snapshot: snapshot.Board{
+				Size:    testdata.Size3_3,
+				Cells:   []string{"SS ", "  *", "  S"},
+				Snake:   snapshot.Snake{Body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+				Snakes:  []snapshot.Snake{{ID: 1, Body: []common.Position{{X: 2, Y: 2}}}},
+				Candies: []snapshot.Candy{{Alive: true, Position: common.Position{X: 2, Y: 1}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "TestSnakeOutOfBoard",
+			snapshot: snapshot.Board{
+				Size:  testdata.Size3_3,
+				Cells: []string{"S  ", "   ", "   "},
+				Snake: snapshot.Snake{Body: []common.Position{{X: 0, Y: 0}, {X: 50, Y: 50}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestSnakeNegativePosition",
+			snapshot: snapshot.Board{
+				Size:  testdata.Size3_3,
+				Cells: []string{"   ", "   ", "   "},
+				Snake: snapshot.Snake{Body: []common.Position{{X: -3, Y: 2}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestSnakeNotInCells",
+			snapshot: snapshot.Board{
+				Size:  testdata.Size3_3,
+				Cells: []string{"S  ", "   ", "   "},
+				Snake: snapshot.Snake{Body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestOtherSnakeWithoutBody",
+			snapshot: snapshot.Board{
+				Size:   testdata.Size3_3,
+				Cells:  []string{"   ", "   ", "   "},
+				Snakes: []snapshot.Snake{{ID: 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestCandyOutOfBoard",
+			snapshot: snapshot.Board{
+				Size:    testdata.Size3_3,
+				Cells:   []string{"   ", "   ", "   "},
+				Candies: []snapshot.Candy{{Alive: true, Position: common.Position{X: 99, Y: 1}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestCandyNotInCells",
+			snapshot: snapshot.Board{
+				Size:    testdata.Size3_3,
+				Cells:   []string{"   ", "   ", "   "},
+				Candies: []snapshot.Candy{{Alive: true, Position: common.Position{X: 1, Y: 1}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().Restore(tt.snapshot)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if gotErr {
+				require.ErrorIs(t, err, ErrInvalidSnapshot)
+			}
+		})
+	}
+}
+
+func TestGameBoard_Obstacles(t *testing.T) {
+	aGameBoard := New(WithRandom(random.NewSeeded(1)))
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+
+	// Every cell but 1,1 gets an obstacle
+	var wantObstacles []common.Position
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			position := common.Position{X: x, Y: y}
+			if position == testdata.Position1_1 {
+				continue
+			}
+			sprite, err := aGameBoard.PlaceObstacle(position)
+			require.NoError(t, err)
+			require.Equal(t, common.Sprite{Value: ObstacleBody, Position: position}, sprite)
+			wantObstacles = append(wantObstacles, position)
+		}
+	}
+	require.Equal(t, wantObstacles, aGameBoard.Obstacles())
+	_, err := aGameBoard.PlaceObstacle(testdata.Position0_0)
+	require.ErrorIs(t, err, ErrCellNotFree)
+	_, err = aGameBoard.PlaceObstacle(testdata.Position4_3)
+	require.ErrorIs(t, err, ErrInvalidPosition)
+
+	// The candies avoid the obstacles
+	sprite, err := aGameBoard.CreateCandy()
+	require.NoError(t, err)
+	require.Equal(t, testdata.Position1_1, sprite.Position)
+	_, err = aGameBoard.PlaceObstacle(testdata.Position1_1)
+	require.ErrorIs(t, err, ErrCellNotFree)
+
+	sprite, err = aGameBoard.RemoveObstacle(testdata.Position0_0)
+	require.NoError(t, err)
+	require.Equal(t, common.Sprite{Value: FreeSpace, Position: testdata.Position0_0}, sprite)
+	require.Equal(t, wantObstacles[1:], aGameBoard.Obstacles())
+	_, err = aGameBoard.RemoveObstacle(testdata.Position0_0)
+	require.ErrorIs(t, err, ErrNoObstacle)
+	require.True(t, aGameBoard.IsObstacle(ObstacleBody))
+	require.False(t, aGameBoard.IsObstacle(FreeSpace))
+}
+
+func TestGameBoard_PlaceCandy(t *testing.T) {
+	aGameBoard := New()
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+	sprite, err := aGameBoard.PlaceCandy(testdata.Position1_1, common.GoldenCandy)
+	require.NoError(t, err)
+	require.Equal(t, common.Sprite{Value: '$', Position: testdata.Position1_1}, sprite)
+	require.Equal(t, []common.Position{testdata.Position1_1}, aGameBoard.CandyPositions())
+	kind, err := aGameBoard.RemoveCandyAt(testdata.Position1_1)
+	require.NoError(t, err)
+	require.Equal(t, common.GoldenCandy, kind)
+
+	_, err = aGameBoard.PlaceObstacle(testdata.Position0_0)
+	require.NoError(t, err)
+	_, err = aGameBoard.PlaceCandy(testdata.Position0_0, common.RegularCandy)
+	require.ErrorIs(t, err, ErrCellNotFree)
+	_, err = aGameBoard.PlaceCandy(testdata.Position4_3, common.RegularCandy)
+	require.ErrorIs(t, err, ErrInvalidPosition)
+}
+
+func TestGameBoard_AddSnake(t *testing.T) {
+	aGameBoard := New()
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+	require.Empty(t, aGameBoard.SnakeIDs())
+
+	for id, position := range []common.Position{testdata.Position0_0, testdata.Position1_1, testdata.Position2_2} {
+		sprite, err := aGameBoard.AddSnake(common.SnakeID(id), position, testdata.Direction1_0)
+		require.NoError(t, err)
+		require.Equal(t, common.Sprite{Value: SnakePart, Position: position}, sprite)
+	}
+	require.Equal(t, []common.SnakeID{0, 1, 2}, aGameBoard.SnakeIDs())
+	_, err := aGameBoard.AddSnake(1, testdata.Position0_1, testdata.Direction1_0)
+	require.ErrorIs(t, err, ErrSnakeExists)
+	_, err = aGameBoard.AddSnake(3, testdata.Position1_1, testdata.Direction1_0)
+	require.ErrorIs(t, err, ErrCellNotFree)
+	_, err = aGameBoard.AddSnake(-1, testdata.Position0_1, testdata.Direction1_0)
+	require.ErrorIs(t, err, ErrUnknownSnake)
+
+	require.NoError(t, aGameBoard.TurnSnake(2, common.Down))
+	state, err := aGameBoard.SnakeState(2)
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Snake{
+		ID:        2,
+		Body:      []common.Position{testdata.Position2_2},
+		Direction: common.Down,
+	}, state)
+	require.ErrorIs(t, aGameBoard.TurnSnake(3, common.Down), ErrUnknownSnake)
+
+	listSprite, err := aGameBoard.RemoveSnake(1)
+	require.NoError(t, err)
+	require.Equal(t, []common.Sprite{{Value: FreeSpace, Position: testdata.Position1_1}}, listSprite)
+	require.Equal(t, []common.SnakeID{0, 2}, aGameBoard.SnakeIDs())
+	_, err = aGameBoard.RemoveSnake(1)
+	require.ErrorIs(t, err, ErrUnknownSnake)
+	_, err = aGameBoard.RemoveSnake(0)
+	require.NoError(t, err)
+	require.Equal(t, []common.SnakeID{2}, aGameBoard.SnakeIDs())
+
+	// The other snakes are saved with the board
+	aSnapshot, err := aGameBoard.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, []snapshot.Snake{state}, aSnapshot.Snakes)
+	restored := New()
+	require.NoError(t, restored.Restore(aSnapshot))
+	require.Equal(t, []common.SnakeID{2}, restored.SnakeIDs())
+}
+
+func TestGameBoard_Clone(t *testing.T) {
+	aGameBoard := New(WithRandom(random.NewSeeded(5)))
+	require.NoError(t, aGameBoard.InitGameBoard(testdata.Size4_4))
+	_, err := aGameBoard.CreateSnake(testdata.Position0_0, testdata.Direction1_0)
+	require.NoError(t, err)
+	_, err = aGameBoard.AddSnake(1, testdata.Position2_2, testdata.Direction1_0)
+	require.NoError(t, err)
+	_, err = aGameBoard.CreateCandy()
+	require.NoError(t, err)
+	require.True(t, aGameBoard.Deterministic())
+	want, err := aGameBoard.Snapshot()
+	require.NoError(t, err)
+
+	aClone, err := Clone(aGameBoard)
+	require.NoError(t, err)
+	got, err := aClone.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// Both boards draw the same candies, the clone moves on its own
+	sprite, err := aGameBoard.CreateCandy()
+	require.NoError(t, err)
+	cloneSprite, err := aClone.CreateCandy()
+	require.NoError(t, err)
+	require.Equal(t, sprite, cloneSprite)
+	_, _, err = aClone.MoveSnakes()
+	require.NoError(t, err)
+	position, err := aGameBoard.SnakePosition()
+	require.NoError(t, err)
+	require.Equal(t, testdata.Position0_0, position)
+	state, err := aGameBoard.SnakeState(1)
+	require.NoError(t, err)
+	require.Equal(t, []common.Position{testdata.Position2_2}, state.Body)
+
+	require.False(t, New(WithRandom(random.NewCrypto())).Deterministic())
+	_, err = Clone(&mocks.GameBoarder{})
+	require.ErrorIs(t, err, ErrNotCloneable)
+}
+
+func TestGameBoard_MoveSnakes(t *testing.T) {
+	type snakeSpec struct {
+		body      []common.Position // starts with the tail
+		direction common.Direction
+	}
+	size := common.Size{
+		Width:  5,
+		Height: 5,
+	}
+	tests := []struct {
+		name           string
+		options        []Option
+		snakes         []snakeSpec
+		cells          []common.Sprite
+		wantCollisions []common.GameOverReason
+		wantIDs        []common.SnakeID
+	}{
+		{
+			name: "TestHeadToBody",
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}}, direction: common.Up},
+			},
+			wantCollisions: []common.GameOverReason{common.SnakeCollision, common.NotOver},
+			wantIDs:        []common.SnakeID{1},
+		},
+		{
+			name: "TestFollowTail", // The tail leaves the cell the other head enters
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 2, Y: 0}, {X: 3, Y: 0}}, direction: common.Right},
+			},
+			wantCollisions: []common.GameOverReason{common.NotOver, common.NotOver},
+			wantIDs:        []common.SnakeID{0, 1},
+		},
+		{
+			name: "TestFollowGrowingTail",
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 2, Y: 0}, {X: 3, Y: 0}}, direction: common.Right},
+			},
+			cells:          []common.Sprite{{Value: CandyBody, Position: common.Position{X: 4, Y: 0}}},
+			wantCollisions: []common.GameOverReason{common.SnakeCollision, common.NotOver},
+			wantIDs:        []common.SnakeID{1},
+		},
+		{
+			name: "TestHeadOnBothDie",
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 3, Y: 0}}, direction: common.Left},
+			},
+			wantCollisions: []common.GameOverReason{common.HeadOnCollision, common.HeadOnCollision},
+		},
+		{
+			name:    "TestHeadOnLongerWins",
+			options: []Option{WithHeadOnRule(common.HeadOnLongerWins)},
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 0, Y: 0}, {X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 3, Y: 0}}, direction: common.Left},
+			},
+			wantCollisions: []common.GameOverReason{common.NotOver, common.HeadOnCollision},
+			wantIDs:        []common.SnakeID{0},
+		},
+		{
+			name:    "TestHeadOnTie",
+			options: []Option{WithHeadOnRule(common.HeadOnLongerWins)},
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 3, Y: 0}}, direction: common.Left},
+			},
+			wantCollisions: []common.GameOverReason{common.HeadOnCollision, common.HeadOnCollision},
+		},
+		{
+			name: "TestSwap", // The heads can't go through each other
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 1, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 2, Y: 0}}, direction: common.Left},
+			},
+			wantCollisions: []common.GameOverReason{common.HeadOnCollision, common.HeadOnCollision},
+		},
+		{
+			name:    "TestWallAndObstacle",
+			options: []Option{WithEdgePolicy(common.UniformEdges(common.WallEdge))},
+			snakes: []snakeSpec{
+				{body: []common.Position{{X: 1, Y: 1}}, direction: common.Right},
+				{body: []common.Position{{X: 4, Y: 0}}, direction: common.Right},
+				{body: []common.Position{{X: 0, Y: 3}}, direction: common.Down},
+			},
+			cells:          []common.Sprite{{Value: ObstacleBody, Position: common.Position{X: 0, Y: 4}}},
+			wantCollisions: []common.GameOverReason{common.NotOver, common.WallCollision, common.ObstacleCollision},
+			wantIDs:        []common.SnakeID{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameBoard := New(tt.options...)
+			require.NoError(t, aGameBoard.InitGameBoard(size))
+			for _, sprite := range tt.cells {
+				require.NoError(t, aGameBoard.(*gameBoard).setCell(sprite.Position, sprite.Value))
+			}
+			for i, spec := range tt.snakes {
+				id := common.SnakeID(i)
+				_, err := aGameBoard.AddSnake(id, spec.body[0], spec.direction)
+				require.NoError(t, err)
+				aSnake, err := aGameBoard.(*gameBoard).snakeOf(id)
+				require.NoError(t, err)
+				for _, position := range spec.body[1:] {
+					require.NoError(t, aSnake.GrowTo(position))
+					require.NoError(t, aGameBoard.(*gameBoard).setCell(position, SnakePart))
+				}
+			}
+
+			moves, _, err := aGameBoard.MoveSnakes()
+			require.NoError(t, err)
+			var gotCollisions []common.GameOverReason
+			for i, move := range moves {
+				require.Equal(t, common.SnakeID(i), move.ID)
+				gotCollisions = append(gotCollisions, move.Collision)
+			}
+			require.Equal(t, tt.wantCollisions, gotCollisions)
+			require.Equal(t, tt.wantIDs, aGameBoard.SnakeIDs())
+
+			// Only the live snakes are left on the board
+			wantParts := 0
+			for _, id := range aGameBoard.SnakeIDs() {
+				state, err := aGameBoard.SnakeState(id)
+				require.NoError(t, err)
+				wantParts += len(state.Body)
+				for _, position := range state.Body {
+					cell, err := aGameBoard.(*gameBoard).cell(position)
+					require.NoError(t, err)
+					require.Equal(t, SnakePart, cell)
+				}
+			}
+			aSnapshot, err := aGameBoard.Snapshot()
+			require.NoError(t, err)
+			gotParts := 0
+			for _, row := range aSnapshot.Cells {
+				for _, cell := range row {
+					if cell == SnakePart {
+						gotParts++
+					}
+				}
+			}
+			require.Equal(t, wantParts, gotParts)
+		})
+	}
+}
+
+func TestGameBoard_NextPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		edges         common.Edge
+		position      common.Position
+		direction     common.Direction
+		wantPosition  common.Position
+		wantDirection common.Direction
+		wantOk        bool
+	}{
+		{
+			name:          "TestInside",
+			edges:         common.WallEdge,
+			position:      testdata.Position1_1,
+			direction:     common.Up,
+			wantPosition:  common.Position{X: 1, Y: 0},
+			wantDirection: common.Up,
+			wantOk:        true,
+		},
+		{
+			name:          "TestWrap",
+			edges:         common.WrapEdge,
+			position:      testdata.Position0_0,
+			direction:     common.Left,
+			wantPosition:  common.Position{X: 2, Y: 0},
+			wantDirection: common.Left,
+			wantOk:        true,
+		},
+		{
+			name:      "TestWall",
+			edges:     common.WallEdge,
+			position:  testdata.Position0_0,
+			direction: common.Left,
+			wantOk:    false,
+		},
+		{
+			name:          "TestBounce",
+			edges:         common.BounceEdge,
+			position:      testdata.Position0_0,
+			direction:     common.Left,
+			wantPosition:  testdata.Position0_1,
+			wantDirection: common.Down,
+			wantOk:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aGameBoard := New(WithEdgePolicy(common.UniformEdges(tt.edges)))
+			require.NoError(t, aGameBoard.InitGameBoard(testdata.Size3_3))
+			gotPosition, gotDirection, gotOk := aGameBoard.NextPosition(tt.position, tt.direction)
+			require.Equal(t, tt.wantOk, gotOk)
+			if gotOk {
+				require.Equal(t, tt.wantPosition, gotPosition)
+				require.Equal(t, tt.wantDirection, gotDirection)
+			}
+			cell, err := aGameBoard.Cell(tt.position)
+			require.NoError(t, err)
+			require.Equal(t, FreeSpace, cell)
+		})
+	}
+}
@@ -0,0 +1,542 @@
+// Package level reads and writes levels as text files.
+//
+// A level starts with a header of "key: value" lines, then a "---" line
+// and the grid, one line per row:
+//
+//	name: Box
+//	edges: wall wall wall wall
+//	snake: 2 1 right
+//	candies: 2
+//	win: score 10
+//	---
+//	######
+//	#....#
+//	#..*.#
+//	######
+//
+// In the grid '#' is an obstacle, '.' or ' ' a free cell and the body of a
+// default candy kind places a candy of that kind. Short rows are padded
+// with free cells.
+//
+// The edges take one value for every edge, two values for the horizontal
+// and vertical edges or four values for the left, right, top and bottom
+// edges. The win condition is none, fill, score N, survive N or candies N M
+// to eat N candies within M rounds. The snake line may be repeated, the
+// first one is the snake 0 and the next ones add the snakes 1, 2...
+// Every snake starts with one part.
+package level
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/gameboard"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+)
+
+// Grid cells
+const (
+	obstacleCell rune = '#'
+	freeCell     rune = '.'
+)
+
+// separator ends the header
+const separator = "---"
+
+// ErrInvalidLevel is a custom error thrown when a level can't be read
+var ErrInvalidLevel = errors.New("invalid level")
+
+// Level is everything needed to start a game on a designed board
+type Level struct {
+	Name           string
+	Size           common.Size
+	Edges          common.EdgePolicy
+	SnakePosition  common.Position
+	SnakeDirection common.Direction
+	Snakes         []SnakeStart // the snakes added to the snake 0, they get the IDs 1, 2...
+	CandyCount     int
+	Win            common.WinCondition
+	Obstacles      []common.Position
+	Candies        []common.Sprite // candies on the board at start, the value is the candy body
+}
+
+// SnakeStart is where a snake starts and where it heads to
+type SnakeStart struct {
+	Position  common.Position
+	Direction common.Direction
+}
+
+var (
+	directionNames = map[common.Direction]string{
+		common.Left:  "left",
+		common.Right: "right",
+		common.Up:    "up",
+		common.Down:  "down",
+	}
+	goalNames = map[common.WinGoal]string{
+		common.NoWin:         "none",
+		common.FillBoard:     "fill",
+		common.ReachScore:    "score",
+		common.SurviveRounds: "survive",
+		common.EatCandies:    "candies",
+	}
+)
+
+// Read decodes a level written by Write or by hand
+func Read(r io.Reader) (aLevel Level, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	candyCountSet := false
+	inGrid := false
+	var rows [][]rune
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if inGrid {
+			rows = append(rows, []rune(line))
+			continue
+		}
+		if strings.TrimSpace(line) == separator {
+			inGrid = true
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		keyValue := strings.SplitN(line, ":", 2)
+		if len(keyValue) != 2 {
+			return aLevel, fmt.Errorf("%w: line %d: expected key: value", ErrInvalidLevel, lineNumber)
+		}
+		key := strings.TrimSpace(keyValue[0])
+		if err = aLevel.setHeader(key, strings.TrimSpace(keyValue[1])); err != nil {
+			return aLevel, fmt.Errorf("%w: line %d: %v", ErrInvalidLevel, lineNumber, err)
+		}
+		if key == "candies" {
+			candyCountSet = true
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return aLevel, err
+	}
+	if !inGrid {
+		return aLevel, fmt.Errorf("%w: no %q line before the grid", ErrInvalidLevel, separator)
+	}
+
+	if err = aLevel.setGrid(rows); err != nil {
+		return aLevel, err
+	}
+	// The snake lines were read into Snakes, the first one is the snake 0
+	if len(aLevel.Snakes) == 0 {
+		aLevel.SnakePosition = common.Position{
+			X: aLevel.Size.Width / 2,
+			Y: aLevel.Size.Height / 2,
+		}
+		aLevel.SnakeDirection = common.Right
+	} else {
+		aLevel.SnakePosition = aLevel.Snakes[0].Position
+		aLevel.SnakeDirection = aLevel.Snakes[0].Direction
+		aLevel.Snakes = aLevel.Snakes[1:]
+		if len(aLevel.Snakes) == 0 {
+			aLevel.Snakes = nil
+		}
+	}
+	if !candyCountSet {
+		aLevel.CandyCount = len(aLevel.Candies)
+	}
+	if err = aLevel.checkSnake(); err != nil {
+		return aLevel, err
+	}
+
+	return aLevel, nil
+}
+
+// setHeader reads a header line, every snake line is added to Snakes
+func (aLevel *Level) setHeader(key, value string) (err error) {
+	fields := strings.Fields(value)
+	switch key {
+	case "name":
+		aLevel.Name = value
+	case "edges":
+		aLevel.Edges, err = parseEdges(fields)
+	case "snake":
+		if len(fields) != 3 {
+			return errors.New("expected snake: x y direction")
+		}
+		var start SnakeStart
+		if start.Position.X, err = strconv.Atoi(fields[0]); err != nil {
+			return err
+		}
+		if start.Position.Y, err = strconv.Atoi(fields[1]); err != nil {
+			return err
+		}
+		if start.Direction, err = parseDirection(fields[2]); err != nil {
+			return err
+		}
+		aLevel.Snakes = append(aLevel.Snakes, start)
+	case "candies":
+		aLevel.CandyCount, err = strconv.Atoi(value)
+	case "win":
+		aLevel.Win, err = parseWin(fields)
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+
+	return err
+}
+
+// setGrid reads the size, the obstacles and the candies of the grid
+func (aLevel *Level) setGrid(rows [][]rune) (err error) {
+	// Trailing empty lines are not rows
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if width == 0 {
+		return fmt.Errorf("%w: empty grid", ErrInvalidLevel)
+	}
+	aLevel.Size = common.Size{
+		Width:  width,
+		Height: len(rows),
+	}
+
+	candyKinds := candy.DefaultRegistry()
+	for y, row := range rows {
+		for x, cell := range row {
+			position := common.Position{X: x, Y: y}
+			switch cell {
+			case freeCell, gameboard.FreeSpace:
+			case obstacleCell:
+				aLevel.Obstacles = append(aLevel.Obstacles, position)
+			default:
+				if _, ok := candyKinds.KindOf(cell); !ok {
+					return fmt.Errorf("%w: unknown cell %q at %d,%d", ErrInvalidLevel, cell, x, y)
+				}
+				aLevel.Candies = append(aLevel.Candies, common.Sprite{
+					Value:    cell,
+					Position: position,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSnake checks that every snake starts on its own free cell of the grid
+func (aLevel *Level) checkSnake() error {
+	starts := append([]SnakeStart{{Position: aLevel.SnakePosition}}, aLevel.Snakes...)
+	for id, start := range starts {
+		position := start.Position
+		if position.X < 0 || position.X >= aLevel.Size.Width || position.Y < 0 || position.Y >= aLevel.Size.Height {
+			return fmt.Errorf("%w: the snake %d starts out of the grid", ErrInvalidLevel, id)
+		}
+		for _, obstacle := range aLevel.Obstacles {
+			if obstacle == position {
+				return fmt.Errorf("%w: the snake %d starts on an obstacle", ErrInvalidLevel, id)
+			}
+		}
+		for _, sprite := range aLevel.Candies {
+			if sprite.Position == position {
+				return fmt.Errorf("%w: the snake %d starts on a candy", ErrInvalidLevel, id)
+			}
+		}
+		for _, other := range starts[:id] {
+			if other.Position == position {
+				return fmt.Errorf("%w: the snake %d starts on another snake", ErrInvalidLevel, id)
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseEdges(fields []string) (edgePolicy common.EdgePolicy, err error) {
+	edges := make([]common.Edge, len(fields))
+	for i, field := range fields {
+		if edges[i], err = common.ParseEdge(field); err != nil {
+			return edgePolicy, err
+		}
+	}
+
+	switch len(edges) {
+	case 1:
+		return common.UniformEdges(edges[0]), nil
+	case 2:
+		return common.MixedEdges(edges[0], edges[1]), nil
+	case 4:
+		return common.EdgePolicy{
+			Left:   edges[0],
+			Right:  edges[1],
+			Top:    edges[2],
+			Bottom: edges[3],
+		}, nil
+	}
+
+	return edgePolicy, errors.New("expected 1, 2 or 4 edges")
+}
+
+func parseDirection(name string) (direction common.Direction, err error) {
+	for direction, directionName := range directionNames {
+		if directionName == name {
+			return direction, nil
+		}
+	}
+
+	return direction, fmt.Errorf("unknown direction %q", name)
+}
+
+func parseWin(fields []string) (win common.WinCondition, err error) {
+	if len(fields) == 0 {
+		return win, errors.New("expected a win condition")
+	}
+	for goal, goalName := range goalNames {
+		if goalName == fields[0] {
+			win.Goal = goal
+		}
+	}
+	if goalNames[win.Goal] != fields[0] {
+		return win, fmt.Errorf("unknown win condition %q", fields[0])
+	}
+
+	values := make([]int, len(fields)-1)
+	for i, field := range fields[1:] {
+		if values[i], err = strconv.Atoi(field); err != nil {
+			return win, err
+		}
+	}
+	wantValues := map[common.WinGoal]int{
+		common.NoWin:         0,
+		common.FillBoard:     0,
+		common.ReachScore:    1,
+		common.SurviveRounds: 1,
+		common.EatCandies:    2,
+	}[win.Goal]
+	if len(values) != wantValues {
+		return win, fmt.Errorf("%s expects %d values", fields[0], wantValues)
+	}
+	if len(values) > 0 {
+		win.Target = values[0]
+	}
+	if len(values) > 1 {
+		win.Rounds = values[1]
+	}
+
+	return win, nil
+}
+
+// Write encodes the level in the format read by Read
+func Write(w io.Writer, aLevel Level) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	var snakes strings.Builder
+	starts := append([]SnakeStart{{Position: aLevel.SnakePosition, Direction: aLevel.SnakeDirection}}, aLevel.Snakes...)
+	for _, start := range starts {
+		direction, ok := directionNames[start.Direction]
+		if !ok {
+			return fmt.Errorf("%w: invalid snake direction %v", ErrInvalidLevel, start.Direction)
+		}
+		fmt.Fprintf(&snakes, "snake: %d %d %s\n", start.Position.X, start.Position.Y, direction)
+	}
+	win := goalNames[aLevel.Win.Goal]
+	switch aLevel.Win.Goal {
+	case common.ReachScore, common.SurviveRounds:
+		win += fmt.Sprintf(" %d", aLevel.Win.Target)
+	case common.EatCandies:
+		win += fmt.Sprintf(" %d %d", aLevel.Win.Target, aLevel.Win.Rounds)
+	}
+
+	grid := make([][]rune, aLevel.Size.Height)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(string(freeCell), aLevel.Size.Width))
+	}
+	set := func(position common.Position, cell rune) error {
+		if position.X < 0 || position.X >= aLevel.Size.Width || position.Y < 0 || position.Y >= aLevel.Size.Height {
+			return fmt.Errorf("%w: %d,%d is out of the grid", ErrInvalidLevel, position.X, position.Y)
+		}
+		grid[position.Y][position.X] = cell
+		return nil
+	}
+	for _, position := range aLevel.Obstacles {
+		if err = set(position, obstacleCell); err != nil {
+			return err
+		}
+	}
+	for _, sprite := range aLevel.Candies {
+		if err = set(sprite.Position, sprite.Value); err != nil {
+			return err
+		}
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "name: %s\n", aLevel.Name)
+	fmt.Fprintf(&builder, "edges: %s %s %s %s\n",
+		aLevel.Edges.Left, aLevel.Edges.Right, aLevel.Edges.Top, aLevel.Edges.Bottom)
+	builder.WriteString(snakes.String())
+	fmt.Fprintf(&builder, "candies: %d\n", aLevel.CandyCount)
+	fmt.Fprintf(&builder, "win: %s\n", win)
+	fmt.Fprintln(&builder, separator)
+	for _, row := range grid {
+		fmt.Fprintln(&builder, string(row))
+	}
+
+	_, err = io.WriteString(w, builder.String())
+	return err
+}
+
+// NewBoard returns a board initialized with the level: obstacles, snake
+// and candies are placed, random candies are added up to the candy count
+func (aLevel Level) NewBoard(options ...gameboard.Option) (aGameBoard gameboard.GameBoarder, listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	options = append([]gameboard.Option{gameboard.WithEdgePolicy(aLevel.Edges)}, options...)
+	aGameBoard = gameboard.New(options...)
+	if err = aGameBoard.InitGameBoard(aLevel.Size); err != nil {
+		return nil, nil, err
+	}
+	for _, position := range aLevel.Obstacles {
+		sprite, err := aGameBoard.PlaceObstacle(position)
+		if err != nil {
+			return nil, nil, err
+		}
+		listSprite = append(listSprite, sprite)
+	}
+	sprite, err := aGameBoard.CreateSnake(aLevel.SnakePosition, aLevel.SnakeDirection)
+	if err != nil {
+		return nil, nil, err
+	}
+	listSprite = append(listSprite, sprite)
+	for i, start := range aLevel.Snakes {
+		if sprite, err = aGameBoard.AddSnake(common.SnakeID(i+1), start.Position, start.Direction); err != nil {
+			return nil, nil, err
+		}
+		listSprite = append(listSprite, sprite)
+	}
+
+	candyKinds := candy.DefaultRegistry()
+	for _, aCandy := range aLevel.Candies {
+		kind, ok := candyKinds.KindOf(aCandy.Value)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: unknown candy %q", ErrInvalidLevel, aCandy.Value)
+		}
+		if sprite, err = aGameBoard.PlaceCandy(aCandy.Position, kind); err != nil {
+			return nil, nil, err
+		}
+		listSprite = append(listSprite, sprite)
+	}
+	for i := len(aLevel.Candies); i < aLevel.CandyCount; i++ {
+		if sprite, err = aGameBoard.CreateCandy(); err != nil {
+			return nil, nil, err
+		}
+		listSprite = append(listSprite, sprite)
+	}
+
+	return aGameBoard, listSprite, nil
+}
+
+// Options returns the game options playing the level,
+// the board still has to be initialized with Size
+func (aLevel Level) Options() []gamestate.Option {
+	options := []gamestate.Option{
+		gamestate.WithEdgePolicy(aLevel.Edges),
+		gamestate.WithSnakeStart(aLevel.SnakePosition, aLevel.SnakeDirection),
+		gamestate.WithCandyRule(common.CandyRule{Count: aLevel.CandyCount}),
+		gamestate.WithObstacles(aLevel.Obstacles...),
+		gamestate.WithCandies(aLevel.Candies...),
+		gamestate.WithWinCondition(aLevel.Win),
+	}
+	for _, start := range aLevel.Snakes {
+		options = append(options, gamestate.WithSnake(start.Position, start.Direction))
+	}
+
+	return options
+}
+
+// FromBoard returns the level of the current state of a board.
+// Each snake is saved as its head position and direction
+func FromBoard(aGameBoard gameboard.GameBoarder) (aLevel Level, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	board, err := aGameBoard.Snapshot()
+	if err != nil {
+		return aLevel, err
+	}
+	if aLevel, err = fromSnapshot(board, aGameBoard.EdgePolicy()); err != nil {
+		return aLevel, err
+	}
+	aLevel.CandyCount = len(aLevel.Candies)
+	return aLevel, nil
+}
+
+// FromGame returns the level of the current state of a game.
+// Each snake is saved as its head position and direction
+func FromGame(game gamestate.GameStater) (aLevel Level, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aSnapshot, err := game.Snapshot()
+	if err != nil {
+		return aLevel, err
+	}
+	if aLevel, err = fromSnapshot(aSnapshot.Board, game.EdgePolicy()); err != nil {
+		return aLevel, err
+	}
+	aLevel.CandyCount = game.CandyRule().Count
+	aLevel.Win = game.WinCondition()
+	return aLevel, nil
+}
+
+// fromSnapshot returns the level of a board, the snakes other than the
+// snake 0 are added in the order of their IDs. A level can't start
+// without the snake 0: it fails once the snake 0 is removed from a game
+// with several snakes. The candies eaten or expired are left out
+func fromSnapshot(board snapshot.Board, edgePolicy common.EdgePolicy) (aLevel Level, err error) {
+	if len(board.Snake.Body) == 0 && len(board.Snakes) > 0 {
+		return aLevel, fmt.Errorf("%w: the snake 0 has been removed", ErrInvalidLevel)
+	}
+	aLevel.Size = board.Size
+	aLevel.Edges = edgePolicy
+	aLevel.SnakeDirection = board.Snake.Direction
+	if len(board.Snake.Body) > 0 {
+		aLevel.SnakePosition = board.Snake.Body[len(board.Snake.Body)-1]
+	}
+	for _, aSnake := range board.Snakes {
+		aLevel.Snakes = append(aLevel.Snakes, SnakeStart{
+			Position:  aSnake.Body[len(aSnake.Body)-1],
+			Direction: aSnake.Direction,
+		})
+	}
+
+	cells := make([][]rune, len(board.Cells))
+	for y, row := range board.Cells {
+		cells[y] = []rune(row)
+		for x, cell := range cells[y] {
+			if cell == gameboard.ObstacleBody {
+				aLevel.Obstacles = append(aLevel.Obstacles, common.Position{X: x, Y: y})
+			}
+		}
+	}
+	for _, aCandy := range board.Candies {
+		if !aCandy.Alive {
+			continue
+		}
+		aLevel.Candies = append(aLevel.Candies, common.Sprite{
+			Value:    cells[aCandy.Position.Y][aCandy.Position.X],
+			Position: aCandy.Position,
+		})
+	}
+
+	return aLevel, nil
+}
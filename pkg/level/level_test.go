@@ -0,0 +1,299 @@
+package level
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/gameboard"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/random"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+
+	"github.com/stretchr/testify/require"
+)
+
+const box = `name: Box
+edges: wall wall wrap wrap
+snake: 1 1 down
+candies: 3
+win: candies 10 300
+---
+######
+#....#
+#..*$#
+######
+`
+
+func boxLevel() Level {
+	return Level{
+		Name: "Box",
+		Size: common.Size{
+			Width:  6,
+			Height: 4,
+		},
+		Edges:          common.MixedEdges(common.WallEdge, common.WrapEdge),
+		SnakePosition:  common.Position{X: 1, Y: 1},
+		SnakeDirection: common.Down,
+		CandyCount:     3,
+		Win: common.WinCondition{
+			Goal:   common.EatCandies,
+			Target: 10,
+			Rounds: 300,
+		},
+		Obstacles: []common.Position{
+			{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0},
+			{X: 0, Y: 1}, {X: 5, Y: 1},
+			{X: 0, Y: 2}, {X: 5, Y: 2},
+			{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 3, Y: 3}, {X: 4, Y: 3}, {X: 5, Y: 3},
+		},
+		Candies: []common.Sprite{
+			{Value: '*', Position: common.Position{X: 3, Y: 2}},
+			{Value: '$', Position: common.Position{X: 4, Y: 2}},
+		},
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+	gotLevel, err := Read(strings.NewReader(box))
+	require.NoError(t, err)
+	require.Equal(t, boxLevel(), gotLevel)
+
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, gotLevel))
+	require.Equal(t, box, buffer.String())
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantLevel Level
+		wantErr   bool
+	}{
+		{
+			name:  "TestDefaults", // The snake starts at the center heading right
+			input: "---\n...\n*\n\n",
+			wantLevel: Level{
+				Size: common.Size{
+					Width:  3,
+					Height: 2,
+				},
+				SnakePosition:  common.Position{X: 1, Y: 1},
+				SnakeDirection: common.Right,
+				CandyCount:     1,
+				Candies: []common.Sprite{
+					{Value: '*', Position: common.Position{X: 0, Y: 1}},
+				},
+			},
+		},
+		{
+			name:  "TestTwoEdgesAndSpaces",
+			input: "edges: bounce wall\nwin: score 5\n---\n   \n  #\n",
+			wantLevel: Level{
+				Size: common.Size{
+					Width:  3,
+					Height: 2,
+				},
+				Edges:          common.MixedEdges(common.BounceEdge, common.WallEdge),
+				SnakePosition:  common.Position{X: 1, Y: 1},
+				SnakeDirection: common.Right,
+				Win: common.WinCondition{
+					Goal:   common.ReachScore,
+					Target: 5,
+				},
+				Obstacles: []common.Position{{X: 2, Y: 1}},
+			},
+		},
+		{
+			name:  "TestSeveralSnakes", // The first snake line is the snake 0
+			input: "snake: 0 0 right\nsnake: 2 1 left\nsnake: 2 0 down\n---\n...\n...\n",
+			wantLevel: Level{
+				Size: common.Size{
+					Width:  3,
+					Height: 2,
+				},
+				SnakePosition:  common.Position{X: 0, Y: 0},
+				SnakeDirection: common.Right,
+				Snakes: []SnakeStart{
+					{Position: common.Position{X: 2, Y: 1}, Direction: common.Left},
+					{Position: common.Position{X: 2, Y: 0}, Direction: common.Down},
+				},
+			},
+		},
+		{
+			name:    "TestNoSeparator",
+			input:   "name: Box\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestEmptyGrid",
+			input:   "---\n\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestUnknownKey",
+			input:   "speed: 3\n---\n...\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestUnknownCell",
+			input:   "---\n.?.\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestThreeEdges",
+			input:   "edges: wall wall wall\n---\n...\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestUnknownDirection",
+			input:   "snake: 0 0 north\n---\n...\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestSnakeOutOfGrid",
+			input:   "snake: 3 0 left\n---\n...\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestSnakeOnObstacle",
+			input:   "snake: 0 0 left\n---\n#..\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestSnakeOnCandy",
+			input:   "---\n...\n.*.\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestSnakeOnSnake",
+			input:   "snake: 0 0 right\nsnake: 0 0 left\n---\n...\n",
+			wantErr: true,
+		},
+		{
+			name:    "TestWinMissingValue",
+			input:   "win: survive\n---\n...\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLevel, err := Read(strings.NewReader(tt.input))
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if gotErr {
+				require.ErrorIs(t, err, ErrInvalidLevel)
+				return
+			}
+			require.Equal(t, tt.wantLevel, gotLevel)
+		})
+	}
+}
+
+func TestLevel_NewBoard(t *testing.T) {
+	aLevel := boxLevel()
+	aGameBoard, listSprite, err := aLevel.NewBoard(gameboard.WithRandom(random.NewSeeded(1)))
+	require.NoError(t, err)
+	require.Len(t, listSprite, len(aLevel.Obstacles)+1+aLevel.CandyCount)
+	require.Equal(t, aLevel.Edges, aGameBoard.EdgePolicy())
+	require.Equal(t, aLevel.CandyCount, aGameBoard.CandyCount())
+
+	// The random candy is dumped with the others
+	gotLevel, err := FromBoard(aGameBoard)
+	require.NoError(t, err)
+	require.Equal(t, aLevel.Obstacles, gotLevel.Obstacles)
+	require.Equal(t, aLevel.Candies, gotLevel.Candies[:2])
+	require.Len(t, gotLevel.Candies, 3)
+	require.Equal(t, aLevel.SnakePosition, gotLevel.SnakePosition)
+	require.Equal(t, aLevel.SnakeDirection, gotLevel.SnakeDirection)
+}
+
+func TestLevel_Options(t *testing.T) {
+	aLevel := boxLevel()
+	aLevel.CandyCount = 2
+	game := gamestate.New(append(aLevel.Options(), gamestate.WithSeed(1))...)
+	require.NoError(t, game.InitBoard(aLevel.Size))
+	_, err := game.CreateObjects()
+	require.NoError(t, err)
+
+	gotLevel, err := FromGame(game)
+	require.NoError(t, err)
+	aLevel.Name = ""
+	require.Equal(t, aLevel, gotLevel)
+
+	// The win header of a level read from text decides the game:
+	// the snake wins by eating the candy in front of it
+	aLevel, err = Read(strings.NewReader("snake: 1 1 right\nwin: score 1\n---\n....\n..*.\n....\n"))
+	require.NoError(t, err)
+	game = gamestate.New(append(aLevel.Options(), gamestate.WithSeed(1))...)
+	require.NoError(t, game.InitBoard(aLevel.Size))
+	_, err = game.CreateObjects()
+	require.NoError(t, err)
+	game.Start()
+	_, err = game.Play()
+	require.NoError(t, err)
+	require.False(t, game.GameInProgress())
+	require.Equal(t, common.Won, game.Outcome())
+}
+
+func TestLevel_SeveralSnakes(t *testing.T) {
+	aLevel := boxLevel()
+	aLevel.Name = ""
+	aLevel.CandyCount = 2
+	aLevel.Snakes = []SnakeStart{
+		{Position: common.Position{X: 4, Y: 1}, Direction: common.Left},
+	}
+	game := gamestate.New(append(aLevel.Options(), gamestate.WithSeed(1))...)
+	require.NoError(t, game.InitBoard(aLevel.Size))
+	_, err := game.CreateObjects()
+	require.NoError(t, err)
+
+	// The other snake goes through the text of the level
+	gotLevel, err := FromGame(game)
+	require.NoError(t, err)
+	require.Equal(t, aLevel, gotLevel)
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, gotLevel))
+	gotLevel, err = Read(&buffer)
+	require.NoError(t, err)
+	require.Equal(t, aLevel, gotLevel)
+
+	aGameBoard, listSprite, err := gotLevel.NewBoard(gameboard.WithRandom(random.NewSeeded(1)))
+	require.NoError(t, err)
+	require.Len(t, listSprite, len(aLevel.Obstacles)+2+aLevel.CandyCount)
+	require.Equal(t, []common.SnakeID{0, 1}, aGameBoard.SnakeIDs())
+}
+
+func TestFromSnapshot(t *testing.T) {
+	board := snapshot.Board{
+		Size:  common.Size{Width: 3, Height: 1},
+		Cells: []string{"S.*"},
+		Snake: snapshot.Snake{
+			Body:      []common.Position{{X: 0, Y: 0}},
+			Direction: common.Right,
+		},
+		Candies: []snapshot.Candy{
+			{Alive: false, Position: common.Position{X: 1, Y: 0}},
+			{Alive: true, Position: common.Position{X: 2, Y: 0}},
+		},
+	}
+
+	// The eaten candy is left out
+	gotLevel, err := fromSnapshot(board, common.EdgePolicy{})
+	require.NoError(t, err)
+	require.Equal(t, []common.Sprite{{Value: '*', Position: common.Position{X: 2, Y: 0}}}, gotLevel.Candies)
+
+	// A level can't start without the snake 0
+	board.Cells = []string{"..S"}
+	board.Snake.Body = nil
+	board.Snakes = []snapshot.Snake{{
+		ID:        1,
+		Body:      []common.Position{{X: 2, Y: 0}},
+		Direction: common.Left,
+	}}
+	board.Candies = nil
+	_, err = fromSnapshot(board, common.EdgePolicy{})
+	require.ErrorIs(t, err, ErrInvalidLevel)
+}
@@ -0,0 +1,102 @@
+package random
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// Randomer is the random source interface
+type Randomer interface {
+	Intn(max int) (rnd int, err error)
+}
+
+// Stater is implemented by the random sources whose state can be saved and restored
+type Stater interface {
+	State() uint64
+	SetState(state uint64)
+}
+
+// Cloner is implemented by the random sources that can be copied,
+// the copy draws the same numbers as the source from then on
+type Cloner interface {
+	Clone() Randomer
+}
+
+// ErrInvalidMax is a custom error thrown when the upper bound is not positive
+var ErrInvalidMax = errors.New("the upper bound must be positive")
+
+// cryptoRandom draws numbers from crypto/rand
+type cryptoRandom struct{}
+
+// seededRandom is a splitmix64 generator: the same seed always
+// produces the same sequence
+type seededRandom struct {
+	state uint64
+}
+
+// NewCrypto returns a non reproducible random source
+func NewCrypto() Randomer {
+	return new(cryptoRandom)
+}
+
+// NewSeeded returns a reproducible random source
+func NewSeeded(seed int64) Randomer {
+	return &seededRandom{
+		state: uint64(seed),
+	}
+}
+
+// Intn returns a number in [0, max)
+func (aRandom *cryptoRandom) Intn(max int) (rnd int, err error) {
+	if max <= 0 {
+		return 0, ErrInvalidMax
+	}
+	aBig, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err == nil {
+		rnd = int(aBig.Int64())
+	}
+
+	return rnd, err
+}
+
+// Intn returns a number in [0, max)
+func (aRandom *seededRandom) Intn(max int) (rnd int, err error) {
+	if max <= 0 {
+		return 0, ErrInvalidMax
+	}
+	// Rejects the values above the largest multiple of max to avoid a modulo bias
+	bound := uint64(max)
+	limit := ^uint64(0) - ^uint64(0)%bound
+	value := aRandom.next()
+	for value >= limit {
+		value = aRandom.next()
+	}
+
+	return int(value % bound), nil
+}
+
+// State returns the generator state
+func (aRandom *seededRandom) State() uint64 {
+	return aRandom.state
+}
+
+// SetState restores a state returned by State
+func (aRandom *seededRandom) SetState(state uint64) {
+	aRandom.state = state
+}
+
+// Clone returns a generator at the same state
+func (aRandom *seededRandom) Clone() Randomer {
+	return &seededRandom{
+		state: aRandom.state,
+	}
+}
+
+func (aRandom *seededRandom) next() uint64 {
+	aRandom.state += 0x9e3779b97f4a7c15
+	z := aRandom.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
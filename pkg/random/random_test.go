@@ -0,0 +1,157 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandom_Intn(t *testing.T) {
+	type args struct {
+		max int
+	}
+	tests := []struct {
+		name        string
+		randomer    Randomer
+		args        args
+		wantRnd     int
+		wantErrType error
+		wantErr     bool
+	}{
+		{
+			name:     "TestCryptoMax1",
+			randomer: NewCrypto(),
+			args: args{
+				max: 1,
+			},
+			wantRnd: 0,
+			wantErr: false,
+		},
+		{
+			name:     "TestCryptoMax0",
+			randomer: NewCrypto(),
+			args: args{
+				max: 0,
+			},
+			wantErrType: ErrInvalidMax,
+			wantErr:     true,
+		},
+		{
+			name:     "TestSeededMax1",
+			randomer: NewSeeded(42),
+			args: args{
+				max: 1,
+			},
+			wantRnd: 0,
+			wantErr: false,
+		},
+		{
+			name:     "TestSeededMaxMinus1",
+			randomer: NewSeeded(42),
+			args: args{
+				max: -1,
+			},
+			wantErrType: ErrInvalidMax,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRnd, err := tt.randomer.Intn(tt.args.max)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+			}
+			require.Equal(t, tt.wantRnd, gotRnd)
+		})
+	}
+}
+
+func TestSeeded_Sequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		seed1     int64
+		seed2     int64
+		wantEqual bool
+	}{
+		{
+			name:      "TestSameSeed",
+			seed1:     7,
+			seed2:     7,
+			wantEqual: true,
+		},
+		{
+			name:      "TestOtherSeed",
+			seed1:     7,
+			seed2:     8,
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			random1 := NewSeeded(tt.seed1)
+			random2 := NewSeeded(tt.seed2)
+			gotEqual := true
+			for i := 0; i < 100; i++ {
+				rnd1, err := random1.Intn(1000)
+				require.NoError(t, err)
+				rnd2, err := random2.Intn(1000)
+				require.NoError(t, err)
+				require.True(t, rnd1 >= 0 && rnd1 < 1000)
+				gotEqual = gotEqual && rnd1 == rnd2
+			}
+			require.Equal(t, tt.wantEqual, gotEqual)
+		})
+	}
+}
+
+func TestSeeded_State(t *testing.T) {
+	aRandom := NewSeeded(21)
+	_, err := aRandom.Intn(10)
+	require.NoError(t, err)
+	aStater, ok := aRandom.(Stater)
+	require.True(t, ok)
+	state := aStater.State()
+	want, err := aRandom.Intn(1000)
+	require.NoError(t, err)
+
+	// Restoring the state replays the sequence
+	other := NewSeeded(0)
+	other.(Stater).SetState(state)
+	got, err := other.Intn(1000)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	_, ok = NewCrypto().(Stater)
+	require.False(t, ok)
+}
+
+func TestSeeded_Clone(t *testing.T) {
+	aRandom := NewSeeded(21)
+	_, err := aRandom.Intn(10)
+	require.NoError(t, err)
+	aClone := aRandom.(Cloner).Clone()
+
+	// The clone draws the same numbers without moving the source
+	for i := 0; i < 10; i++ {
+		want, err := aRandom.Intn(1000)
+		require.NoError(t, err)
+		got, err := aClone.Intn(1000)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	_, err = aClone.Intn(1000)
+	require.NoError(t, err)
+	require.NotEqual(t, aRandom.(Stater).State(), aClone.(Stater).State())
+
+	_, ok := NewCrypto().(Cloner)
+	require.False(t, ok)
+}
+
+func TestNew(t *testing.T) {
+	var wantCrypto *cryptoRandom
+	require.IsType(t, wantCrypto, NewCrypto())
+	var wantSeeded *seededRandom
+	require.IsType(t, wantSeeded, NewSeeded(0))
+}
@@ -0,0 +1,351 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// Version is the version of the replay file format written by Write,
+// a replay of another version is rejected. It goes up with every field
+// added, an older replay would be played with other rules:
+//
+//	1 the seed, the size, the start of the snake and the moves
+//	2 the edges
+//	3 the candy rule
+//	4 the candy kinds
+//	5 the obstacles
+//	6 several snakes and the head-on rule
+//	7 the candies placed at start and the win condition
+//	8 the input depth, and the head, game over reason and board at the end
+const Version = 8
+
+// Defines custom errors
+var (
+	ErrUnsupportedVersion = errors.New("unsupported replay version")
+	ErrInvalidDirection   = errors.New("invalid direction")
+	ErrScoreMismatch      = errors.New("the replay score doesn't match the simulation")
+	ErrRoundMismatch      = errors.New("the replay rounds don't match the simulation")
+	ErrBoardMismatch      = errors.New("the replay board doesn't match the simulation")
+	ErrHeadMismatch       = errors.New("the replay head doesn't match the simulation")
+	ErrGameOverMismatch   = errors.New("the replay game over reason doesn't match the simulation")
+)
+
+// Move is a direction change requested for a snake before the given round is played
+type Move struct {
+	Round     int              `json:"round"`
+	Direction common.Direction `json:"direction"`
+	Snake     common.SnakeID   `json:"snake,omitempty"`
+}
+
+// Start is where a snake other than the snake 0 starts
+type Start struct {
+	Position  common.Position  `json:"position"`
+	Direction common.Direction `json:"direction"`
+}
+
+// Replay holds everything needed to play a game again. Head, GameOver
+// and Cells are the state of the game at the end, checked by Verify
+type Replay struct {
+	Version        int                   `json:"version"`
+	Seed           int64                 `json:"seed"`
+	Size           common.Size           `json:"size"`
+	Edges          common.EdgePolicy     `json:"edges"`
+	Candies        common.CandyRule      `json:"candies"`
+	CandyKinds     candy.Registry        `json:"candyKinds,omitempty"`
+	Obstacles      []common.Position     `json:"obstacles,omitempty"`
+	StartCandies   []common.Sprite       `json:"startCandies,omitempty"`
+	Win            common.WinCondition   `json:"win,omitempty"`
+	SnakePosition  common.Position       `json:"snakePosition"`
+	SnakeDirection common.Direction      `json:"snakeDirection"`
+	Snakes         []Start               `json:"snakes,omitempty"`
+	HeadOn         common.HeadOnRule     `json:"headOn,omitempty"`
+	InputDepth     int                   `json:"inputDepth"`
+	Moves          []Move                `json:"moves"`
+	Rounds         int                   `json:"rounds"`
+	Score          int                   `json:"score"`
+	Head           common.Position       `json:"head"`
+	GameOver       common.GameOverReason `json:"gameOver,omitempty"`
+	Cells          []string              `json:"cells"`
+}
+
+// Recorder is a GameStater recording the direction changes of a single game.
+// Every option changing how the game plays is recorded but the effect rule,
+// a game using WithEffectRule is played again with the same option
+type Recorder struct {
+	gamestate.GameStater
+	replay Replay
+}
+
+// NewRecorder returns a Recorder wrapping a game seeded with seed
+func NewRecorder(seed int64, options ...gamestate.Option) *Recorder {
+	options = append(options, gamestate.WithSeed(seed))
+	return &Recorder{
+		GameStater: gamestate.New(options...),
+		replay: Replay{
+			Version: Version,
+			Seed:    seed,
+		},
+	}
+}
+
+// InitBoard starts recording a new game on a board of size,
+// its candies are drawn from the seed again. The size recorded is the
+// one of the board, the size of WithDifficulty for a zero size
+func (aRecorder *Recorder) InitBoard(size common.Size) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aRecorder.replay.Moves = nil
+	if err = aRecorder.GameStater.InitBoard(size); err != nil {
+		return err
+	}
+	aRecorder.replay.Size = aRecorder.BoardSize()
+	return nil
+}
+
+// CreateObjects records the edge policy, the candy rules, the obstacles,
+// the candies placed at start, the win condition, the input depth and
+// where the snakes start
+func (aRecorder *Recorder) CreateObjects() (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	listSprite, err = aRecorder.GameStater.CreateObjects()
+	if err != nil {
+		return listSprite, err
+	}
+	aRecorder.replay.Edges = aRecorder.EdgePolicy()
+	aRecorder.replay.Candies = aRecorder.CandyRule()
+	aRecorder.replay.CandyKinds = aRecorder.CandyKinds()
+	aRecorder.replay.Obstacles = aRecorder.Obstacles()
+	aRecorder.replay.StartCandies = aRecorder.StartCandies()
+	aRecorder.replay.Win = aRecorder.WinCondition()
+	aRecorder.replay.HeadOn = aRecorder.HeadOnRule()
+	aRecorder.replay.InputDepth = aRecorder.InputDepth()
+	aRecorder.replay.Snakes = nil
+	for _, id := range aRecorder.SnakeIDs() {
+		if id == 0 {
+			continue
+		}
+		state, err := aRecorder.SnakeState(id)
+		if err != nil {
+			return listSprite, err
+		}
+		aRecorder.replay.Snakes = append(aRecorder.replay.Snakes, Start{
+			Position:  state.Body[len(state.Body)-1],
+			Direction: state.Direction,
+		})
+	}
+	if aRecorder.replay.SnakePosition, err = aRecorder.SnakePosition(); err != nil {
+		return listSprite, err
+	}
+	aRecorder.replay.SnakeDirection, err = aRecorder.SnakeDirection()
+	return listSprite, err
+}
+
+// MoveLeft records the move then forwards it
+func (aRecorder *Recorder) MoveLeft() {
+	aRecorder.record(0, common.Left)
+	aRecorder.GameStater.MoveLeft()
+}
+
+// MoveRight records the move then forwards it
+func (aRecorder *Recorder) MoveRight() {
+	aRecorder.record(0, common.Right)
+	aRecorder.GameStater.MoveRight()
+}
+
+// MoveDown records the move then forwards it
+func (aRecorder *Recorder) MoveDown() {
+	aRecorder.record(0, common.Down)
+	aRecorder.GameStater.MoveDown()
+}
+
+// MoveUp records the move then forwards it
+func (aRecorder *Recorder) MoveUp() {
+	aRecorder.record(0, common.Up)
+	aRecorder.GameStater.MoveUp()
+}
+
+// Steer records the move then forwards it
+func (aRecorder *Recorder) Steer(id common.SnakeID, direction common.Direction) {
+	aRecorder.record(id, direction)
+	aRecorder.GameStater.Steer(id, direction)
+}
+
+func (aRecorder *Recorder) record(id common.SnakeID, direction common.Direction) {
+	aRecorder.replay.Moves = append(aRecorder.replay.Moves, Move{
+		Round:     aRecorder.Round(),
+		Direction: direction,
+		Snake:     id,
+	})
+}
+
+// Replay returns the game recorded so far with its current score and board
+func (aRecorder *Recorder) Replay() Replay {
+	aReplay := aRecorder.replay
+	aReplay.Moves = append([]Move(nil), aRecorder.replay.Moves...)
+	aReplay.Rounds = aRecorder.Round()
+	aReplay.Score = aRecorder.Score()
+	aReplay.Head, _ = aRecorder.SnakePosition()
+	aReplay.GameOver = aRecorder.GameOverReason()
+	aReplay.Cells = gamestate.Cells(aRecorder)
+	return aReplay
+}
+
+// Play simulates the replay, onRound receives the sprites of the
+// initial board then those of every round. It returns the game at the
+// end of the replay. options are added to the ones of the replay, they
+// give again what can't be recorded like WithEffectRule
+func Play(aReplay Replay, onRound func(listSprite []common.Sprite), options ...gamestate.Option) (
+	game gamestate.GameStater, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aReplay.Version != Version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, aReplay.Version)
+	}
+
+	options = append([]gamestate.Option{
+		gamestate.WithSeed(aReplay.Seed),
+		gamestate.WithEdgePolicy(aReplay.Edges),
+		gamestate.WithCandyRule(aReplay.Candies),
+		gamestate.WithSnakeStart(aReplay.SnakePosition, aReplay.SnakeDirection),
+		gamestate.WithObstacles(aReplay.Obstacles...),
+		gamestate.WithHeadOnRule(aReplay.HeadOn),
+		gamestate.WithCandies(aReplay.StartCandies...),
+		gamestate.WithWinCondition(aReplay.Win),
+		gamestate.WithInputDepth(aReplay.InputDepth),
+	}, options...)
+	for _, start := range aReplay.Snakes {
+		options = append(options, gamestate.WithSnake(start.Position, start.Direction))
+	}
+	if aReplay.CandyKinds != nil {
+		options = append(options, gamestate.WithCandyKinds(aReplay.CandyKinds))
+	}
+	game = gamestate.New(options...)
+	if err = game.InitBoard(aReplay.Size); err != nil {
+		return game, err
+	}
+	listSprite, err := game.CreateObjects()
+	if err != nil {
+		return game, err
+	}
+	notify(onRound, listSprite)
+	game.Start()
+
+	moves := aReplay.Moves
+	for game.GameInProgress() && game.Round() < aReplay.Rounds {
+		for len(moves) > 0 && moves[0].Round <= game.Round() {
+			if err = move(game, moves[0].Snake, moves[0].Direction); err != nil {
+				return game, err
+			}
+			moves = moves[1:]
+		}
+		if listSprite, err = game.Play(); err != nil {
+			return game, err
+		}
+		notify(onRound, listSprite)
+	}
+
+	return game, nil
+}
+
+// Verify checks the score, the rounds, the head, the game over reason
+// and the board claimed by the replay against a simulation.
+// options are passed to Play
+func Verify(aReplay Replay, options ...gamestate.Option) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	game, err := Play(aReplay, nil, options...)
+	if err != nil {
+		return err
+	}
+	if game.Round() != aReplay.Rounds {
+		return fmt.Errorf("%w: claimed %d, simulated %d", ErrRoundMismatch, aReplay.Rounds, game.Round())
+	}
+	if game.Score() != aReplay.Score {
+		return fmt.Errorf("%w: claimed %d, simulated %d", ErrScoreMismatch, aReplay.Score, game.Score())
+	}
+	if head, _ := game.SnakePosition(); head != aReplay.Head {
+		return fmt.Errorf("%w: claimed %v, simulated %v", ErrHeadMismatch, aReplay.Head, head)
+	}
+	if game.GameOverReason() != aReplay.GameOver {
+		return fmt.Errorf("%w: claimed %v, simulated %v", ErrGameOverMismatch, aReplay.GameOver, game.GameOverReason())
+	}
+	if cells := gamestate.Cells(game); !equalRows(cells, aReplay.Cells) {
+		return fmt.Errorf("%w: claimed %q, simulated %q", ErrBoardMismatch, aReplay.Cells, cells)
+	}
+
+	return nil
+}
+
+// Write encodes the replay as JSON
+func Write(w io.Writer, aReplay Replay) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(aReplay)
+}
+
+// Read decodes a replay written by Write
+func Read(r io.Reader) (aReplay Replay, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if err = json.NewDecoder(r).Decode(&aReplay); err != nil {
+		return aReplay, err
+	}
+	if aReplay.Version != Version {
+		return aReplay, fmt.Errorf("%w: %d", ErrUnsupportedVersion, aReplay.Version)
+	}
+
+	return aReplay, nil
+}
+
+func move(game gamestate.GameStater, id common.SnakeID, direction common.Direction) error {
+	if id != 0 {
+		switch direction {
+		case common.Left, common.Right, common.Up, common.Down:
+			game.Steer(id, direction)
+			return nil
+		}
+		return ErrInvalidDirection
+	}
+	switch direction {
+	case common.Left:
+		game.MoveLeft()
+	case common.Right:
+		game.MoveRight()
+	case common.Up:
+		game.MoveUp()
+	case common.Down:
+		game.MoveDown()
+	default:
+		return ErrInvalidDirection
+	}
+
+	return nil
+}
+
+func equalRows(rows, other []string) bool {
+	if len(rows) != len(other) {
+		return false
+	}
+	for i := range rows {
+		if rows[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func notify(onRound func(listSprite []common.Sprite), listSprite []common.Sprite) {
+	if onRound != nil {
+		onRound(listSprite)
+	}
+}
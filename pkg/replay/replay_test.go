@@ -0,0 +1,357 @@
+package replay
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/candy"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/difficulty"
+	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+// record plays a serpentine game: 5 rounds right then 1 round down.
+// A snake 1 zigzags down and right
+func record(t *testing.T, seed int64, options ...gamestate.Option) (aReplay Replay, stream []common.Sprite) {
+	return recordWith(t, NewRecorder(seed, options...))
+}
+
+// recordWith plays the serpentine game of record on aRecorder
+func recordWith(t *testing.T, aRecorder *Recorder) (aReplay Replay, stream []common.Sprite) {
+	require.NoError(t, aRecorder.InitBoard(common.Size{
+		Width:  5,
+		Height: 5,
+	}))
+	listSprite, err := aRecorder.CreateObjects()
+	require.NoError(t, err)
+	stream = append(stream, listSprite...)
+	aRecorder.Start()
+	for aRecorder.GameInProgress() && aRecorder.Round() < 300 {
+		switch aRecorder.Round() % 6 {
+		case 4:
+			aRecorder.MoveDown()
+		case 5:
+			aRecorder.MoveRight()
+		}
+		if len(aRecorder.SnakeIDs()) > 1 {
+			switch aRecorder.Round() % 4 {
+			case 1:
+				aRecorder.Steer(1, common.Right)
+			case 3:
+				aRecorder.Steer(1, common.Down)
+			}
+		}
+		listSprite, err = aRecorder.Play()
+		require.NoError(t, err)
+		stream = append(stream, listSprite...)
+	}
+
+	return aRecorder.Replay(), stream
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	aReplay, _ := record(t, 5)
+	require.Equal(t, Version, aReplay.Version)
+	require.Equal(t, int64(5), aReplay.Seed)
+	require.Equal(t, common.Position{X: 2, Y: 2}, aReplay.SnakePosition)
+	require.Equal(t, common.Right, aReplay.SnakeDirection)
+	require.NotEmpty(t, aReplay.Moves)
+	require.Equal(t, Move{Round: 4, Direction: common.Down}, aReplay.Moves[0])
+	require.True(t, aReplay.Rounds > 0)
+}
+
+func TestRecorder_Reuse(t *testing.T) {
+	// Each game recorded starts from the seed again
+	aRecorder := NewRecorder(42)
+	first, firstStream := recordWith(t, aRecorder)
+	second, secondStream := recordWith(t, aRecorder)
+	require.NoError(t, Verify(first))
+	require.NoError(t, Verify(second))
+	require.Equal(t, first, second)
+	require.Equal(t, firstStream, secondStream)
+}
+
+func TestRecorder_Level(t *testing.T) {
+	// The candies placed at start and the win condition are replayed
+	start := common.Sprite{Value: '*', Position: common.Position{X: 4, Y: 2}}
+	win := common.WinCondition{Goal: common.ReachScore, Target: 2}
+	for seed := int64(1); seed <= 5; seed++ {
+		aRecorder := NewRecorder(seed, gamestate.WithCandies(start), gamestate.WithWinCondition(win))
+		aReplay, _ := recordWith(t, aRecorder)
+		require.Equal(t, []common.Sprite{start}, aReplay.StartCandies)
+		require.Equal(t, win, aReplay.Win)
+		require.NoError(t, Verify(aReplay))
+		game, err := Play(aReplay, nil)
+		require.NoError(t, err)
+		require.Equal(t, aRecorder.Outcome(), game.Outcome())
+	}
+}
+
+func TestRecorder_Difficulty(t *testing.T) {
+	// The board takes the size of the level
+	aRecorder := NewRecorder(3, gamestate.WithDifficulty(difficulty.Hard))
+	require.NoError(t, aRecorder.InitBoard(common.Size{}))
+	_, err := aRecorder.CreateObjects()
+	require.NoError(t, err)
+	aRecorder.Start()
+	for aRecorder.GameInProgress() && aRecorder.Round() < 100 {
+		if aRecorder.Round()%10 == 5 {
+			aRecorder.MoveDown()
+		}
+		_, err = aRecorder.Play()
+		require.NoError(t, err)
+	}
+	aReplay := aRecorder.Replay()
+	require.Equal(t, difficulty.Hard.Size, aReplay.Size)
+	require.Equal(t, difficulty.Hard.Edges, aReplay.Edges)
+	require.NoError(t, Verify(aReplay))
+}
+
+func TestRecorder_InputDepth(t *testing.T) {
+	// Two moves are queued every few rounds, only the first one is kept
+	aRecorder := NewRecorder(7, gamestate.WithInputDepth(1))
+	require.NoError(t, aRecorder.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := aRecorder.CreateObjects()
+	require.NoError(t, err)
+	aRecorder.Start()
+	for aRecorder.GameInProgress() && aRecorder.Round() < 60 {
+		switch aRecorder.Round() % 6 {
+		case 0:
+			aRecorder.MoveDown()
+			aRecorder.MoveLeft()
+		case 3:
+			aRecorder.MoveRight()
+			aRecorder.MoveUp()
+		}
+		_, err = aRecorder.Play()
+		require.NoError(t, err)
+	}
+	aReplay := aRecorder.Replay()
+	require.Equal(t, 1, aReplay.InputDepth)
+	require.NoError(t, Verify(aReplay))
+
+	// With the default depth the second moves are played
+	aReplay.InputDepth = gamestate.DefaultInputDepth
+	require.Error(t, Verify(aReplay))
+}
+
+func TestPlay(t *testing.T) {
+	tests := []struct {
+		name       string
+		seed       int64
+		options    []gamestate.Option
+		wantSnakes int
+	}{
+		{
+			name: "TestSeed1",
+			seed: 1,
+		},
+		{
+			name:    "TestSeed1Walls",
+			seed:    1,
+			options: []gamestate.Option{gamestate.WithEdgePolicy(common.UniformEdges(common.WallEdge))},
+		},
+		{
+			name:    "TestSeed1Bounce",
+			seed:    1,
+			options: []gamestate.Option{gamestate.WithEdgePolicy(common.UniformEdges(common.BounceEdge))},
+		},
+		{
+			name: "TestSeed1CandyKinds",
+			seed: 1,
+			options: []gamestate.Option{
+				gamestate.WithCandyRule(common.CandyRule{Count: 3}),
+				gamestate.WithCandyKinds(candyKinds()),
+			},
+		},
+		{
+			name:    "TestSeed1Obstacles",
+			seed:    1,
+			options: []gamestate.Option{gamestate.WithObstacles(common.Position{X: 1, Y: 3}, common.Position{X: 4, Y: 4})},
+		},
+		{
+			name: "TestSeed1TwoSnakes",
+			seed: 1,
+			options: []gamestate.Option{
+				gamestate.WithSnake(common.Position{X: 0, Y: 0}, common.Down),
+				gamestate.WithHeadOnRule(common.HeadOnLongerWins),
+			},
+			wantSnakes: 1,
+		},
+		{
+			name: "TestSeed2",
+			seed: 2,
+		},
+		{
+			name: "TestSeed3",
+			seed: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aReplay, wantStream := record(t, tt.seed, tt.options...)
+			var gotStream []common.Sprite
+			game, err := Play(aReplay, func(listSprite []common.Sprite) {
+				gotStream = append(gotStream, listSprite...)
+			})
+			require.NoError(t, err)
+			require.Equal(t, wantStream, gotStream)
+			require.Equal(t, aReplay.Score, game.Score())
+			require.Equal(t, aReplay.Rounds, game.Round())
+			require.Len(t, aReplay.Snakes, tt.wantSnakes)
+		})
+	}
+}
+
+// candyKinds returns the default kinds, every one of them can be spawned
+func candyKinds() candy.Registry {
+	aRegistry := candy.DefaultRegistry()
+	for kind, properties := range aRegistry {
+		properties.Weight = 1
+		aRegistry[kind] = properties
+	}
+
+	return aRegistry
+}
+
+func TestVerify(t *testing.T) {
+	aReplay, _ := record(t, 4)
+	tests := []struct {
+		name        string
+		change      func(aReplay *Replay)
+		wantErrType error
+		wantErr     bool
+	}{
+		{
+			name:    "TestGenuine",
+			change:  func(aReplay *Replay) {},
+			wantErr: false,
+		},
+		{
+			name: "TestCheatedScore",
+			change: func(aReplay *Replay) {
+				aReplay.Score += 10
+			},
+			wantErrType: ErrScoreMismatch,
+			wantErr:     true,
+		},
+		{
+			name: "TestCheatedRounds",
+			change: func(aReplay *Replay) {
+				aReplay.Rounds += 10000
+			},
+			wantErrType: ErrRoundMismatch,
+			wantErr:     true,
+		},
+		{
+			name: "TestCheatedHead",
+			change: func(aReplay *Replay) {
+				aReplay.Head.X++
+			},
+			wantErrType: ErrHeadMismatch,
+			wantErr:     true,
+		},
+		{
+			name: "TestCheatedGameOver",
+			change: func(aReplay *Replay) {
+				aReplay.GameOver = common.ObstacleCollision
+			},
+			wantErrType: ErrGameOverMismatch,
+			wantErr:     true,
+		},
+		{
+			name: "TestCheatedBoard",
+			change: func(aReplay *Replay) {
+				aReplay.Cells = append([]string{"#####"}, aReplay.Cells[1:]...)
+			},
+			wantErrType: ErrBoardMismatch,
+			wantErr:     true,
+		},
+		{
+			name: "TestInvalidDirection",
+			change: func(aReplay *Replay) {
+				aReplay.Moves = append([]Move{{Direction: testdata.Direction0_0}}, aReplay.Moves...)
+			},
+			wantErrType: ErrInvalidDirection,
+			wantErr:     true,
+		},
+		{
+			name: "TestUnsupportedVersion",
+			change: func(aReplay *Replay) {
+				aReplay.Version = Version + 1
+			},
+			wantErrType: ErrUnsupportedVersion,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := aReplay
+			changed.Moves = append([]Move(nil), aReplay.Moves...)
+			tt.change(&changed)
+			err := Verify(changed)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+			}
+		})
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	aReplay, _ := record(t, 9)
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, aReplay))
+	require.Contains(t, buffer.String(), fmt.Sprintf(`"version": %d`, Version))
+	gotReplay, err := Read(&buffer)
+	require.NoError(t, err)
+	require.Equal(t, aReplay, gotReplay)
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantErrType error
+		wantErr     bool
+	}{
+		{
+			name:    "TestCurrentVersion",
+			input:   fmt.Sprintf(`{"version": %d, "seed": 3}`, Version),
+			wantErr: false,
+		},
+		{
+			name:        "TestOldVersion", // The fields added since are missing
+			input:       `{"version": 1, "seed": 3}`,
+			wantErrType: ErrUnsupportedVersion,
+			wantErr:     true,
+		},
+		{
+			name:        "TestFutureVersion",
+			input:       fmt.Sprintf(`{"version": %d, "seed": 3}`, Version+1),
+			wantErrType: ErrUnsupportedVersion,
+			wantErr:     true,
+		},
+		{
+			name:    "TestNotJSON",
+			input:   `version 1`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input))
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if tt.wantErrType != nil {
+				require.ErrorIs(t, err, tt.wantErrType)
+			}
+		})
+	}
+}
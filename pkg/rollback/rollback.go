@@ -0,0 +1,263 @@
+// Package rollback plays a deterministic game between peers. Every peer
+// runs the same simulation and sends the inputs of its snakes to the
+// others. A Session doesn't wait for the inputs of the remote snakes:
+// it predicts they keep going, and once a late input proves it wrong it
+// plays again the rounds since then from a saved state and returns the
+// cells the correction changed.
+//
+// With a maximum prediction of 0 the session waits for every input
+// before playing a round, that is plain lockstep.
+package rollback
+
+import (
+	"errors"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// DefaultMaxPrediction is the number of rounds a session plays ahead of its inputs
+const DefaultMaxPrediction = 8
+
+// Defines custom errors
+var (
+	ErrNotDeterministic = errors.New("the game isn't deterministic, seed it")
+	ErrUnknownSnake     = errors.New("there is no snake with this ID")
+	ErrConfirmedRound   = errors.New("the round is already confirmed")
+	ErrInputChanged     = errors.New("the input differs from the one received")
+	ErrTooFarAhead      = errors.New("too many rounds played without the inputs")
+)
+
+// Input is the direction of a snake before the given round is played.
+// The zero direction keeps the snake going, each snake has one input
+// per round
+type Input struct {
+	Round     int              `json:"round"`
+	Snake     common.SnakeID   `json:"snake"`
+	Direction common.Direction `json:"direction"`
+}
+
+// Session plays a game with the inputs of every snake, predicting
+// the inputs not received yet. It isn't safe for concurrent use
+type Session struct {
+	game          gamestate.GameStater
+	maxPrediction int
+	// next is the first round without an input, per snake
+	next map[common.SnakeID]int
+	// confirmed is the last round with the input of every snake
+	confirmed int
+	// saved holds a copy of the game after each round not confirmed yet
+	saved  map[int]gamestate.GameStater
+	inputs map[int]map[common.SnakeID]common.Direction
+	// mispredicted is the first round played with a wrong prediction, 0 if none
+	mispredicted int
+}
+
+// Option configures a Session created by New
+type Option func(aSession *Session)
+
+// WithMaxPrediction sets how many rounds the session may play ahead
+// of the last round confirmed, 0 waits for every input
+func WithMaxPrediction(rounds int) Option {
+	return func(aSession *Session) {
+		if rounds < 0 {
+			rounds = 0
+		}
+		aSession.maxPrediction = rounds
+	}
+}
+
+// New returns a session playing game from its current round. The game
+// must be Deterministic and its snakes created, it is owned by the
+// session from then on: use Game to read it
+func New(game gamestate.GameStater, options ...Option) (aSession *Session, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if !game.Deterministic() {
+		return nil, ErrNotDeterministic
+	}
+	saved, err := gamestate.Clone(game)
+	if err != nil {
+		return nil, err
+	}
+	round := game.Round()
+	aSession = &Session{
+		game:          game,
+		maxPrediction: DefaultMaxPrediction,
+		next:          make(map[common.SnakeID]int),
+		confirmed:     round,
+		saved:         map[int]gamestate.GameStater{round: saved},
+		inputs:        make(map[int]map[common.SnakeID]common.Direction),
+	}
+	for _, id := range game.SnakeIDs() {
+		aSession.next[id] = round + 1
+	}
+	for _, option := range options {
+		option(aSession)
+	}
+
+	return aSession, nil
+}
+
+// Game returns the game as played so far. A correction replaces it,
+// so the game returned must not be kept across calls to Advance or Reconcile
+func (aSession *Session) Game() gamestate.GameStater {
+	return aSession.game
+}
+
+// Round returns the last round played
+func (aSession *Session) Round() int {
+	return aSession.game.Round()
+}
+
+// Confirmed returns the last round with the input of every snake
+func (aSession *Session) Confirmed() int {
+	return aSession.confirmed
+}
+
+// AddInput records the input of a snake, local or remote. An input for
+// a round already played with another prediction is applied by the
+// next Reconcile or Advance
+func (aSession *Session) AddInput(anInput Input) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	next, ok := aSession.next[anInput.Snake]
+	if !ok {
+		return ErrUnknownSnake
+	}
+	if direction, ok := aSession.inputs[anInput.Round][anInput.Snake]; ok {
+		if direction != anInput.Direction {
+			return ErrInputChanged
+		}
+		return nil
+	}
+	if anInput.Round <= aSession.confirmed || anInput.Round < next {
+		return ErrConfirmedRound
+	}
+
+	roundInputs := aSession.inputs[anInput.Round]
+	if roundInputs == nil {
+		roundInputs = make(map[common.SnakeID]common.Direction)
+		aSession.inputs[anInput.Round] = roundInputs
+	}
+	roundInputs[anInput.Snake] = anInput.Direction
+	// The round was played keeping the snake going
+	if anInput.Round <= aSession.Round() && anInput.Direction != (common.Direction{}) &&
+		(aSession.mispredicted == 0 || anInput.Round < aSession.mispredicted) {
+		aSession.mispredicted = anInput.Round
+	}
+
+	for {
+		if _, ok := aSession.inputs[next][anInput.Snake]; !ok {
+			break
+		}
+		next++
+	}
+	aSession.next[anInput.Snake] = next
+	aSession.confirmed = next - 1
+	for _, snakeNext := range aSession.next {
+		if snakeNext-1 < aSession.confirmed {
+			aSession.confirmed = snakeNext - 1
+		}
+	}
+
+	return nil
+}
+
+// Reconcile plays again the rounds since the first mispredicted one
+// with the inputs received. It returns the cells that changed between
+// the game as it was and the corrected game
+func (aSession *Session) Reconcile() (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if aSession.mispredicted == 0 {
+		aSession.prune()
+		return nil, nil
+	}
+
+	from, last := aSession.mispredicted, aSession.Round()
+	before := gamestate.Cells(aSession.game)
+	game, err := gamestate.Clone(aSession.saved[from-1])
+	if err != nil {
+		return nil, err
+	}
+	aSession.game = game
+	aSession.mispredicted = 0
+	for round := from; round <= last; round++ {
+		delete(aSession.saved, round)
+	}
+	for aSession.Round() < last && game.GameInProgress() {
+		if _, err = aSession.play(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, aChange := range common.NewBoardDiff(aSession.Round(), before, gamestate.Cells(game)).Changes {
+		listSprite = append(listSprite, common.Sprite{Value: aChange.After, Position: aChange.Position})
+	}
+	aSession.prune()
+
+	return listSprite, nil
+}
+
+// Advance plays the next round once the mispredicted rounds are corrected.
+// It returns the corrections followed by the cells changed by the round.
+// ErrTooFarAhead asks to wait for the inputs of the remote snakes
+func (aSession *Session) Advance() (listSprite []common.Sprite, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	listSprite, err = aSession.Reconcile()
+	if err != nil {
+		return listSprite, err
+	}
+	if !aSession.game.GameInProgress() {
+		return listSprite, nil
+	}
+	if aSession.Round()+1-aSession.confirmed > aSession.maxPrediction {
+		return listSprite, ErrTooFarAhead
+	}
+	roundSprites, err := aSession.play()
+	listSprite = append(listSprite, roundSprites...)
+
+	return listSprite, err
+}
+
+// play steers the snakes with the known inputs of the next round,
+// plays it and saves the game
+func (aSession *Session) play() (listSprite []common.Sprite, err error) {
+	round := aSession.Round() + 1
+	for id, direction := range aSession.inputs[round] {
+		if direction != (common.Direction{}) {
+			aSession.game.Steer(id, direction)
+		}
+	}
+	if listSprite, err = aSession.game.Play(); err != nil {
+		return listSprite, err
+	}
+	saved, err := gamestate.Clone(aSession.game)
+	if err != nil {
+		return listSprite, err
+	}
+	aSession.saved[aSession.Round()] = saved
+
+	return listSprite, nil
+}
+
+// prune forgets the saved games and the inputs of the confirmed rounds,
+// a correction starts after the last one
+func (aSession *Session) prune() {
+	base := aSession.confirmed
+	if round := aSession.Round(); round < base {
+		base = round
+	}
+	for round := range aSession.saved {
+		if round < base {
+			delete(aSession.saved, round)
+		}
+	}
+	for round := range aSession.inputs {
+		if round <= base {
+			delete(aSession.inputs, round)
+		}
+	}
+}
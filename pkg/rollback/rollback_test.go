@@ -0,0 +1,182 @@
+package rollback
+
+import (
+	"testing"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newGame returns a started game with the snake 0 at the top
+// and the snake 1 at the bottom, both heading right
+func newGame(t *testing.T, options ...gamestate.Option) gamestate.GameStater {
+	options = append([]gamestate.Option{
+		gamestate.WithSnakeStart(common.Position{X: 2, Y: 2}, common.Right),
+		gamestate.WithSnake(common.Position{X: 2, Y: 8}, common.Right),
+	}, options...)
+	game := gamestate.New(options...)
+	require.NoError(t, game.InitBoard(common.Size{Width: 12, Height: 12}))
+	_, err := game.CreateObjects()
+	require.NoError(t, err)
+	game.Start()
+
+	return game
+}
+
+// board applies the sprites to rows
+func board(rows []string, listSprite []common.Sprite) []string {
+	cells := make([][]rune, len(rows))
+	for y, row := range rows {
+		cells[y] = []rune(row)
+	}
+	for _, sprite := range listSprite {
+		cells[sprite.Position.Y][sprite.Position.X] = sprite.Value
+	}
+	rows = make([]string, len(cells))
+	for y, row := range cells {
+		rows[y] = string(row)
+	}
+
+	return rows
+}
+
+func TestSession_Advance(t *testing.T) {
+	const rounds = 12
+	inputs := map[common.SnakeID]map[int]common.Direction{
+		0: {2: common.Down, 3: common.Right, 8: common.Up},
+		1: {3: common.Down, 5: common.Left, 9: common.Up},
+	}
+	// The snake 0 eats the candy at 7,3 in the round 6, a new one is drawn.
+	// The reference game gets every input in time
+	reference := newGame(t, gamestate.WithSeed(3))
+	for round := 1; round <= rounds; round++ {
+		for id := range inputs {
+			if direction, ok := inputs[id][round]; ok {
+				reference.Steer(id, direction)
+			}
+		}
+		_, err := reference.Play()
+		require.NoError(t, err)
+	}
+
+	// The inputs of the snake 1 arrive 3 rounds late
+	const delay = 3
+	aSession, err := New(newGame(t, gamestate.WithSeed(3)))
+	require.NoError(t, err)
+	rows := gamestate.Cells(aSession.Game())
+	addInput := func(id common.SnakeID, round int) {
+		require.NoError(t, aSession.AddInput(Input{Round: round, Snake: id, Direction: inputs[id][round]}))
+	}
+	for round := 1; round <= rounds; round++ {
+		addInput(0, round)
+		if round > delay {
+			addInput(1, round-delay)
+		}
+		listSprite, err := aSession.Advance()
+		require.NoError(t, err)
+		rows = board(rows, listSprite)
+		require.Equal(t, round, aSession.Round())
+		require.Equal(t, gamestate.Cells(aSession.Game()), rows)
+		require.LessOrEqual(t, len(aSession.saved), delay+2)
+	}
+	require.Equal(t, rounds-delay, aSession.Confirmed())
+	for round := rounds - delay + 1; round <= rounds; round++ {
+		addInput(1, round)
+	}
+	listSprite, err := aSession.Reconcile()
+	require.NoError(t, err)
+	rows = board(rows, listSprite)
+	require.Equal(t, rounds, aSession.Confirmed())
+
+	// The corrections end up with the game played in time
+	require.Equal(t, gamestate.Cells(reference), rows)
+	require.Equal(t, gamestate.Cells(reference), gamestate.Cells(aSession.Game()))
+	require.Equal(t, reference.Round(), aSession.Round())
+	require.Equal(t, reference.Score(), aSession.Game().Score())
+	for id := range inputs {
+		want, err := reference.SnakeState(id)
+		require.NoError(t, err)
+		got, err := aSession.Game().SnakeState(id)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	require.Len(t, aSession.saved, 1)
+	require.Empty(t, aSession.inputs)
+}
+
+func TestSession_Lockstep(t *testing.T) {
+	aSession, err := New(newGame(t, gamestate.WithSeed(3)), WithMaxPrediction(0))
+	require.NoError(t, err)
+
+	// A round is played once the inputs of every snake are received
+	_, err = aSession.Advance()
+	require.ErrorIs(t, err, ErrTooFarAhead)
+	require.NoError(t, aSession.AddInput(Input{Round: 1, Snake: 0, Direction: common.Down}))
+	_, err = aSession.Advance()
+	require.ErrorIs(t, err, ErrTooFarAhead)
+	require.NoError(t, aSession.AddInput(Input{Round: 1, Snake: 1}))
+	listSprite, err := aSession.Advance()
+	require.NoError(t, err)
+	require.NotEmpty(t, listSprite)
+	require.Equal(t, 1, aSession.Round())
+	direction, err := aSession.Game().SnakeDirection()
+	require.NoError(t, err)
+	require.Equal(t, common.Down, direction)
+	_, err = aSession.Advance()
+	require.ErrorIs(t, err, ErrTooFarAhead)
+}
+
+func TestSession_Errors(t *testing.T) {
+	_, err := New(newGame(t))
+	require.ErrorIs(t, err, ErrNotDeterministic)
+
+	aSession, err := New(newGame(t, gamestate.WithSeed(3)), WithMaxPrediction(2))
+	require.NoError(t, err)
+	require.NoError(t, aSession.AddInput(Input{Round: 1, Snake: 0, Direction: common.Down}))
+
+	tests := []struct {
+		name    string
+		anInput Input
+		wantErr error
+	}{
+		{
+			name:    "TestUnknownSnake",
+			anInput: Input{Round: 1, Snake: 2},
+			wantErr: ErrUnknownSnake,
+		},
+		{
+			name:    "TestStartRound",
+			anInput: Input{Round: 0, Snake: 1},
+			wantErr: ErrConfirmedRound,
+		},
+		{
+			name:    "TestChangedInput",
+			anInput: Input{Round: 1, Snake: 0, Direction: common.Up},
+			wantErr: ErrInputChanged,
+		},
+		{
+			name:    "TestSameInput",
+			anInput: Input{Round: 1, Snake: 0, Direction: common.Down},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := aSession.AddInput(tt.anInput)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	// The session plays ahead up to the maximum prediction
+	for round := 1; round <= 2; round++ {
+		_, err = aSession.Advance()
+		require.NoError(t, err)
+	}
+	_, err = aSession.Advance()
+	require.ErrorIs(t, err, ErrTooFarAhead)
+}
@@ -0,0 +1,32 @@
+package runner
+
+import "time"
+
+// Clock makes the tickers of a runner, tests replace it to tick on demand
+type Clock interface {
+	NewTicker(interval time.Duration) Ticker
+}
+
+// Ticker sends the time on C at each tick until it is stopped
+type Ticker interface {
+	C() <-chan time.Time
+	Reset(interval time.Duration)
+	Stop()
+}
+
+// SystemClock ticks with time.Ticker
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) NewTicker(interval time.Duration) Ticker {
+	return systemTicker{Ticker: time.NewTicker(interval)}
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (aTicker systemTicker) C() <-chan time.Time {
+	return aTicker.Ticker.C
+}
@@ -0,0 +1,381 @@
+// Package runner plays a game in real time. A Runner owns the game:
+// only its goroutine touches it, the other goroutines steer the snakes,
+// read the game through Do and receive the rounds played as frames.
+// Observers only receive the frames: a keyframe holding the whole board,
+// then the cells changed by each round, with a keyframe now and then
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/event"
+)
+
+// Defaults of a runner
+const (
+	DefaultInputBuffer      = 64 // inputs waiting for the runner goroutine
+	DefaultKeyframeInterval = 50 // rounds between the keyframes sent to the observers
+)
+
+// Defines custom errors
+var (
+	ErrRunning = errors.New("the runner is already running")
+	ErrStopped = errors.New("the runner is stopped")
+)
+
+// Input is a direction requested for a snake
+type Input struct {
+	Snake     common.SnakeID
+	Direction common.Direction
+}
+
+// Frame is a round played by the runner.
+// Events holds the events sent by the game since the previous frame.
+// Board is only set on the keyframes sent to the observers: it holds
+// the rows of the whole board after Round, Sprites are already in it
+type Frame struct {
+	Round          int
+	Board          []string
+	Sprites        []common.Sprite
+	Events         []event.Event
+	Score          int
+	GameInProgress bool
+	Outcome        common.Outcome
+}
+
+// Runner plays a game on the ticks of a clock
+type Runner struct {
+	game    gamestate.GameStater
+	clock   Clock
+	inputs  chan Input
+	calls   chan *call
+	done    chan struct{}
+	started int32
+
+	keyframeInterval int
+	// wake tells the runner goroutine an observer waits for its first keyframe
+	wake chan struct{}
+
+	mutex       sync.Mutex
+	subscribers map[int]chan Frame
+	observers   map[int]*observer
+	nextID      int
+	stopped     bool
+}
+
+// observer is the channel of an observer, keyframe is set
+// until it receives the whole board
+type observer struct {
+	frames   chan Frame
+	keyframe bool
+}
+
+// call is a function run on the runner goroutine, done is closed once it returns
+type call struct {
+	function func(game gamestate.GameStater) error
+	err      error
+	done     chan struct{}
+}
+
+// Option configures a Runner created by New
+type Option func(aRunner *Runner)
+
+// WithClock replaces SystemClock
+func WithClock(clock Clock) Option {
+	return func(aRunner *Runner) {
+		aRunner.clock = clock
+	}
+}
+
+// WithInputBuffer sets how many inputs can wait for the runner goroutine
+// before Steer blocks
+func WithInputBuffer(size int) Option {
+	return func(aRunner *Runner) {
+		aRunner.inputs = make(chan Input, size)
+	}
+}
+
+// WithKeyframeInterval sets every how many rounds the observers receive
+// the whole board, 0 only sends it to the observers which need it
+func WithKeyframeInterval(rounds int) Option {
+	return func(aRunner *Runner) {
+		if rounds < 0 {
+			rounds = 0
+		}
+		aRunner.keyframeInterval = rounds
+	}
+}
+
+// New returns a runner of game. The game must not be used
+// by other goroutines than the runner one once it runs
+func New(game gamestate.GameStater, options ...Option) *Runner {
+	aRunner := &Runner{
+		game:        game,
+		clock:       SystemClock,
+		inputs:      make(chan Input, DefaultInputBuffer),
+		calls:       make(chan *call),
+		done:        make(chan struct{}),
+		subscribers: make(map[int]chan Frame),
+		observers:   make(map[int]*observer),
+		wake:        make(chan struct{}, 1),
+
+		keyframeInterval: DefaultKeyframeInterval,
+	}
+	for _, option := range options {
+		option(aRunner)
+	}
+
+	return aRunner
+}
+
+// Run plays a round at each tick until ctx is done or a round fails.
+// The ticks follow the TickInterval of the game, nothing is played
+// while the game is over or paused.
+// Once Run has returned the runner is stopped: the frame channels
+// are closed and Steer and Do fail with ErrStopped
+func (aRunner *Runner) Run(ctx context.Context) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	if !atomic.CompareAndSwapInt32(&aRunner.started, 0, 1) {
+		return ErrRunning
+	}
+	defer aRunner.stop()
+
+	var events []event.Event
+	unsubscribe := aRunner.game.Subscribe(func(anEvent event.Event) {
+		events = append(events, anEvent)
+	})
+	defer unsubscribe()
+
+	interval := aRunner.game.TickInterval()
+	ticker := aRunner.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case anInput := <-aRunner.inputs:
+			aRunner.game.Steer(anInput.Snake, anInput.Direction)
+		case aCall := <-aRunner.calls:
+			aCall.err = aCall.function(aRunner.game)
+			close(aCall.done)
+		case <-aRunner.wake:
+			// The new observers get the board without waiting for a round
+			aRunner.observe(aRunner.frame(nil, nil), false)
+		case <-ticker.C():
+			// The inputs sent before the tick are taken in this round
+			aRunner.takeInputs()
+			if !aRunner.game.GameInProgress() || aRunner.game.Paused() {
+				continue
+			}
+			listSprite, err := aRunner.game.Play()
+			if err != nil {
+				return err
+			}
+			aFrame := aRunner.frame(listSprite, events)
+			aRunner.publish(aFrame)
+			aRunner.observe(aFrame, true)
+			events = nil
+
+			if speed := aRunner.game.TickInterval(); speed != interval {
+				interval = speed
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
+
+// frame returns the frame of the last round played
+func (aRunner *Runner) frame(listSprite []common.Sprite, events []event.Event) Frame {
+	return Frame{
+		Round:          aRunner.game.Round(),
+		Sprites:        listSprite,
+		Events:         events,
+		Score:          aRunner.game.Score(),
+		GameInProgress: aRunner.game.GameInProgress(),
+		Outcome:        aRunner.game.Outcome(),
+	}
+}
+
+// takeInputs steers the snakes with the inputs waiting
+func (aRunner *Runner) takeInputs() {
+	for {
+		select {
+		case anInput := <-aRunner.inputs:
+			aRunner.game.Steer(anInput.Snake, anInput.Direction)
+		default:
+			return
+		}
+	}
+}
+
+// Steer sends a direction for the snake id, it blocks while the input buffer is full
+func (aRunner *Runner) Steer(id common.SnakeID, direction common.Direction) error {
+	select {
+	case <-aRunner.done:
+		return ErrStopped
+	default:
+	}
+	select {
+	case aRunner.inputs <- Input{Snake: id, Direction: direction}:
+		return nil
+	case <-aRunner.done:
+		return ErrStopped
+	}
+}
+
+// Inputs returns the channel read by Steer, an input sent on it
+// once the runner is stopped is never read
+func (aRunner *Runner) Inputs() chan<- Input {
+	return aRunner.inputs
+}
+
+// Do runs function on the runner goroutine between two rounds and returns its error.
+// It is the way to read or change the game while it runs: pausing it, saving it,
+// starting a new game...
+func (aRunner *Runner) Do(ctx context.Context, function func(game gamestate.GameStater) error) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	aCall := &call{
+		function: function,
+		done:     make(chan struct{}),
+	}
+	select {
+	case aRunner.calls <- aCall:
+	case <-aRunner.done:
+		return ErrStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	// The runner goroutine always completes a call it has received
+	<-aCall.done
+
+	return aCall.err
+}
+
+// Subscribe returns a channel receiving the frames, buffer is its capacity.
+// A frame is dropped for a subscriber whose channel is full, so a slow
+// subscriber never delays the game. The channel is closed by unsubscribe
+// or once the runner is stopped
+func (aRunner *Runner) Subscribe(buffer int) (frames <-chan Frame, unsubscribe func()) {
+	channel := make(chan Frame, buffer)
+	aRunner.mutex.Lock()
+	defer aRunner.mutex.Unlock()
+	if aRunner.stopped {
+		close(channel)
+		return channel, func() {}
+	}
+	aRunner.nextID++
+	id := aRunner.nextID
+	aRunner.subscribers[id] = channel
+
+	return channel, func() {
+		aRunner.mutex.Lock()
+		defer aRunner.mutex.Unlock()
+		if channel, ok := aRunner.subscribers[id]; ok {
+			delete(aRunner.subscribers, id)
+			close(channel)
+		}
+	}
+}
+
+// Observe returns a channel receiving the game as it is played, buffer is
+// its capacity. The first frame is a keyframe holding the whole board,
+// the next ones hold the cells changed by each round and a keyframe is sent
+// every keyframe interval. An observer too slow to take a frame misses it,
+// the next frame it takes is a keyframe: it never delays the game and its
+// board is never wrong. The channel is closed by detach or once the runner
+// is stopped
+func (aRunner *Runner) Observe(buffer int) (frames <-chan Frame, detach func()) {
+	if buffer < 1 {
+		buffer = 1
+	}
+	anObserver := &observer{
+		frames:   make(chan Frame, buffer),
+		keyframe: true,
+	}
+	aRunner.mutex.Lock()
+	defer aRunner.mutex.Unlock()
+	if aRunner.stopped {
+		close(anObserver.frames)
+		return anObserver.frames, func() {}
+	}
+	aRunner.nextID++
+	id := aRunner.nextID
+	aRunner.observers[id] = anObserver
+	select {
+	case aRunner.wake <- struct{}{}:
+	default:
+	}
+
+	return anObserver.frames, func() {
+		aRunner.mutex.Lock()
+		defer aRunner.mutex.Unlock()
+		if anObserver, ok := aRunner.observers[id]; ok {
+			delete(aRunner.observers, id)
+			close(anObserver.frames)
+		}
+	}
+}
+
+// observe sends aFrame to the observers which have room for it, as a
+// keyframe to those which need one. When no round was played it is only
+// sent to them, every keyframe interval it is a keyframe for all
+func (aRunner *Runner) observe(aFrame Frame, played bool) {
+	aRunner.mutex.Lock()
+	defer aRunner.mutex.Unlock()
+	keyframe := played && aRunner.keyframeInterval > 0 && aFrame.Round%aRunner.keyframeInterval == 0
+	var board []string
+	for _, anObserver := range aRunner.observers {
+		observed := aFrame
+		if keyframe || anObserver.keyframe {
+			// Cells leaves the game as it is, unlike Snapshot
+			if board == nil {
+				board = gamestate.Cells(aRunner.game)
+			}
+			observed.Board = board
+		} else if !played {
+			continue
+		}
+		select {
+		case anObserver.frames <- observed:
+			anObserver.keyframe = false
+		default:
+			anObserver.keyframe = true
+		}
+	}
+}
+
+// publish sends aFrame to the subscribers which have room for it
+func (aRunner *Runner) publish(aFrame Frame) {
+	aRunner.mutex.Lock()
+	defer aRunner.mutex.Unlock()
+	for _, channel := range aRunner.subscribers {
+		select {
+		case channel <- aFrame:
+		default:
+		}
+	}
+}
+
+// stop closes the frame channels and releases the goroutines blocked in Steer or Do
+func (aRunner *Runner) stop() {
+	aRunner.mutex.Lock()
+	defer aRunner.mutex.Unlock()
+	aRunner.stopped = true
+	for id, channel := range aRunner.subscribers {
+		delete(aRunner.subscribers, id)
+		close(channel)
+	}
+	for id, anObserver := range aRunner.observers {
+		delete(aRunner.observers, id)
+		close(anObserver.frames)
+	}
+	close(aRunner.done)
+}
@@ -0,0 +1,338 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/difficulty"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/event"
+	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock ticks when the test sends on ticks,
+// interval is the last one set by the runner
+type manualClock struct {
+	ticks    chan time.Time
+	interval time.Duration
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{ticks: make(chan time.Time)}
+}
+
+func (aClock *manualClock) NewTicker(interval time.Duration) Ticker {
+	aClock.interval = interval
+	return aClock
+}
+
+func (aClock *manualClock) C() <-chan time.Time {
+	return aClock.ticks
+}
+
+func (aClock *manualClock) Reset(interval time.Duration) {
+	aClock.interval = interval
+}
+
+func (aClock *manualClock) Stop() {}
+
+// tick sends a tick, it returns once the runner goroutine has received it
+func (aClock *manualClock) tick() {
+	aClock.ticks <- time.Time{}
+}
+
+// newGame returns a started game on a 10x10 board, the snake starts
+// at the top left heading right and a candy is at (3,0)
+func newGame(t *testing.T, options ...gamestate.Option) gamestate.GameStater {
+	options = append(options,
+		gamestate.WithSeed(1),
+		gamestate.WithSnakeStart(testdata.Position0_0, common.Right),
+		gamestate.WithCandies(common.Sprite{Value: '*', Position: common.Position{X: 3, Y: 0}}),
+	)
+	game := gamestate.New(options...)
+	require.NoError(t, game.InitBoard(common.Size{Width: 10, Height: 10}))
+	_, err := game.CreateObjects()
+	require.NoError(t, err)
+	game.Start()
+
+	return game
+}
+
+// start runs aRunner until the test ends, the returned function
+// stops it and returns the error of Run
+func start(t *testing.T, aRunner *Runner) (stop func() error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- aRunner.Run(ctx)
+	}()
+	var once sync.Once
+	var err error
+	stop = func() error {
+		once.Do(func() {
+			cancel()
+			err = <-result
+		})
+		return err
+	}
+	t.Cleanup(func() { _ = stop() })
+
+	return stop
+}
+
+func TestRunner_Run(t *testing.T) {
+	aClock := newManualClock()
+	curve := difficulty.Linear(difficulty.Score, 100*time.Millisecond, 10*time.Millisecond, 50*time.Millisecond)
+	aRunner := New(newGame(t, gamestate.WithSpeedCurve(curve)), WithClock(aClock))
+	frames, _ := aRunner.Subscribe(10)
+	stop := start(t, aRunner)
+
+	// A round is played at each tick with the inputs sent before it
+	aClock.tick()
+	aFrame := <-frames
+	require.Equal(t, 1, aFrame.Round)
+	require.True(t, aFrame.GameInProgress)
+	aClock.tick()
+	aFrame = <-frames
+	require.Equal(t, 2, aFrame.Round)
+	require.NoError(t, aRunner.Steer(0, common.Right))
+	aClock.tick()
+	aFrame = <-frames
+	require.Equal(t, 3, aFrame.Round)
+	require.Equal(t, 1, aFrame.Score)
+	var kinds []event.Kind
+	for _, anEvent := range aFrame.Events {
+		kinds = append(kinds, anEvent.Kind)
+	}
+	require.Contains(t, kinds, event.CandyEaten)
+	// The ticks follow the speed curve of the game
+	require.NoError(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+		return nil
+	}))
+	require.Equal(t, 90*time.Millisecond, aClock.interval)
+	require.NoError(t, aRunner.Steer(0, common.Down))
+	aClock.tick()
+	<-frames
+	var position common.Position
+	require.NoError(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) (err error) {
+		position, err = game.SnakePosition()
+		return err
+	}))
+	require.Equal(t, common.Position{X: 3, Y: 1}, position)
+
+	// Nothing is played while the game is paused
+	require.NoError(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+		game.Pause()
+		return nil
+	}))
+	aClock.tick()
+	round := 0
+	require.NoError(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+		round = game.Round()
+		return nil
+	}))
+	require.Equal(t, 4, round)
+	require.Empty(t, frames)
+
+	// Stopping closes the frames and fails the next calls
+	require.ErrorIs(t, stop(), context.Canceled)
+	_, ok := <-frames
+	require.False(t, ok)
+	require.ErrorIs(t, aRunner.Steer(0, common.Up), ErrStopped)
+	require.ErrorIs(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+		return nil
+	}), ErrStopped)
+	require.ErrorIs(t, aRunner.Run(context.Background()), ErrRunning)
+	frames, _ = aRunner.Subscribe(1)
+	_, ok = <-frames
+	require.False(t, ok)
+}
+
+func TestRunner_Subscribe(t *testing.T) {
+	aClock := newManualClock()
+	aRunner := New(newGame(t), WithClock(aClock))
+	slow, _ := aRunner.Subscribe(1)
+	gone, unsubscribe := aRunner.Subscribe(1)
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-gone
+	require.False(t, ok)
+	fast, _ := aRunner.Subscribe(10)
+	start(t, aRunner)
+
+	// The slow subscriber misses the frames it has no room for
+	for round := 1; round <= 3; round++ {
+		aClock.tick()
+		require.Equal(t, round, (<-fast).Round)
+	}
+	require.Equal(t, 1, (<-slow).Round)
+	require.Empty(t, slow)
+}
+
+// apply returns board with the cells of aFrame
+func apply(board []string, aFrame Frame) []string {
+	if aFrame.Board != nil {
+		return aFrame.Board
+	}
+	cells := make([][]rune, len(board))
+	for y, row := range board {
+		cells[y] = []rune(row)
+	}
+	for _, sprite := range aFrame.Sprites {
+		cells[sprite.Position.Y][sprite.Position.X] = sprite.Value
+	}
+	board = make([]string, len(cells))
+	for y, row := range cells {
+		board[y] = string(row)
+	}
+
+	return board
+}
+
+func TestRunner_Observe(t *testing.T) {
+	aClock := newManualClock()
+	aRunner := New(newGame(t), WithClock(aClock), WithKeyframeInterval(4))
+	stop := start(t, aRunner)
+	cells := func() (rows []string) {
+		require.NoError(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+			rows = gamestate.Cells(game)
+			return nil
+		}))
+		return rows
+	}
+
+	// An observer receives the board first, then the cells of each round
+	fast, detach := aRunner.Observe(10)
+	aFrame := <-fast
+	require.Equal(t, 0, aFrame.Round)
+	require.Equal(t, cells(), aFrame.Board)
+	board := aFrame.Board
+	for round := 1; round <= 3; round++ {
+		aClock.tick()
+		aFrame = <-fast
+		require.Equal(t, round, aFrame.Round)
+		require.Nil(t, aFrame.Board)
+		board = apply(board, aFrame)
+	}
+	require.Equal(t, cells(), board)
+
+	// A slow observer never delays the game, it gets a keyframe once it
+	// has room again. The keyframe interval sends one to every observer
+	slow, _ := aRunner.Observe(1)
+	require.Eventually(t, func() bool { return len(slow) == 1 }, time.Second, time.Millisecond)
+	for round := 4; round <= 5; round++ {
+		aClock.tick()
+		aFrame = <-fast
+		require.Equal(t, round, aFrame.Round)
+		require.Equal(t, round == 4, aFrame.Board != nil)
+		board = apply(board, aFrame)
+	}
+	aFrame = <-slow
+	require.Equal(t, 3, aFrame.Round)
+	require.NotNil(t, aFrame.Board)
+	aClock.tick()
+	aFrame = <-slow
+	require.Equal(t, 6, aFrame.Round)
+	require.Equal(t, cells(), aFrame.Board)
+	board = apply(board, <-fast)
+	require.Equal(t, aFrame.Board, board)
+
+	// The channels are closed by detach or once the runner is stopped
+	detach()
+	detach()
+	_, ok := <-fast
+	require.False(t, ok)
+	require.ErrorIs(t, stop(), context.Canceled)
+	_, ok = <-slow
+	require.False(t, ok)
+	stopped, _ := aRunner.Observe(1)
+	_, ok = <-stopped
+	require.False(t, ok)
+}
+
+func TestRunner_Errors(t *testing.T) {
+	aClock := newManualClock()
+	aRunner := New(newGame(t), WithClock(aClock))
+	start(t, aRunner)
+
+	errFailed := errors.New("failed")
+	require.ErrorIs(t, aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+		return errFailed
+	}), errFailed)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	blocked := New(newGame(t))
+	require.ErrorIs(t, blocked.Do(ctx, func(game gamestate.GameStater) error {
+		return nil
+	}), context.Canceled)
+}
+
+// TestRunner_Concurrency is meant to be run with -race: many goroutines
+// steer the snake, read the game and observe it while the rounds are played
+func TestRunner_Concurrency(t *testing.T) {
+	aClock := newManualClock()
+	aRunner := New(newGame(t, gamestate.WithEdgePolicy(common.UniformEdges(common.WrapEdge))), WithClock(aClock), WithInputBuffer(4))
+	frames, _ := aRunner.Subscribe(1000)
+	stop := start(t, aRunner)
+
+	directions := []common.Direction{common.Up, common.Right, common.Down, common.Left}
+	var group sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		group.Add(3)
+		go func(i int) {
+			defer group.Done()
+			for j := 0; j < 500; j++ {
+				if err := aRunner.Steer(0, directions[(i+j)%len(directions)]); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+		go func() {
+			defer group.Done()
+			for j := 0; j < 50; j++ {
+				err := aRunner.Do(context.Background(), func(game gamestate.GameStater) error {
+					_, err := game.Snapshot()
+					return err
+				})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer group.Done()
+			for j := 0; j < 50; j++ {
+				observed, detach := aRunner.Observe(1)
+				<-observed
+				detach()
+			}
+		}()
+	}
+	ticks := 0
+	ticked := make(chan struct{})
+	go func() {
+		defer close(ticked)
+		for ; ticks < 200; ticks++ {
+			aClock.tick()
+		}
+	}()
+	group.Wait()
+	<-ticked
+	require.ErrorIs(t, stop(), context.Canceled)
+
+	// The rounds follow each other until the game is over
+	round := 0
+	for aFrame := range frames {
+		round++
+		require.Equal(t, round, aFrame.Round)
+	}
+	require.LessOrEqual(t, round, ticks)
+}
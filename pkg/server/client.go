@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+)
+
+// Client is a connection to a server. It keeps the board up to date
+// with the messages it receives
+type Client struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	encoder *json.Encoder
+	snake   common.SnakeID
+	round   int
+	cells   [][]rune
+}
+
+// Dial connects to the server at address
+func Dial(address string) (aClient *Client, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(conn), nil
+}
+
+// NewClient returns a client talking to a server on conn
+func NewClient(conn net.Conn) *Client {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxLine)
+	return &Client{
+		conn:    conn,
+		scanner: scanner,
+		encoder: json.NewEncoder(conn),
+	}
+}
+
+// Join enters the lobby as name
+func (aClient *Client) Join(name string) error {
+	return aClient.Send(Message{Type: TypeJoin, Name: name})
+}
+
+// Start starts the game with the players in the lobby
+func (aClient *Client) Start() error {
+	return aClient.Send(Message{Type: TypeStart})
+}
+
+// Watch follows the game being played, or the next one, as a spectator
+func (aClient *Client) Watch() error {
+	return aClient.Send(Message{Type: TypeWatch})
+}
+
+// Steer turns the snake of the player to direction
+func (aClient *Client) Steer(direction common.Direction) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	name, err := DirectionName(direction)
+	if err != nil {
+		return err
+	}
+
+	return aClient.Send(Message{Type: TypeSteer, Direction: name})
+}
+
+// Send writes a message to the server
+func (aClient *Client) Send(aMessage Message) error {
+	return aClient.encoder.Encode(aMessage)
+}
+
+// Receive reads the next message of the server and applies it to the board,
+// it returns io.EOF once the server has closed the connection
+func (aClient *Client) Receive() (aMessage Message, err error) {
+	if !aClient.scanner.Scan() {
+		if err = aClient.scanner.Err(); err == nil {
+			err = io.EOF
+		}
+		return aMessage, err
+	}
+	if err = json.Unmarshal(aClient.scanner.Bytes(), &aMessage); err != nil {
+		return aMessage, err
+	}
+	if aMessage.Snake != nil {
+		aClient.snake = *aMessage.Snake
+	}
+	if aMessage.Board != nil {
+		aClient.setBoard(*aMessage.Board)
+	}
+	for _, sprite := range aMessage.Sprites {
+		aClient.setCell(sprite)
+	}
+	if aMessage.Round > aClient.round {
+		aClient.round = aMessage.Round
+	}
+
+	return aMessage, nil
+}
+
+// Snake returns the ID of the snake steered by the client
+func (aClient *Client) Snake() common.SnakeID {
+	return aClient.snake
+}
+
+// Round returns the last round received
+func (aClient *Client) Round() int {
+	return aClient.round
+}
+
+// Board returns the rows of the board as known by the client
+func (aClient *Client) Board() (rows []string) {
+	for _, row := range aClient.cells {
+		rows = append(rows, string(row))
+	}
+
+	return rows
+}
+
+// Close closes the connection
+func (aClient *Client) Close() error {
+	return aClient.conn.Close()
+}
+
+func (aClient *Client) setBoard(board snapshot.Board) {
+	aClient.cells = make([][]rune, len(board.Cells))
+	for y, row := range board.Cells {
+		aClient.cells[y] = []rune(row)
+	}
+}
+
+func (aClient *Client) setCell(sprite common.Sprite) {
+	x, y := sprite.Position.X, sprite.Position.Y
+	if y < 0 || y >= len(aClient.cells) || x < 0 || x >= len(aClient.cells[y]) {
+		return
+	}
+	aClient.cells[y][x] = sprite.Value
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/runner"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+)
+
+// frameBuffer is the number of rounds waiting to be sent to a player
+const frameBuffer = 32
+
+// match is a game and the players steering its snakes. The player i steers
+// the snake i, the snakes start on the left of the board heading right
+type match struct {
+	server    *Server
+	runner    *runner.Runner
+	game      gamestate.GameStater
+	begin     snapshot.Board
+	cancel    context.CancelFunc
+	connected int
+	finished  chan struct{}
+	final     Message
+}
+
+// newMatch creates a game for players snakes, it is ready to be played
+func newMatch(aServer *Server, players int) (aMatch *match, err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	size := aServer.size
+	options := append([]gamestate.Option(nil), aServer.gameOptions...)
+	for i := 0; i < players; i++ {
+		position := common.Position{
+			X: size.Width / 4,
+			Y: (i + 1) * size.Height / (players + 1),
+		}
+		if i == 0 {
+			options = append(options, gamestate.WithSnakeStart(position, common.Right))
+			continue
+		}
+		options = append(options, gamestate.WithSnake(position, common.Right))
+	}
+	game := gamestate.New(options...)
+	if err = game.InitBoard(size); err != nil {
+		return nil, err
+	}
+	if _, err = game.CreateObjects(); err != nil {
+		return nil, err
+	}
+	game.Start()
+	aSnapshot, err := game.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return &match{
+		server:   aServer,
+		runner:   runner.New(game, runner.WithClock(aServer.clock)),
+		game:     game,
+		begin:    publicBoard(aSnapshot.Board),
+		finished: make(chan struct{}),
+	}, nil
+}
+
+// start plays the game until it is over or ctx is done.
+// It is called with the server mutex locked
+func (aMatch *match) start(ctx context.Context, players []*connection) {
+	ctx, aMatch.cancel = context.WithCancel(ctx)
+	aMatch.connected = len(players)
+	group := &aMatch.server.group
+	// The frames are subscribed before the game starts so none is missed
+	for _, player := range players {
+		frames, unsubscribe := aMatch.runner.Subscribe(frameBuffer)
+		group.Add(1)
+		go func(player *connection) {
+			defer group.Done()
+			defer unsubscribe()
+			aMatch.play(player, frames)
+		}(player)
+	}
+	frames, _ := aMatch.runner.Subscribe(1)
+	group.Add(2)
+	go func() {
+		defer group.Done()
+		aMatch.watch(ctx, frames)
+	}()
+	go func() {
+		defer group.Done()
+		defer aMatch.cancel()
+		// A failing round ends the game with an internal error, reported by final
+		_ = aMatch.runner.Run(ctx)
+		aMatch.final = aMatch.over()
+		close(aMatch.finished)
+		aMatch.server.ended(aMatch)
+	}()
+}
+
+// watch stops the runner once the game is over. It looks at the game
+// itself after each frame since a frame can be dropped
+func (aMatch *match) watch(ctx context.Context, frames <-chan runner.Frame) {
+	for range frames {
+		over := false
+		err := aMatch.runner.Do(ctx, func(game gamestate.GameStater) error {
+			over = !game.GameInProgress()
+			return nil
+		})
+		if err == nil && over {
+			aMatch.cancel()
+		}
+	}
+}
+
+// play sends the game to a player: the board, then the rounds and the result
+func (aMatch *match) play(player *connection, frames <-chan runner.Frame) {
+	snake := player.snake
+	if !player.send(Message{Type: TypeBegin, Snake: &snake, Board: &aMatch.begin}) {
+		return
+	}
+	round := 0
+	for aFrame := range frames {
+		if aFrame.Round <= round {
+			continue
+		}
+		if aFrame.Round > round+1 {
+			// Rounds were dropped, the whole board replaces them
+			board, at, err := aMatch.board()
+			if err != nil {
+				// The runner has stopped, the final board comes next
+				continue
+			}
+			if !player.send(Message{Type: TypeBoard, Round: at, Board: &board}) {
+				return
+			}
+			round = at
+			continue
+		}
+		if !player.send(Message{Type: TypeRound, Round: aFrame.Round, Sprites: aFrame.Sprites, Events: aFrame.Events}) {
+			return
+		}
+		round = aFrame.Round
+	}
+	aMatch.end(player)
+}
+
+// spectate sends the game to a spectator until it is over.
+// It is called with the server mutex locked
+func (aMatch *match) spectate(spectator *connection) {
+	frames, detach := aMatch.runner.Observe(frameBuffer)
+	group := &aMatch.server.group
+	group.Add(1)
+	go func() {
+		defer group.Done()
+		defer detach()
+		for aFrame := range frames {
+			aMessage := Message{Type: TypeRound, Round: aFrame.Round, Sprites: aFrame.Sprites, Events: aFrame.Events}
+			if aFrame.Board != nil {
+				board := snapshot.Board{Size: aMatch.begin.Size, Cells: aFrame.Board}
+				aMessage = Message{Type: TypeBoard, Round: aFrame.Round, Board: &board, Events: aFrame.Events}
+			}
+			if !spectator.send(aMessage) {
+				return
+			}
+		}
+		aMatch.end(spectator)
+	}()
+}
+
+// end sends the result to a client once the game is over and disconnects it
+func (aMatch *match) end(aConnection *connection) {
+	<-aMatch.finished
+	if aMatch.final.Type != "" {
+		aConnection.send(aMatch.final)
+	}
+	aConnection.close()
+}
+
+// board returns the board of the running game and its round
+func (aMatch *match) board() (board snapshot.Board, round int, err error) {
+	err = aMatch.runner.Do(context.Background(), func(game gamestate.GameStater) error {
+		aSnapshot, err := game.Snapshot()
+		board = publicBoard(aSnapshot.Board)
+		round = aSnapshot.Round
+		return err
+	})
+
+	return board, round, err
+}
+
+// over returns the message ending the game once the runner has stopped,
+// its type is empty when the game was interrupted
+func (aMatch *match) over() Message {
+	aResult, ok := aMatch.game.Result()
+	if !ok {
+		return Message{}
+	}
+	aMessage := Message{
+		Type:   TypeOver,
+		Round:  aMatch.game.Round(),
+		Result: &aResult,
+	}
+	if aSnapshot, err := aMatch.game.Snapshot(); err == nil {
+		board := publicBoard(aSnapshot.Board)
+		aMessage.Board = &board
+	}
+
+	return aMessage
+}
+
+// leave counts a player gone, the game is stopped once they are all gone.
+// It is called with the server mutex locked
+func (aMatch *match) leave() {
+	aMatch.connected--
+	if aMatch.connected == 0 {
+		aMatch.cancel()
+	}
+}
+
+// publicBoard hides the state of the random source from the players
+func publicBoard(board snapshot.Board) snapshot.Board {
+	board.RandomState = nil
+	return board
+}
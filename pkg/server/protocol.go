@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/event"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
+)
+
+// maxLine is the longest line read, a board of a few hundred cells per side fits
+const maxLine = 1 << 20
+
+// Types of the messages
+const (
+	// Sent by the clients
+	TypeJoin  = "join"  // enter the lobby as Name
+	TypeStart = "start" // start the game with the players in the lobby
+	TypeSteer = "steer" // turn the snake of the player to Direction
+	TypeWatch = "watch" // follow the game being played, or the next one, without playing
+
+	// Sent by the server
+	TypeLobby = "lobby" // Players are waiting, the game starts when there are Needed
+	TypeBegin = "begin" // the game starts, the player has the Snake and Board is the whole board
+	TypeBoard = "board" // Board is the whole board after Round, sent when rounds were missed and now and then to the spectators
+	TypeRound = "round" // Round has been played, Sprites are the cells changed and Events what happened
+	TypeOver  = "over"  // the game has ended with Result, Board is the final board
+	TypeError = "error" // the last message was rejected for Error
+)
+
+// Message is a line of the protocol, a JSON object whose fields depend on Type
+type Message struct {
+	Type      string             `json:"type"`
+	Name      string             `json:"name,omitempty"`
+	Direction string             `json:"direction,omitempty"`
+	Players   []string           `json:"players,omitempty"`
+	Needed    int                `json:"needed,omitempty"`
+	Snake     *common.SnakeID    `json:"snake,omitempty"`
+	Round     int                `json:"round,omitempty"`
+	Board     *snapshot.Board    `json:"board,omitempty"`
+	Sprites   []common.Sprite    `json:"sprites,omitempty"`
+	Events    []event.Event      `json:"events,omitempty"`
+	Result    *common.GameResult `json:"result,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+var directionNames = map[common.Direction]string{
+	common.Left:  "left",
+	common.Right: "right",
+	common.Up:    "up",
+	common.Down:  "down",
+}
+
+// DirectionName returns the name of direction in the messages
+func DirectionName(direction common.Direction) (name string, err error) {
+	name, ok := directionNames[direction]
+	if !ok {
+		return name, fmt.Errorf("invalid direction %v", direction)
+	}
+
+	return name, nil
+}
+
+// ParseDirection returns the direction called name in the messages
+func ParseDirection(name string) (direction common.Direction, err error) {
+	for direction, directionName := range directionNames {
+		if directionName == name {
+			return direction, nil
+		}
+	}
+
+	return direction, fmt.Errorf("unknown direction %q", name)
+}
@@ -0,0 +1,391 @@
+// Package server hosts snake games over TCP.
+//
+// The protocol is a JSON Message per line. A client joins the lobby,
+// the game starts once enough players have joined or when one of them
+// asks for it. Each player then receives the whole board and the snake
+// it steers, followed by the cells changed every round, until the game
+// is over and the server closes the connection:
+//
+//	-> {"type":"join","name":"alice"}
+//	<- {"type":"lobby","players":["alice"],"needed":2}
+//	<- {"type":"begin","snake":1,"board":{...}}
+//	-> {"type":"steer","direction":"up"}
+//	<- {"type":"round","round":1,"sprites":[...]}
+//	<- {"type":"over","round":57,"result":{...},"board":{...}}
+//
+// A player too slow to read every round gets the whole board again
+// instead of the rounds it missed. The snake of a player who leaves
+// goes on without being steered.
+//
+// A spectator watches the game being played, or the next one, without
+// taking part in it. It receives the whole board, then the rounds, and
+// the whole board again now and then:
+//
+//	-> {"type":"watch"}
+//	<- {"type":"board","round":12,"board":{...}}
+//	<- {"type":"round","round":13,"sprites":[...]}
+//
+// A spectator never slows the game down: one too slow to read every
+// round gets the whole board instead of the rounds it missed.
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/runner"
+)
+
+// Defaults of a server
+const (
+	DefaultPlayers      = 2
+	DefaultWriteTimeout = 5 * time.Second
+)
+
+// DefaultSize is the board size of the games
+var DefaultSize = common.Size{Width: 40, Height: 20}
+
+// Defines custom errors
+var (
+	ErrNotJoined     = errors.New("join the lobby first")
+	ErrAlreadyJoined = errors.New("already joined")
+	ErrNotPlaying    = errors.New("the game hasn't started")
+	ErrUnknownType   = errors.New("unknown message type")
+	ErrSpectator     = errors.New("spectators can't play")
+)
+
+// Server hosts the games, the players wait in the lobby until a game starts
+type Server struct {
+	players      int
+	size         common.Size
+	gameOptions  []gamestate.Option
+	clock        runner.Clock
+	writeTimeout time.Duration
+
+	mutex sync.Mutex
+	lobby []*connection
+	// spectators wait for the next game, latest is the last game started
+	spectators []*connection
+	latest     *match
+	group      sync.WaitGroup
+}
+
+// connection is a client, match is set once its game has started
+type connection struct {
+	conn     net.Conn
+	name     string
+	snake    common.SnakeID
+	match    *match
+	watching bool
+	timeout  time.Duration
+	writing  sync.Mutex
+	encoder  *json.Encoder
+}
+
+// Option configures a Server created by New
+type Option func(aServer *Server)
+
+// WithPlayers sets how many players start a game, a game can start
+// with less when a player asks for it
+func WithPlayers(players int) Option {
+	return func(aServer *Server) {
+		if players < 1 {
+			players = 1
+		}
+		aServer.players = players
+	}
+}
+
+// WithSize sets the board size of the games
+func WithSize(size common.Size) Option {
+	return func(aServer *Server) {
+		aServer.size = size
+	}
+}
+
+// WithGameOptions configures the games, the snake starts are set by the server.
+// The options are shared by the games played at the same time: WithSeed
+// gives each game its own source, a source set by WithRandom is shared
+func WithGameOptions(options ...gamestate.Option) Option {
+	return func(aServer *Server) {
+		aServer.gameOptions = append(aServer.gameOptions, options...)
+	}
+}
+
+// WithClock sets the clock ticking the games
+func WithClock(clock runner.Clock) Option {
+	return func(aServer *Server) {
+		aServer.clock = clock
+	}
+}
+
+// WithWriteTimeout sets how long a message may take to be sent before the client is dropped
+func WithWriteTimeout(timeout time.Duration) Option {
+	return func(aServer *Server) {
+		aServer.writeTimeout = timeout
+	}
+}
+
+// New returns a server
+func New(options ...Option) *Server {
+	aServer := &Server{
+		players:      DefaultPlayers,
+		size:         DefaultSize,
+		clock:        runner.SystemClock,
+		writeTimeout: DefaultWriteTimeout,
+	}
+	for _, option := range options {
+		option(aServer)
+	}
+
+	return aServer
+}
+
+// Serve accepts the clients on listener until ctx is done, then it
+// closes listener and the connections and waits for the games to end
+func (aServer *Server) Serve(ctx context.Context, listener net.Listener) (err error) {
+	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	defer aServer.group.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		aServer.group.Add(1)
+		go func() {
+			defer aServer.group.Done()
+			aServer.handle(ctx, conn)
+		}()
+	}
+}
+
+// handle reads the messages of a client until it leaves or ctx is done
+func (aServer *Server) handle(ctx context.Context, conn net.Conn) {
+	aConnection := &connection{
+		conn:    conn,
+		timeout: aServer.writeTimeout,
+		encoder: json.NewEncoder(conn),
+	}
+	left := make(chan struct{})
+	defer close(left)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-left:
+		}
+		aConnection.close()
+	}()
+	defer aServer.leave(aConnection)
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(nil, maxLine)
+	for scanner.Scan() {
+		var aMessage Message
+		if err := json.Unmarshal(scanner.Bytes(), &aMessage); err != nil {
+			aConnection.sendError(err)
+			continue
+		}
+		if err := aServer.receive(ctx, aConnection, aMessage); err != nil {
+			aConnection.sendError(err)
+		}
+	}
+}
+
+// receive applies a message of a client
+func (aServer *Server) receive(ctx context.Context, aConnection *connection, aMessage Message) error {
+	switch aMessage.Type {
+	case TypeJoin:
+		return aServer.join(ctx, aConnection, aMessage.Name)
+	case TypeStart:
+		return aServer.start(ctx, aConnection)
+	case TypeWatch:
+		return aServer.watch(aConnection)
+	case TypeSteer:
+		direction, err := ParseDirection(aMessage.Direction)
+		if err != nil {
+			return err
+		}
+		aServer.mutex.Lock()
+		aMatch, snake := aConnection.match, aConnection.snake
+		aServer.mutex.Unlock()
+		if aMatch == nil {
+			return ErrNotPlaying
+		}
+		// The runner is stopped once the game is over, the late inputs don't matter
+		_ = aMatch.runner.Steer(snake, direction)
+		return nil
+	}
+
+	return ErrUnknownType
+}
+
+// join adds a client to the lobby, the game starts when it is full
+func (aServer *Server) join(ctx context.Context, aConnection *connection, name string) error {
+	aServer.mutex.Lock()
+	defer aServer.mutex.Unlock()
+	if aConnection.watching {
+		return ErrSpectator
+	}
+	if aConnection.name != "" {
+		return ErrAlreadyJoined
+	}
+	if name == "" {
+		name = "player"
+	}
+	aConnection.name = name
+	aServer.lobby = append(aServer.lobby, aConnection)
+	if len(aServer.lobby) >= aServer.players {
+		return aServer.startMatch(ctx)
+	}
+	aServer.announceLobby()
+	return nil
+}
+
+// start starts a game with the players in the lobby
+func (aServer *Server) start(ctx context.Context, aConnection *connection) error {
+	aServer.mutex.Lock()
+	defer aServer.mutex.Unlock()
+	if aConnection.match != nil {
+		return nil
+	}
+	if aConnection.name == "" {
+		return ErrNotJoined
+	}
+
+	return aServer.startMatch(ctx)
+}
+
+// watch makes a client a spectator of the last game started,
+// or of the next one once it is over
+func (aServer *Server) watch(aConnection *connection) error {
+	aServer.mutex.Lock()
+	defer aServer.mutex.Unlock()
+	if aConnection.name != "" {
+		return ErrAlreadyJoined
+	}
+	if aConnection.watching {
+		return nil
+	}
+	aConnection.watching = true
+	if aServer.latest != nil {
+		aServer.latest.spectate(aConnection)
+		return nil
+	}
+	aServer.spectators = append(aServer.spectators, aConnection)
+	return nil
+}
+
+// leave removes a client from the lobby or from its game
+func (aServer *Server) leave(aConnection *connection) {
+	aServer.mutex.Lock()
+	defer aServer.mutex.Unlock()
+	if aConnection.match != nil {
+		aConnection.match.leave()
+		return
+	}
+	for i, waiting := range aServer.spectators {
+		if waiting == aConnection {
+			aServer.spectators = append(aServer.spectators[:i], aServer.spectators[i+1:]...)
+			return
+		}
+	}
+	for i, waiting := range aServer.lobby {
+		if waiting == aConnection {
+			aServer.lobby = append(aServer.lobby[:i], aServer.lobby[i+1:]...)
+			aServer.announceLobby()
+			return
+		}
+	}
+}
+
+// announceLobby tells the players in the lobby who is waiting.
+// It is called with the mutex locked so the announces keep their order
+func (aServer *Server) announceLobby() {
+	aMessage := Message{
+		Type:   TypeLobby,
+		Needed: aServer.players,
+	}
+	for _, waiting := range aServer.lobby {
+		aMessage.Players = append(aMessage.Players, waiting.name)
+	}
+	for _, waiting := range aServer.lobby {
+		waiting.send(aMessage)
+	}
+}
+
+// startMatch starts a game with the players of the lobby.
+// It is called with the mutex locked
+func (aServer *Server) startMatch(ctx context.Context) error {
+	players := aServer.lobby
+	aMatch, err := newMatch(aServer, len(players))
+	if err != nil {
+		return err
+	}
+	aServer.lobby = nil
+	for i, aConnection := range players {
+		aConnection.snake = common.SnakeID(i)
+		aConnection.match = aMatch
+	}
+	aMatch.start(ctx, players)
+	for _, spectator := range aServer.spectators {
+		aMatch.spectate(spectator)
+	}
+	aServer.spectators = nil
+	aServer.latest = aMatch
+	return nil
+}
+
+// ended forgets aMatch once it is over, the next spectators wait for the next game
+func (aServer *Server) ended(aMatch *match) {
+	aServer.mutex.Lock()
+	defer aServer.mutex.Unlock()
+	if aServer.latest == aMatch {
+		aServer.latest = nil
+	}
+}
+
+// send writes a message to the client, a client which can't
+// receive it in time is disconnected
+func (aConnection *connection) send(aMessage Message) bool {
+	aConnection.writing.Lock()
+	defer aConnection.writing.Unlock()
+	if aConnection.timeout > 0 {
+		_ = aConnection.conn.SetWriteDeadline(time.Now().Add(aConnection.timeout))
+	}
+	if err := aConnection.encoder.Encode(aMessage); err != nil {
+		aConnection.close()
+		return false
+	}
+
+	return true
+}
+
+func (aConnection *connection) sendError(err error) {
+	aConnection.send(Message{
+		Type:  TypeError,
+		Error: err.Error(),
+	})
+}
+
+// close closes the connection, it unblocks a message being sent
+func (aConnection *connection) close() {
+	aConnection.conn.Close()
+}
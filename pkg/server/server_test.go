@@ -0,0 +1,447 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	gamestate "github.com/Amari-Mecheri/GoSnakeLogic"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/runner"
+	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock ticks when the test sends on ticks
+type manualClock struct {
+	ticks chan time.Time
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{ticks: make(chan time.Time)}
+}
+
+func (aClock *manualClock) NewTicker(interval time.Duration) runner.Ticker {
+	return aClock
+}
+
+func (aClock *manualClock) C() <-chan time.Time {
+	return aClock.ticks
+}
+
+func (aClock *manualClock) Reset(interval time.Duration) {}
+
+func (aClock *manualClock) Stop() {}
+
+// tickUntil ticks until done is closed
+func (aClock *manualClock) tickUntil(done <-chan struct{}) {
+	for {
+		select {
+		case aClock.ticks <- time.Time{}:
+		case <-done:
+			return
+		}
+	}
+}
+
+// serve runs aServer on a loopback port until the test ends, the returned
+// function stops it and returns the error of Serve
+func serve(t *testing.T, aServer *Server) (address string, stop func() error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- aServer.Serve(ctx, listener)
+	}()
+	stopped := false
+	stop = func() error {
+		if stopped {
+			return nil
+		}
+		stopped = true
+		cancel()
+		return <-result
+	}
+	t.Cleanup(func() { _ = stop() })
+
+	return listener.Addr().String(), stop
+}
+
+func dial(t *testing.T, address string) *Client {
+	aClient, err := Dial(address)
+	require.NoError(t, err)
+	t.Cleanup(func() { aClient.Close() })
+
+	return aClient
+}
+
+func receive(t *testing.T, aClient *Client, messageType string) Message {
+	aMessage, err := aClient.Receive()
+	require.NoError(t, err)
+	require.Equal(t, messageType, aMessage.Type, aMessage.Error)
+
+	return aMessage
+}
+
+// flush returns once the server has handled the messages sent before,
+// it answers an invalid message with an error
+func flush(t *testing.T, aClient *Client) {
+	require.NoError(t, aClient.Send(Message{Type: "sync"}))
+	aMessage := receive(t, aClient, TypeError)
+	require.Equal(t, ErrUnknownType.Error(), aMessage.Error)
+}
+
+// playing is what a client received until the server closed the connection
+type playing struct {
+	rounds int
+	over   Message
+	// board is the board built from the rounds before the final one was received
+	board []string
+}
+
+// play reads the messages of aClient until the end of the game
+func play(aClient *Client, result chan<- playing) {
+	var aPlaying playing
+	for {
+		board := aClient.Board()
+		aMessage, err := aClient.Receive()
+		if err != nil {
+			result <- aPlaying
+			return
+		}
+		switch aMessage.Type {
+		case TypeRound, TypeBoard:
+			aPlaying.rounds++
+		case TypeOver:
+			aPlaying.over = aMessage
+			aPlaying.board = board
+		}
+	}
+}
+
+func TestServer_Game(t *testing.T) {
+	aClock := newManualClock()
+	aServer := New(
+		WithPlayers(2),
+		WithSize(common.Size{Width: 12, Height: 6}),
+		WithClock(aClock),
+		WithGameOptions(
+			gamestate.WithSeed(1),
+			gamestate.WithEdgePolicy(common.UniformEdges(common.WallEdge)),
+		),
+	)
+	address, stop := serve(t, aServer)
+
+	// The game starts when the second player joins
+	alice := dial(t, address)
+	require.NoError(t, alice.Join("alice"))
+	aMessage := receive(t, alice, TypeLobby)
+	require.Equal(t, []string{"alice"}, aMessage.Players)
+	require.Equal(t, 2, aMessage.Needed)
+	bob := dial(t, address)
+	require.NoError(t, bob.Join("bob"))
+	aMessage = receive(t, alice, TypeBegin)
+	require.Equal(t, common.SnakeID(0), alice.Snake())
+	require.Len(t, aMessage.Board.Cells, 6)
+	require.Nil(t, aMessage.Board.RandomState)
+	receive(t, bob, TypeBegin)
+	require.Equal(t, common.SnakeID(1), bob.Snake())
+	require.Equal(t, alice.Board(), bob.Board())
+
+	// Alice heads to the bottom wall, Bob to the right wall and dies last
+	require.NoError(t, alice.Steer(common.Down))
+	flush(t, alice)
+	results := make(chan playing, 2)
+	go play(alice, results)
+	go play(bob, results)
+	done := make(chan struct{})
+	defer close(done)
+	go aClock.tickUntil(done)
+
+	for i := 0; i < 2; i++ {
+		aPlaying := <-results
+		require.Equal(t, TypeOver, aPlaying.over.Type)
+		require.Equal(t, common.SnakeID(1), aPlaying.over.Result.Snake)
+		require.Equal(t, common.WallCollision, aPlaying.over.Result.Reason)
+		require.Equal(t, aPlaying.over.Round, aPlaying.over.Result.Round)
+		require.Greater(t, aPlaying.rounds, 0)
+		// The rounds sent rebuild the final board
+		require.Equal(t, aPlaying.over.Board.Cells, aPlaying.board)
+	}
+	require.ErrorIs(t, stop(), context.Canceled)
+}
+
+// TestServer_Matches is meant to be run with -race: the games
+// created with the same options are played at the same time
+func TestServer_Matches(t *testing.T) {
+	aClock := newManualClock()
+	aServer := New(
+		WithPlayers(1),
+		WithSize(testdata.Size4_4),
+		WithClock(aClock),
+		WithGameOptions(
+			gamestate.WithSeed(1),
+			gamestate.WithEdgePolicy(common.UniformEdges(common.WallEdge)),
+		),
+	)
+	address, _ := serve(t, aServer)
+
+	results := make(chan playing, 2)
+	for _, name := range []string{"alice", "bob"} {
+		aClient := dial(t, address)
+		require.NoError(t, aClient.Join(name))
+		receive(t, aClient, TypeBegin)
+		go play(aClient, results)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go aClock.tickUntil(done)
+
+	// Both games draw the same candies from their own source
+	var over []Message
+	for i := 0; i < 2; i++ {
+		aPlaying := <-results
+		require.Equal(t, TypeOver, aPlaying.over.Type)
+		require.Equal(t, aPlaying.over.Board.Cells, aPlaying.board)
+		over = append(over, aPlaying.over)
+	}
+	require.Equal(t, over[0].Round, over[1].Round)
+	require.Equal(t, over[0].Board.Cells, over[1].Board.Cells)
+}
+
+func TestServer_Errors(t *testing.T) {
+	address, _ := serve(t, New(WithClock(newManualClock())))
+	aClient := dial(t, address)
+
+	tests := []struct {
+		name      string
+		aMessage  Message
+		wantError string
+	}{
+		{
+			name:      "TestSteerBeforeJoin",
+			aMessage:  Message{Type: TypeSteer, Direction: "up"},
+			wantError: ErrNotPlaying.Error(),
+		},
+		{
+			name:      "TestStartBeforeJoin",
+			aMessage:  Message{Type: TypeStart},
+			wantError: ErrNotJoined.Error(),
+		},
+		{
+			name:      "TestInvalidDirection",
+			aMessage:  Message{Type: TypeSteer, Direction: "north"},
+			wantError: `unknown direction "north"`,
+		},
+		{
+			name:      "TestUnknownType",
+			aMessage:  Message{Type: "dance"},
+			wantError: ErrUnknownType.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, aClient.Send(tt.aMessage))
+			aMessage := receive(t, aClient, TypeError)
+			require.Equal(t, tt.wantError, aMessage.Error)
+		})
+	}
+
+	require.NoError(t, aClient.Join("alice"))
+	receive(t, aClient, TypeLobby)
+	require.NoError(t, aClient.Join("alice"))
+	require.Equal(t, ErrAlreadyJoined.Error(), receive(t, aClient, TypeError).Error)
+	_, err := aClient.conn.Write([]byte("{\n"))
+	require.NoError(t, err)
+	receive(t, aClient, TypeError)
+	require.Error(t, aClient.Steer(common.Direction{DX: 1, DY: 1}))
+}
+
+func TestServer_Start(t *testing.T) {
+	address, stop := serve(t, New(WithPlayers(3), WithClock(newManualClock())))
+
+	// A player starts the game alone, the next one waits in the lobby
+	alice := dial(t, address)
+	require.NoError(t, alice.Join("alice"))
+	receive(t, alice, TypeLobby)
+	require.NoError(t, alice.Start())
+	receive(t, alice, TypeBegin)
+	bob := dial(t, address)
+	require.NoError(t, bob.Join(""))
+	require.Equal(t, []string{"player"}, receive(t, bob, TypeLobby).Players)
+	carol := dial(t, address)
+	require.NoError(t, carol.Join("carol"))
+	require.Equal(t, []string{"player", "carol"}, receive(t, bob, TypeLobby).Players)
+	require.NoError(t, carol.Close())
+	require.Equal(t, []string{"player"}, receive(t, bob, TypeLobby).Players)
+
+	// Stopping the server closes the connections without a result
+	require.ErrorIs(t, stop(), context.Canceled)
+	_, err := alice.Receive()
+	require.ErrorIs(t, err, io.EOF)
+	_, err = bob.Receive()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestServer_Spectator(t *testing.T) {
+	aClock := newManualClock()
+	aServer := New(
+		WithPlayers(1),
+		WithSize(common.Size{Width: 12, Height: 6}),
+		WithClock(aClock),
+		WithGameOptions(
+			gamestate.WithSeed(1),
+			gamestate.WithEdgePolicy(common.UniformEdges(common.WallEdge)),
+		),
+	)
+	address, _ := serve(t, aServer)
+
+	// A spectator waits for the next game, it can't play
+	early := dial(t, address)
+	require.NoError(t, early.Watch())
+	require.NoError(t, early.Join("early"))
+	require.Equal(t, ErrSpectator.Error(), receive(t, early, TypeError).Error)
+	require.NoError(t, early.Steer(common.Down))
+	require.Equal(t, ErrNotPlaying.Error(), receive(t, early, TypeError).Error)
+	alice := dial(t, address)
+	require.NoError(t, alice.Join("alice"))
+	receive(t, alice, TypeBegin)
+	aMessage := receive(t, early, TypeBoard)
+	require.Equal(t, 0, aMessage.Round)
+	require.Equal(t, alice.Board(), early.Board())
+
+	// A spectator joining a game being played receives its board first
+	for round := 1; round <= 2; round++ {
+		aClock.ticks <- time.Time{}
+		require.Equal(t, round, receive(t, alice, TypeRound).Round)
+	}
+	late := dial(t, address)
+	require.NoError(t, late.Watch())
+	aMessage = receive(t, late, TypeBoard)
+	require.Equal(t, 2, aMessage.Round)
+	require.Equal(t, alice.Board(), late.Board())
+	require.NoError(t, late.Join("late"))
+	require.Equal(t, ErrSpectator.Error(), receive(t, late, TypeError).Error)
+
+	// The spectators follow the game until its end, Alice hits the right wall
+	results := make(chan playing, 3)
+	go play(alice, results)
+	go play(early, results)
+	go play(late, results)
+	done := make(chan struct{})
+	defer close(done)
+	go aClock.tickUntil(done)
+	for i := 0; i < 3; i++ {
+		aPlaying := <-results
+		require.Equal(t, TypeOver, aPlaying.over.Type)
+		require.Equal(t, common.WallCollision, aPlaying.over.Result.Reason)
+		require.Equal(t, aPlaying.over.Board.Cells, aPlaying.board)
+	}
+}
+
+func TestServer_SlowPlayer(t *testing.T) {
+	// net.Pipe has no buffer: the rounds pile up while the player doesn't read
+	aClock := newManualClock()
+	aServer := New(
+		WithPlayers(1),
+		WithSize(common.Size{Width: 30, Height: 30}),
+		WithClock(aClock),
+		WithGameOptions(gamestate.WithSeed(1)),
+	)
+	serverConn, clientConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		aServer.handle(ctx, serverConn)
+	}()
+	aClient := NewClient(clientConn)
+	require.NoError(t, aClient.Join("slow"))
+	receive(t, aClient, TypeBegin)
+	for i := 0; i < 3*frameBuffer; i++ {
+		aClock.ticks <- time.Time{}
+	}
+	done := make(chan struct{})
+	go aClock.tickUntil(done)
+
+	// The rounds missed are replaced by the board, the next ones follow it
+	round := 0
+	boardRound := 0
+	for boardRound == 0 || round < boardRound+frameBuffer {
+		aMessage, err := aClient.Receive()
+		require.NoError(t, err)
+		if aMessage.Type == TypeBoard {
+			require.Greater(t, aMessage.Round, round+1)
+			boardRound = aMessage.Round
+		} else {
+			require.Equal(t, TypeRound, aMessage.Type)
+			require.Equal(t, round+1, aMessage.Round)
+		}
+		round = aMessage.Round
+	}
+	require.Greater(t, boardRound, frameBuffer)
+
+	cancel()
+	<-handled
+	close(done)
+	aServer.group.Wait()
+}
+
+func TestServer_SlowSpectator(t *testing.T) {
+	aClock := newManualClock()
+	aServer := New(
+		WithPlayers(1),
+		WithSize(common.Size{Width: 30, Height: 30}),
+		WithClock(aClock),
+		WithGameOptions(gamestate.WithSeed(1)),
+	)
+	address, stop := serve(t, aServer)
+	alice := dial(t, address)
+	require.NoError(t, alice.Join("alice"))
+	receive(t, alice, TypeBegin)
+
+	// net.Pipe has no buffer: the spectator doesn't read, the game goes on
+	serverConn, clientConn := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		aServer.handle(ctx, serverConn)
+	}()
+	spectator := NewClient(clientConn)
+	require.NoError(t, spectator.Watch())
+	for round := 1; round <= 3*frameBuffer; round++ {
+		aClock.ticks <- time.Time{}
+		require.Equal(t, round, receive(t, alice, TypeRound).Round)
+	}
+
+	done := make(chan struct{})
+	go aClock.tickUntil(done)
+
+	// The spectator gets the whole board again after the rounds it missed
+	round := receive(t, spectator, TypeBoard).Round
+	boardRound := 0
+	for boardRound == 0 {
+		aMessage, err := spectator.Receive()
+		require.NoError(t, err)
+		if aMessage.Type == TypeBoard {
+			require.Greater(t, aMessage.Round, round+1)
+			boardRound = aMessage.Round
+		} else {
+			require.Equal(t, TypeRound, aMessage.Type)
+			require.Equal(t, round+1, aMessage.Round)
+		}
+		round = aMessage.Round
+	}
+	require.Greater(t, boardRound, 3*frameBuffer)
+	require.Len(t, spectator.Board(), 30)
+
+	cancel()
+	<-handled
+	require.ErrorIs(t, stop(), context.Canceled)
+	close(done)
+}
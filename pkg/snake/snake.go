@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 )
 
 // Snaker is the snake interface
@@ -13,13 +14,26 @@ type Snaker interface {
 	Position() (position common.Position, err error)
 	Direction() (direction common.Direction, err error)
 	Tail() (tail common.Position, err error)
+	Occupies(position common.Position) bool
 	NextMove() (nextPosition common.Position, err error)
 	MoveTo(newPosition common.Position) (theTail common.Position, err error)
 	GrowTo(newPosition common.Position) (err error)
+	Shrink(segments int) (removed []common.Position, err error)
+	Snapshot() snapshot.Snake
+	Restore(aSnapshot snapshot.Snake)
+	Clone() Snaker
 }
 
+// snake stores its body in a ring buffer: the tail is at ring[first] and
+// the following parts up to the head come next, going round the end of ring.
+// cells counts the parts on each cell.
+// Moving and looking up the body are O(1) and don't allocate, only growing
+// past the capacity of ring or onto cells never visited does
 type snake struct {
-	body      []common.Position
+	ring      []common.Position
+	first     int
+	size      int
+	cells     occupancy
 	direction common.Direction
 }
 
@@ -31,10 +45,11 @@ func New() Snaker {
 	return new(snake)
 }
 
-func (aSnake *snake) Size() (size int, err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+// The accessors and the moves are called for every snake every round,
+// they don't go through ErrorWrapper to stay cheap
 
-	return len(aSnake.body), nil
+func (aSnake *snake) Size() (size int, err error) {
+	return aSnake.size, nil
 }
 
 func (aSnake *snake) SetDirection(direction common.Direction) {
@@ -42,42 +57,31 @@ func (aSnake *snake) SetDirection(direction common.Direction) {
 }
 
 func (aSnake *snake) Direction() (direction common.Direction, err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
 	return aSnake.direction, nil
 }
 
 func (aSnake *snake) Position() (position common.Position, err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
-	var size int
-	size, err = aSnake.Size()
-	if err != nil { // should not occur
-		return position, err
-	}
-	if size > 0 {
-		return aSnake.body[size-1], nil
+	if aSnake.size == 0 {
+		return position, ErrNoSnakeBody
 	}
 
-	return position, ErrNoSnakeBody
+	return aSnake.part(aSnake.size - 1), nil
 }
 
 func (aSnake *snake) Tail() (tail common.Position, err error) {
-	size, err := aSnake.Size()
-	if err != nil { // should not occur
-		return tail, err
+	if aSnake.size == 0 {
+		return tail, ErrNoSnakeBody
 	}
 
-	if size > 0 {
-		return aSnake.body[0], nil
-	}
+	return aSnake.ring[aSnake.first], nil
+}
 
-	return tail, ErrNoSnakeBody
+// Occupies tells if a part of the snake is on position
+func (aSnake *snake) Occupies(position common.Position) bool {
+	return aSnake.cells.count(position) > 0
 }
 
 func (aSnake *snake) NextMove() (nextPosition common.Position, err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
-
 	nextPosition, err = aSnake.Position()
 	if err != nil {
 		return nextPosition, err
@@ -88,31 +92,204 @@ func (aSnake *snake) NextMove() (nextPosition common.Position, err error) {
 }
 
 func (aSnake *snake) MoveTo(newPosition common.Position) (theTail common.Position, err error) {
+	if aSnake.size == 0 {
+		return theTail, ErrNoSnakeBody
+	}
+	// Removes the tail, the head may take its place in the ring
+	theTail = aSnake.ring[aSnake.first]
+	aSnake.cells.add(theTail, -1)
+	aSnake.first = aSnake.index(1)
+	// Inserts the head at new position
+	aSnake.ring[aSnake.index(aSnake.size-1)] = newPosition
+	aSnake.cells.add(newPosition, 1)
+	// return the tail
+	return theTail, nil
+}
+
+func (aSnake *snake) GrowTo(newPosition common.Position) (err error) {
+	// Doesn't remove the tail
+	// Inserts the head at new position
+	if aSnake.size == len(aSnake.ring) {
+		aSnake.grow()
+	}
+	aSnake.ring[aSnake.index(aSnake.size)] = newPosition
+	aSnake.size++
+	aSnake.cells.add(newPosition, 1)
+	return nil
+}
+
+// Shrink removes up to segments parts from the tail, the head is always kept
+func (aSnake *snake) Shrink(segments int) (removed []common.Position, err error) {
 	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
 
-	var size int
-	size, err = aSnake.Size()
-	if err != nil { // should not occur
-		return theTail, err
+	if aSnake.size == 0 {
+		return nil, ErrNoSnakeBody
+	}
+	if segments > aSnake.size-1 {
+		segments = aSnake.size - 1
+	}
+	if segments <= 0 {
+		return nil, nil
 	}
-	if size > 0 {
-		// Removes the tail
-		theTail = aSnake.body[0]
-		aSnake.body = aSnake.body[1:]
-		// Inserts the head at new position
-		aSnake.body = append(aSnake.body, newPosition)
-		// return the tail
-		return theTail, nil
+	removed = make([]common.Position, segments)
+	for i := range removed {
+		removed[i] = aSnake.part(i)
+		aSnake.cells.add(removed[i], -1)
 	}
+	aSnake.first = aSnake.index(segments)
+	aSnake.size -= segments
+	return removed, nil
+}
 
-	return theTail, ErrNoSnakeBody
+func (aSnake *snake) Snapshot() snapshot.Snake {
+	return snapshot.Snake{
+		Body:      aSnake.body(),
+		Direction: aSnake.direction,
+	}
 }
 
-func (aSnake *snake) GrowTo(newPosition common.Position) (err error) {
-	defer common.ErrorWrapper(common.GetCurrentFuncName(), &err)
+func (aSnake *snake) Restore(aSnapshot snapshot.Snake) {
+	aSnake.ring = append([]common.Position(nil), aSnapshot.Body...)
+	aSnake.first = 0
+	aSnake.size = len(aSnapshot.Body)
+	aSnake.cells.clear()
+	for _, position := range aSnapshot.Body {
+		aSnake.cells.add(position, 1)
+	}
+	aSnake.direction = aSnapshot.Direction
+}
 
-	// Doesn't remove the tail
-	// Inserts the head at new position
-	aSnake.body = append(aSnake.body, newPosition)
-	return nil
+// Clone returns a copy of the snake, moving one doesn't move the other
+func (aSnake *snake) Clone() Snaker {
+	return &snake{
+		ring:      append([]common.Position(nil), aSnake.ring...),
+		first:     aSnake.first,
+		size:      aSnake.size,
+		cells:     aSnake.cells.clone(),
+		direction: aSnake.direction,
+	}
+}
+
+// body returns a copy of the parts from the tail to the head, nil when empty
+func (aSnake *snake) body() (body []common.Position) {
+	if aSnake.size == 0 {
+		return nil
+	}
+	body = make([]common.Position, aSnake.size)
+	for i := range body {
+		body[i] = aSnake.part(i)
+	}
+
+	return body
+}
+
+// part returns the part i of the body, the tail is 0
+func (aSnake *snake) part(i int) common.Position {
+	return aSnake.ring[aSnake.index(i)]
+}
+
+// index returns where the part i of the body is in ring
+func (aSnake *snake) index(i int) int {
+	i += aSnake.first
+	if i >= len(aSnake.ring) {
+		i -= len(aSnake.ring)
+	}
+
+	return i
+}
+
+// grow doubles the capacity of ring, the tail goes back to its start
+func (aSnake *snake) grow() {
+	capacity := 2 * len(aSnake.ring)
+	if capacity < 8 {
+		capacity = 8
+	}
+	ring := make([]common.Position, capacity)
+	for i := 0; i < aSnake.size; i++ {
+		ring[i] = aSnake.part(i)
+	}
+	aSnake.ring = ring
+	aSnake.first = 0
+}
+
+// occupancy counts the parts of a snake on each cell of a dense grid.
+// The grid covers the cells the snake has been on, it is extended
+// when the snake goes beyond them
+type occupancy struct {
+	origin common.Position
+	width  int
+	height int
+	counts []int32
+}
+
+func (anOccupancy *occupancy) count(position common.Position) int32 {
+	if !anOccupancy.covers(position) {
+		return 0
+	}
+
+	return anOccupancy.counts[anOccupancy.offset(position)]
+}
+
+// add adds delta to the count of position
+func (anOccupancy *occupancy) add(position common.Position, delta int32) {
+	if !anOccupancy.covers(position) {
+		anOccupancy.extend(position)
+	}
+	anOccupancy.counts[anOccupancy.offset(position)] += delta
+}
+
+func (anOccupancy *occupancy) clone() occupancy {
+	aClone := *anOccupancy
+	aClone.counts = append([]int32(nil), anOccupancy.counts...)
+	return aClone
+}
+
+func (anOccupancy *occupancy) clear() {
+	for i := range anOccupancy.counts {
+		anOccupancy.counts[i] = 0
+	}
+}
+
+func (anOccupancy *occupancy) covers(position common.Position) bool {
+	x := position.X - anOccupancy.origin.X
+	y := position.Y - anOccupancy.origin.Y
+	return x >= 0 && x < anOccupancy.width && y >= 0 && y < anOccupancy.height
+}
+
+func (anOccupancy *occupancy) offset(position common.Position) int {
+	return (position.Y-anOccupancy.origin.Y)*anOccupancy.width + position.X - anOccupancy.origin.X
+}
+
+// extend makes the grid cover position. It grows by at least its size on
+// the side of position, so it is reallocated a logarithmic number of times
+func (anOccupancy *occupancy) extend(position common.Position) {
+	minX, minY := anOccupancy.origin.X, anOccupancy.origin.Y
+	maxX, maxY := minX+anOccupancy.width, minY+anOccupancy.height
+	if anOccupancy.width == 0 {
+		minX, minY, maxX, maxY = position.X, position.Y, position.X+1, position.Y+1
+	}
+	if position.X < minX {
+		minX = position.X - anOccupancy.width
+	}
+	if position.X >= maxX {
+		maxX = position.X + 1 + anOccupancy.width
+	}
+	if position.Y < minY {
+		minY = position.Y - anOccupancy.height
+	}
+	if position.Y >= maxY {
+		maxY = position.Y + 1 + anOccupancy.height
+	}
+
+	extended := occupancy{
+		origin: common.Position{X: minX, Y: minY},
+		width:  maxX - minX,
+		height: maxY - minY,
+	}
+	extended.counts = make([]int32, extended.width*extended.height)
+	for y := 0; y < anOccupancy.height; y++ {
+		row := common.Position{X: anOccupancy.origin.X, Y: anOccupancy.origin.Y + y}
+		copy(extended.counts[extended.offset(row):], anOccupancy.counts[y*anOccupancy.width:(y+1)*anOccupancy.width])
+	}
+	*anOccupancy = extended
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/snapshot"
 	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
 
 	"github.com/stretchr/testify/require"
@@ -64,10 +65,7 @@ func TestSnake_Size(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			gotSize, err := aSnake.Size()
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
@@ -110,10 +108,7 @@ func TestSnake_SetDirection(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			aSnake.SetDirection(tt.args.direction)
 			gotDirection := aSnake.direction
 			require.Equal(t, tt.wantDirection, gotDirection)
@@ -181,10 +176,7 @@ func TestSnake_Position(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			gotPosition, err := aSnake.Position()
 			gotErr := err != nil
 			require.Equal(t, tt.wantErr, gotErr)
@@ -295,10 +287,7 @@ func TestSnake_GetNextMove(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			gotNextPosition, err := aSnake.NextMove()
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
@@ -421,10 +410,7 @@ func TestSnake_MoveTo(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			gotTheTail, err := aSnake.MoveTo(tt.args.newPosition)
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
@@ -557,10 +543,7 @@ func TestSnake_GrowTo(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			err := aSnake.GrowTo(tt.args.newPosition)
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
@@ -576,6 +559,63 @@ func TestSnake_GrowTo(t *testing.T) {
 	}
 }
 
+func TestSnake_Shrink(t *testing.T) {
+	type args struct {
+		segments int
+	}
+	tests := []struct {
+		name        string
+		body        []common.Position
+		args        args
+		wantRemoved []common.Position
+		wantBody    []common.Position
+		wantErr     bool
+	}{
+		{
+			name:    "TestEmptyBody",
+			args:    args{segments: 1},
+			wantErr: true,
+		},
+		{
+			name:     "TestNothing",
+			body:     []common.Position{testdata.Position0_0, testdata.Position0_1},
+			args:     args{segments: 0},
+			wantBody: []common.Position{testdata.Position0_0, testdata.Position0_1},
+		},
+		{
+			name:        "TestOneSegment",
+			body:        []common.Position{testdata.Position0_0, testdata.Position0_1, testdata.Position1_1},
+			args:        args{segments: 1},
+			wantRemoved: []common.Position{testdata.Position0_0},
+			wantBody:    []common.Position{testdata.Position0_1, testdata.Position1_1},
+		},
+		{
+			name:        "TestHeadKept",
+			body:        []common.Position{testdata.Position0_0, testdata.Position0_1, testdata.Position1_1},
+			args:        args{segments: 5},
+			wantRemoved: []common.Position{testdata.Position0_0, testdata.Position0_1},
+			wantBody:    []common.Position{testdata.Position1_1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSnake := newSnake(tt.body, common.Direction{})
+			gotRemoved, err := aSnake.Shrink(tt.args.segments)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr)
+			require.Equal(t, tt.wantRemoved, gotRemoved)
+			require.Equal(t, tt.wantBody, aSnake.body())
+		})
+	}
+}
+
+// newSnake returns a snake with the given body, from the tail to the head
+func newSnake(body []common.Position, direction common.Direction) *snake {
+	aSnake := new(snake)
+	aSnake.Restore(snapshot.Snake{Body: body, Direction: direction})
+	return aSnake
+}
+
 func TestNew(t *testing.T) {
 	var wantType *snake
 	var got = New()
@@ -642,10 +682,7 @@ func Test_snake_Tail(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			aSnake := &snake{
-				body:      tt.fields.body,
-				direction: tt.fields.direction,
-			}
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
 			gotTail, err := aSnake.Tail()
 			gotErr := (err != nil)
 			require.Equal(t, tt.wantErr, gotErr)
@@ -656,3 +693,147 @@ func Test_snake_Tail(t *testing.T) {
 		})
 	}
 }
+
+func TestSnake_SnapshotRestore(t *testing.T) {
+	type fields struct {
+		body      []common.Position
+		direction common.Direction
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		wantSnapshot snapshot.Snake
+	}{
+		{
+			name: "TestEmptyBody",
+			fields: fields{
+				body:      nil,
+				direction: testdata.Direction0_0,
+			},
+			wantSnapshot: snapshot.Snake{
+				Direction: testdata.Direction0_0,
+			},
+		},
+		{
+			name: "TestBodyTwo",
+			fields: fields{
+				body: []common.Position{
+					testdata.Position1_1,
+					testdata.Position1_2,
+				},
+				direction: testdata.DirectionMinus1_0,
+			},
+			wantSnapshot: snapshot.Snake{
+				Body: []common.Position{
+					testdata.Position1_1,
+					testdata.Position1_2,
+				},
+				Direction: testdata.DirectionMinus1_0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aSnake := newSnake(tt.fields.body, tt.fields.direction)
+			gotSnapshot := aSnake.Snapshot()
+			require.Equal(t, tt.wantSnapshot, gotSnapshot)
+
+			// The snapshot doesn't share the body of the snake
+			_, err := aSnake.MoveTo(testdata.Position4_3)
+			if len(tt.fields.body) > 0 {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantSnapshot, gotSnapshot)
+
+			restored := New()
+			restored.Restore(gotSnapshot)
+			require.Equal(t, tt.wantSnapshot, restored.Snapshot())
+		})
+	}
+}
+
+func TestSnake_Clone(t *testing.T) {
+	aSnake := newSnake([]common.Position{testdata.Position1_1, testdata.Position1_2}, testdata.Direction1_0)
+	want := aSnake.Snapshot()
+	aClone := aSnake.Clone()
+	require.Equal(t, want, aClone.Snapshot())
+
+	// Moving the clone doesn't move the snake
+	require.NoError(t, aClone.GrowTo(common.Position{X: 1, Y: 3}))
+	aClone.SetDirection(testdata.DirectionMinus1_0)
+	require.Equal(t, want, aSnake.Snapshot())
+	require.False(t, aSnake.Occupies(common.Position{X: 1, Y: 3}))
+	require.True(t, aClone.Occupies(common.Position{X: 1, Y: 3}))
+}
+
+// boardPosition returns where a snake scanning a 100x100 board row by row is after round rounds
+func boardPosition(round int) common.Position {
+	return common.Position{X: round % 100, Y: round / 100 % 100}
+}
+
+// sliceSnake moves its body the way snake did before the ring buffer
+type sliceSnake struct {
+	body []common.Position
+}
+
+func (aSnake *sliceSnake) moveTo(newPosition common.Position) common.Position {
+	theTail := aSnake.body[0]
+	aSnake.body = aSnake.body[1:]
+	aSnake.body = append(aSnake.body, newPosition)
+	return theTail
+}
+
+func BenchmarkSnake_MoveTo(b *testing.B) {
+	const length = 100
+	b.Run("Ring", func(b *testing.B) {
+		aSnake := new(snake)
+		for i := 0; i < length; i++ {
+			require.NoError(b, aSnake.GrowTo(boardPosition(i)))
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := aSnake.MoveTo(boardPosition(length + i)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Slice", func(b *testing.B) {
+		aSnake := new(sliceSnake)
+		for i := 0; i < length; i++ {
+			aSnake.body = append(aSnake.body, boardPosition(i))
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			aSnake.moveTo(boardPosition(length + i))
+		}
+	})
+}
+
+func BenchmarkSnake_MillionRounds(b *testing.B) {
+	// Every op is a game of a million rounds: the snake grows every
+	// thousand rounds and checks the cell ahead and its ends every round
+	const rounds = 1000000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		aSnake := New()
+		require.NoError(b, aSnake.GrowTo(boardPosition(0)))
+		for round := 1; round < rounds; round++ {
+			next := boardPosition(round)
+			if aSnake.Occupies(next) {
+				b.Fatal("the snake bit itself")
+			}
+			if _, err := aSnake.Tail(); err != nil {
+				b.Fatal(err)
+			}
+			if round%1000 == 0 {
+				require.NoError(b, aSnake.GrowTo(next))
+				continue
+			}
+			if _, err := aSnake.MoveTo(next); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
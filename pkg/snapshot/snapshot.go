@@ -0,0 +1,404 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+)
+
+// Version is the version of the snapshot encodings, a snapshot of
+// another version is rejected. It goes up with every change of the layout:
+//
+//	1 the board, its snake and its candy
+//	2 the queued inputs
+//	3 several candies and the rounds before they respawn
+//	4 the candy kinds, their lifetimes and the speed rounds
+//	5 several snakes, their scores and inputs, and their deaths
+//	6 the outcome and the candies eaten
+//	7 the result replacing the outcome
+//	8 the pause
+const Version = 8
+
+// magic starts every binary snapshot
+const magic = "GSS"
+
+// ErrUnsupported is a custom error thrown when a snapshot can't be decoded
+var ErrUnsupported = errors.New("unsupported snapshot")
+
+// Game is the saved state of a game.
+// It encodes to JSON with encoding/json and to a compact binary
+// form with MarshalBinary
+type Game struct {
+	Version        int                `json:"version"`
+	GameInProgress bool               `json:"gameInProgress"`
+	Round          int                `json:"round"`
+	Score          int                `json:"score"`
+	HighScore      int                `json:"highScore"`
+	Dirty          bool               `json:"dirty"`
+	Inputs         []common.Direction `json:"inputs,omitempty"`
+	PendingCandies []int              `json:"pendingCandies,omitempty"`
+	SpeedRounds    int                `json:"speedRounds,omitempty"`
+	Players        []Player           `json:"players,omitempty"`
+	Deaths         []Death            `json:"deaths,omitempty"`
+	Result         *common.GameResult `json:"result,omitempty"`
+	CandiesEaten   int                `json:"candiesEaten,omitempty"`
+	Paused         bool               `json:"paused,omitempty"`
+	Board          Board              `json:"board"`
+}
+
+// Player is the saved state of a snake other than the snake 0,
+// Start is where it starts on a new board
+type Player struct {
+	ID     common.SnakeID     `json:"id"`
+	Start  Snake              `json:"start"`
+	Score  int                `json:"score"`
+	Inputs []common.Direction `json:"inputs,omitempty"`
+}
+
+// Death tells why a snake of a game with several snakes died
+type Death struct {
+	ID     common.SnakeID        `json:"id"`
+	Reason common.GameOverReason `json:"reason"`
+}
+
+// Board is the saved state of a game board.
+// Cells holds one string per row, RandomState is only set when the
+// random source can be saved
+type Board struct {
+	Size        common.Size `json:"size"`
+	Cells       []string    `json:"cells"`
+	Snake       Snake       `json:"snake"`
+	Snakes      []Snake     `json:"snakes,omitempty"`
+	Candies     []Candy     `json:"candies"`
+	RandomState *uint64     `json:"randomState,omitempty"`
+}
+
+// Snake is the saved state of a snake, the body starts with the tail.
+// Board.Snake is the snake 0, Board.Snakes holds the other snakes
+type Snake struct {
+	ID        common.SnakeID    `json:"id,omitempty"`
+	Body      []common.Position `json:"body"`
+	Direction common.Direction  `json:"direction"`
+}
+
+// Candy is the saved state of a candy
+type Candy struct {
+	Alive    bool             `json:"alive"`
+	Position common.Position  `json:"position"`
+	Kind     common.CandyKind `json:"kind,omitempty"`
+	Lifetime int              `json:"lifetime,omitempty"`
+}
+
+// MarshalBinary encodes the snapshot in a compact binary form
+func (aSnapshot Game) MarshalBinary() (data []byte, err error) {
+	var buffer bytes.Buffer
+	buffer.WriteString(magic)
+	writer := binaryWriter{buffer: &buffer}
+	writer.int(int64(aSnapshot.Version))
+	writer.bool(aSnapshot.GameInProgress)
+	writer.int(int64(aSnapshot.Round))
+	writer.int(int64(aSnapshot.Score))
+	writer.int(int64(aSnapshot.HighScore))
+	writer.bool(aSnapshot.Dirty)
+	writer.int(int64(len(aSnapshot.Inputs)))
+	for _, direction := range aSnapshot.Inputs {
+		writer.direction(direction)
+	}
+	writer.int(int64(len(aSnapshot.PendingCandies)))
+	for _, round := range aSnapshot.PendingCandies {
+		writer.int(int64(round))
+	}
+	writer.int(int64(aSnapshot.SpeedRounds))
+	writer.int(int64(len(aSnapshot.Players)))
+	for _, aPlayer := range aSnapshot.Players {
+		writer.int(int64(aPlayer.ID))
+		writer.snake(aPlayer.Start)
+		writer.int(int64(aPlayer.Score))
+		writer.int(int64(len(aPlayer.Inputs)))
+		for _, direction := range aPlayer.Inputs {
+			writer.direction(direction)
+		}
+	}
+	writer.int(int64(len(aSnapshot.Deaths)))
+	for _, aDeath := range aSnapshot.Deaths {
+		writer.int(int64(aDeath.ID))
+		writer.int(int64(aDeath.Reason))
+	}
+	writer.bool(aSnapshot.Result != nil)
+	if aResult := aSnapshot.Result; aResult != nil {
+		writer.int(int64(aResult.Outcome))
+		writer.int(int64(aResult.Reason))
+		writer.int(int64(aResult.Round))
+		writer.int(int64(aResult.Length))
+		writer.int(int64(aResult.Score))
+		writer.position(aResult.Killer)
+		writer.int(int64(aResult.Snake))
+	}
+	writer.int(int64(aSnapshot.CandiesEaten))
+	writer.bool(aSnapshot.Paused)
+
+	board := aSnapshot.Board
+	writer.int(int64(board.Size.Width))
+	writer.int(int64(board.Size.Height))
+	writer.int(int64(len(board.Cells)))
+	for _, row := range board.Cells {
+		writer.string(row)
+	}
+	writer.snake(board.Snake)
+	writer.int(int64(len(board.Snakes)))
+	for _, aSnake := range board.Snakes {
+		writer.snake(aSnake)
+	}
+	writer.int(int64(len(board.Candies)))
+	for _, aCandy := range board.Candies {
+		writer.bool(aCandy.Alive)
+		writer.position(aCandy.Position)
+		writer.int(int64(aCandy.Kind))
+		writer.int(int64(aCandy.Lifetime))
+	}
+	writer.bool(board.RandomState != nil)
+	if board.RandomState != nil {
+		writer.uint(*board.RandomState)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a snapshot encoded by MarshalBinary
+func (aSnapshot *Game) UnmarshalBinary(data []byte) (err error) {
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return ErrUnsupported
+	}
+	reader := binaryReader{reader: bytes.NewReader(data[len(magic):])}
+	var decoded Game
+	decoded.Version = reader.int()
+	if reader.err == nil && decoded.Version != Version {
+		return fmt.Errorf("%w: version %d", ErrUnsupported, decoded.Version)
+	}
+	decoded.GameInProgress = reader.bool()
+	decoded.Round = reader.int()
+	decoded.Score = reader.int()
+	decoded.HighScore = reader.int()
+	decoded.Dirty = reader.bool()
+	if length := reader.length(); length > 0 {
+		decoded.Inputs = make([]common.Direction, length)
+		for i := range decoded.Inputs {
+			decoded.Inputs[i] = reader.direction()
+		}
+	}
+	if length := reader.length(); length > 0 {
+		decoded.PendingCandies = make([]int, length)
+		for i := range decoded.PendingCandies {
+			decoded.PendingCandies[i] = reader.int()
+		}
+	}
+	decoded.SpeedRounds = reader.int()
+	if length := reader.length(); length > 0 {
+		decoded.Players = make([]Player, length)
+		for i := range decoded.Players {
+			aPlayer := &decoded.Players[i]
+			aPlayer.ID = common.SnakeID(reader.int())
+			aPlayer.Start = reader.snake()
+			aPlayer.Score = reader.int()
+			if length := reader.length(); length > 0 {
+				aPlayer.Inputs = make([]common.Direction, length)
+				for j := range aPlayer.Inputs {
+					aPlayer.Inputs[j] = reader.direction()
+				}
+			}
+		}
+	}
+	if length := reader.length(); length > 0 {
+		decoded.Deaths = make([]Death, length)
+		for i := range decoded.Deaths {
+			decoded.Deaths[i] = Death{
+				ID:     common.SnakeID(reader.int()),
+				Reason: common.GameOverReason(reader.int()),
+			}
+		}
+	}
+	if reader.bool() {
+		decoded.Result = &common.GameResult{
+			Outcome: common.Outcome(reader.int()),
+			Reason:  common.GameOverReason(reader.int()),
+			Round:   reader.int(),
+			Length:  reader.int(),
+			Score:   reader.int(),
+			Killer:  reader.position(),
+			Snake:   common.SnakeID(reader.int()),
+		}
+	}
+	decoded.CandiesEaten = reader.int()
+	decoded.Paused = reader.bool()
+
+	board := &decoded.Board
+	board.Size.Width = reader.int()
+	board.Size.Height = reader.int()
+	board.Cells = make([]string, reader.length())
+	for i := range board.Cells {
+		board.Cells[i] = reader.string()
+	}
+	board.Snake = reader.snake()
+	if length := reader.length(); length > 0 {
+		board.Snakes = make([]Snake, length)
+		for i := range board.Snakes {
+			board.Snakes[i] = reader.snake()
+		}
+	}
+	if length := reader.length(); length > 0 {
+		board.Candies = make([]Candy, length)
+		for i := range board.Candies {
+			board.Candies[i] = Candy{
+				Alive:    reader.bool(),
+				Position: reader.position(),
+				Kind:     common.CandyKind(reader.int()),
+				Lifetime: reader.int(),
+			}
+		}
+	}
+	if reader.bool() {
+		state := reader.uint()
+		board.RandomState = &state
+	}
+
+	if reader.err != nil {
+		return fmt.Errorf("%w: %v", ErrUnsupported, reader.err)
+	}
+	*aSnapshot = decoded
+	return nil
+}
+
+// binaryWriter writes varints to a buffer
+type binaryWriter struct {
+	buffer *bytes.Buffer
+}
+
+func (aWriter binaryWriter) int(value int64) {
+	var scratch [binary.MaxVarintLen64]byte
+	aWriter.buffer.Write(scratch[:binary.PutVarint(scratch[:], value)])
+}
+
+func (aWriter binaryWriter) uint(value uint64) {
+	var scratch [binary.MaxVarintLen64]byte
+	aWriter.buffer.Write(scratch[:binary.PutUvarint(scratch[:], value)])
+}
+
+func (aWriter binaryWriter) bool(value bool) {
+	if value {
+		aWriter.buffer.WriteByte(1)
+		return
+	}
+	aWriter.buffer.WriteByte(0)
+}
+
+func (aWriter binaryWriter) string(value string) {
+	aWriter.uint(uint64(len(value)))
+	aWriter.buffer.WriteString(value)
+}
+
+func (aWriter binaryWriter) position(position common.Position) {
+	aWriter.int(int64(position.X))
+	aWriter.int(int64(position.Y))
+}
+
+func (aWriter binaryWriter) direction(direction common.Direction) {
+	aWriter.int(int64(direction.DX))
+	aWriter.int(int64(direction.DY))
+}
+
+func (aWriter binaryWriter) snake(aSnake Snake) {
+	aWriter.int(int64(aSnake.ID))
+	aWriter.int(int64(len(aSnake.Body)))
+	for _, position := range aSnake.Body {
+		aWriter.position(position)
+	}
+	aWriter.direction(aSnake.Direction)
+}
+
+// binaryReader reads what binaryWriter wrote, the first error stops the reading
+type binaryReader struct {
+	reader *bytes.Reader
+	err    error
+}
+
+func (aReader *binaryReader) int() int {
+	if aReader.err != nil {
+		return 0
+	}
+	value, err := binary.ReadVarint(aReader.reader)
+	aReader.err = err
+	return int(value)
+}
+
+func (aReader *binaryReader) uint() uint64 {
+	if aReader.err != nil {
+		return 0
+	}
+	value, err := binary.ReadUvarint(aReader.reader)
+	aReader.err = err
+	return value
+}
+
+// length reads a count, it can't exceed the remaining bytes
+func (aReader *binaryReader) length() int {
+	length := aReader.int()
+	if aReader.err == nil && (length < 0 || length > aReader.reader.Len()) {
+		aReader.err = io.ErrUnexpectedEOF
+	}
+	if aReader.err != nil {
+		return 0
+	}
+	return length
+}
+
+func (aReader *binaryReader) bool() bool {
+	if aReader.err != nil {
+		return false
+	}
+	value, err := aReader.reader.ReadByte()
+	aReader.err = err
+	return value == 1
+}
+
+func (aReader *binaryReader) string() string {
+	length := aReader.uint()
+	if aReader.err == nil && length > uint64(aReader.reader.Len()) {
+		aReader.err = io.ErrUnexpectedEOF
+	}
+	if aReader.err != nil {
+		return ""
+	}
+	value := make([]byte, length)
+	_, aReader.err = io.ReadFull(aReader.reader, value)
+	return string(value)
+}
+
+func (aReader *binaryReader) position() common.Position {
+	return common.Position{
+		X: aReader.int(),
+		Y: aReader.int(),
+	}
+}
+
+func (aReader *binaryReader) direction() common.Direction {
+	return common.Direction{
+		DX: aReader.int(),
+		DY: aReader.int(),
+	}
+}
+
+func (aReader *binaryReader) snake() (aSnake Snake) {
+	aSnake.ID = common.SnakeID(aReader.int())
+	if length := aReader.length(); length > 0 {
+		aSnake.Body = make([]common.Position, length)
+		for i := range aSnake.Body {
+			aSnake.Body[i] = aReader.position()
+		}
+	}
+	aSnake.Direction = aReader.direction()
+	return aSnake
+}
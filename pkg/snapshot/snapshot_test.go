@@ -0,0 +1,164 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/Amari-Mecheri/GoSnakeLogic/pkg/common"
+	"github.com/Amari-Mecheri/GoSnakeLogic/testdata"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGame_MarshalBinary(t *testing.T) {
+	state := uint64(1) << 63
+	tests := []struct {
+		name     string
+		snapshot Game
+	}{
+		{
+			name: "TestEmpty",
+			snapshot: Game{
+				Version: Version,
+				Board: Board{
+					Cells: []string{},
+				},
+			},
+		},
+		{
+			name: "TestFull",
+			snapshot: Game{
+				Version:        Version,
+				GameInProgress: true,
+				Round:          1234,
+				Score:          56,
+				HighScore:      78,
+				Dirty:          true,
+				Inputs: []common.Direction{
+					testdata.DirectionMinus1_0,
+					testdata.Direction0_0,
+				},
+				PendingCandies: []int{1240, 1245},
+				SpeedRounds:    7,
+				Players: []Player{
+					{
+						ID: 1,
+						Start: Snake{
+							Body:      []common.Position{testdata.Position2_2},
+							Direction: testdata.Direction0_0,
+						},
+						Score:  3,
+						Inputs: []common.Direction{testdata.DirectionMinus1_0},
+					},
+				},
+				Deaths: []Death{
+					{
+						ID:     1,
+						Reason: common.HeadOnCollision,
+					},
+				},
+				Result: &common.GameResult{
+					Outcome: common.Lost,
+					Reason:  common.HeadOnCollision,
+					Round:   12,
+					Length:  3,
+					Score:   3,
+					Killer:  testdata.Position1_2,
+					Snake:   1,
+				},
+				CandiesEaten: 4,
+				Paused:       true,
+				Board: Board{
+					Size:  testdata.Size3_3,
+					Cells: []string{"S* ", "S  ", "  $"},
+					Snake: Snake{
+						Body: []common.Position{
+							testdata.Position0_1,
+							testdata.Position0_0,
+						},
+						Direction: testdata.DirectionMinus1_0,
+					},
+					Snakes: []Snake{
+						{
+							ID:        1,
+							Body:      []common.Position{testdata.Position2_2},
+							Direction: testdata.Direction0_0,
+						},
+					},
+					Candies: []Candy{
+						{
+							Alive:    true,
+							Position: common.Position{X: 1, Y: 0},
+						},
+						{
+							Alive:    true,
+							Position: testdata.Position2_2,
+							Kind:     common.GoldenCandy,
+							Lifetime: 12,
+						},
+					},
+					RandomState: &state,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.snapshot.MarshalBinary()
+			require.NoError(t, err)
+			var gotSnapshot Game
+			require.NoError(t, gotSnapshot.UnmarshalBinary(data))
+			require.Equal(t, tt.snapshot, gotSnapshot)
+		})
+	}
+}
+
+func TestGame_UnmarshalBinary(t *testing.T) {
+	valid, err := Game{Version: Version, Board: Board{Cells: []string{"ab"}}}.MarshalBinary()
+	require.NoError(t, err)
+	future, err := Game{Version: Version + 1}.MarshalBinary()
+	require.NoError(t, err)
+	old, err := Game{Version: 1}.MarshalBinary()
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name:    "TestValid",
+			data:    valid,
+			wantErr: false,
+		},
+		{
+			name:    "TestNoMagic",
+			data:    []byte("XYZ"),
+			wantErr: true,
+		},
+		{
+			name:    "TestFutureVersion",
+			data:    future,
+			wantErr: true,
+		},
+		{
+			name:    "TestOldVersion", // The layout changed since
+			data:    old,
+			wantErr: true,
+		},
+		{
+			name:    "TestTruncated",
+			data:    valid[:len(valid)-3],
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSnapshot Game
+			err := gotSnapshot.UnmarshalBinary(tt.data)
+			gotErr := (err != nil)
+			require.Equal(t, tt.wantErr, gotErr, err)
+			if gotErr {
+				require.ErrorIs(t, err, ErrUnsupported)
+			}
+		})
+	}
+}